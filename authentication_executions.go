@@ -0,0 +1,124 @@
+package keycloak
+
+import (
+	"sort"
+)
+
+// AuthenticationExecutionNode is an execution of a flow and, if it is a sub flow, the executions of the sub flow.
+type AuthenticationExecutionNode struct {
+	Execution  AuthenticationExecutionInfoRepresentation
+	Executions []AuthenticationExecutionNode
+}
+
+// AuthenticationExecutionTree turns the depth first list returned by GetAuthenticationExecutionForFlow into a
+// tree, the executions of each level being ordered by Index.
+func AuthenticationExecutionTree(executions []AuthenticationExecutionInfoRepresentation) []AuthenticationExecutionNode {
+	var i int
+	var nodes = executionNodes(executions, 0, &i)
+	for i < len(executions) {
+		// An execution deeper than expected without a parent, which Keycloak never returns.
+		nodes = append(nodes, executionNodes(executions, executionLevel(executions[i]), &i)...)
+	}
+	sortExecutionNodes(nodes)
+	return nodes
+}
+
+// executionNodes consumes from i the executions at level, along with the executions of their sub flows.
+func executionNodes(executions []AuthenticationExecutionInfoRepresentation, level int32, i *int) []AuthenticationExecutionNode {
+	var nodes = []AuthenticationExecutionNode{}
+	for *i < len(executions) && executionLevel(executions[*i]) == level {
+		var node = AuthenticationExecutionNode{Execution: executions[*i]}
+		*i++
+		if *i < len(executions) && executionLevel(executions[*i]) > level {
+			node.Executions = executionNodes(executions, executionLevel(executions[*i]), i)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func executionLevel(execution AuthenticationExecutionInfoRepresentation) int32 {
+	if execution.Level == nil {
+		return 0
+	}
+	return *execution.Level
+}
+
+func sortExecutionNodes(nodes []AuthenticationExecutionNode) {
+	var index = func(node AuthenticationExecutionNode) int32 {
+		if node.Execution.Index == nil {
+			return 0
+		}
+		return *node.Execution.Index
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return index(nodes[i]) < index(nodes[j]) })
+	for _, node := range nodes {
+		sortExecutionNodes(node.Executions)
+	}
+}
+
+// GetAuthenticationExecutionByProviderID returns the first execution of the flow or of its sub flows, depth first,
+// whose authenticator is providerID, e.g. "auth-otp-form". It fails with ErrNotFound if there is no such execution.
+func GetAuthenticationExecutionByProviderID(client KeycloakAdmin, accessToken string, realmName, flowAlias, providerID string) (AuthenticationExecutionInfoRepresentation, error) {
+	var executions, err = client.GetAuthenticationExecutionForFlow(accessToken, realmName, flowAlias)
+	if err != nil {
+		return AuthenticationExecutionInfoRepresentation{}, err
+	}
+	return findExecution(executions, byProviderID(providerID))
+}
+
+// GetAuthenticationExecutionByDisplayName returns the first execution of the flow or of its sub flows, depth
+// first, whose display name is displayName, e.g. "OTP Form" or the alias of a sub flow. It fails with ErrNotFound
+// if there is no such execution.
+func GetAuthenticationExecutionByDisplayName(client KeycloakAdmin, accessToken string, realmName, flowAlias, displayName string) (AuthenticationExecutionInfoRepresentation, error) {
+	var executions, err = client.GetAuthenticationExecutionForFlow(accessToken, realmName, flowAlias)
+	if err != nil {
+		return AuthenticationExecutionInfoRepresentation{}, err
+	}
+	return findExecution(executions, byDisplayName(displayName))
+}
+
+// SetAuthenticationExecutionRequirement sets the requirement of the execution of the flow or of its sub flows
+// whose authenticator, or else whose display name, is name:
+//
+//	err := keycloak.SetAuthenticationExecutionRequirement(admin, accessToken, "my-realm", "browser", "auth-otp-form", keycloak.RequirementRequired)
+//
+// It fails with ErrNotFound if there is no such execution.
+func SetAuthenticationExecutionRequirement(client KeycloakAdmin, accessToken string, realmName, flowAlias, name, requirement string) error {
+	var executions, err = client.GetAuthenticationExecutionForFlow(accessToken, realmName, flowAlias)
+	if err != nil {
+		return err
+	}
+	var execution AuthenticationExecutionInfoRepresentation
+	if execution, err = findExecution(executions, byProviderID(name)); err != nil {
+		if execution, err = findExecution(executions, byDisplayName(name)); err != nil {
+			return err
+		}
+	}
+	if execution.Requirement != nil && *execution.Requirement == requirement {
+		return nil
+	}
+	execution.Requirement = &requirement
+	return client.UpdateAuthenticationExecutionForFlow(accessToken, realmName, flowAlias, execution)
+}
+
+func findExecution(executions []AuthenticationExecutionInfoRepresentation, match func(AuthenticationExecutionInfoRepresentation) bool) (AuthenticationExecutionInfoRepresentation, error) {
+	for _, execution := range executions {
+		if match(execution) {
+			return execution, nil
+		}
+	}
+	return AuthenticationExecutionInfoRepresentation{}, newError(MsgErrNotFound, ExecutionNotFound, ErrNotFound)
+}
+
+func byProviderID(providerID string) func(AuthenticationExecutionInfoRepresentation) bool {
+	return func(execution AuthenticationExecutionInfoRepresentation) bool {
+		return execution.ProviderID != nil && *execution.ProviderID == providerID
+	}
+}
+
+func byDisplayName(displayName string) func(AuthenticationExecutionInfoRepresentation) bool {
+	return func(execution AuthenticationExecutionInfoRepresentation) bool {
+		return execution.DisplayName != nil && *execution.DisplayName == displayName
+	}
+}
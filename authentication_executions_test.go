@@ -0,0 +1,88 @@
+package keycloak
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAuthenticationExecutionForFlow(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/test/authentication/flows/browser/executions", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": "cookie", "providerId": "auth-cookie", "level": 0, "index": 0},
+			{"id": "forms", "displayName": "forms", "authenticationFlow": true, "level": 0, "index": 1},
+			{"id": "password", "providerId": "auth-username-password-form", "level": 1, "index": 0}
+		]`))
+	}))
+
+	var executions, err = client.GetAuthenticationExecutionForFlow("token", "test", "browser")
+	assert.Nil(t, err)
+	assert.Len(t, executions, 3)
+	assert.Equal(t, "auth-username-password-form", *executions[2].ProviderID)
+}
+
+func TestAuthenticationExecutionTree(t *testing.T) {
+	var execution = func(id string, level, index int32) AuthenticationExecutionInfoRepresentation {
+		return AuthenticationExecutionInfoRepresentation{ID: strPtr(id), Level: int32Ptr(level), Index: int32Ptr(index)}
+	}
+	var ids func(nodes []AuthenticationExecutionNode) []interface{}
+	ids = func(nodes []AuthenticationExecutionNode) []interface{} {
+		var res = []interface{}{}
+		for _, node := range nodes {
+			res = append(res, *node.Execution.ID)
+			if len(node.Executions) > 0 {
+				res = append(res, ids(node.Executions))
+			}
+		}
+		return res
+	}
+
+	var tree = AuthenticationExecutionTree([]AuthenticationExecutionInfoRepresentation{
+		execution("cookie", 0, 0),
+		execution("forms", 0, 2),
+		execution("password", 1, 0),
+		execution("conditional", 1, 1),
+		execution("otp", 2, 0),
+		execution("webauthn", 1, 2),
+		execution("kerberos", 0, 1),
+	})
+	assert.Equal(t, []interface{}{"cookie", "kerberos", "forms", []interface{}{"password", "conditional", []interface{}{"otp"}, "webauthn"}}, ids(tree))
+
+	tree = AuthenticationExecutionTree([]AuthenticationExecutionInfoRepresentation{execution("orphan", 1, 0), execution("cookie", 0, 0)})
+	assert.Equal(t, []interface{}{"orphan", "cookie"}, ids(tree))
+	assert.Empty(t, AuthenticationExecutionTree(nil))
+}
+
+func TestAuthenticationExecutionLookups(t *testing.T) {
+	var api = &testFlows{
+		flows: map[string][]*testExecution{
+			"browser": {{id: "cookie", provider: "auth-cookie", requirement: RequirementAlternative}, {id: "forms", subFlow: "forms", requirement: RequirementAlternative}},
+			"forms":   {{id: "otp", provider: "auth-otp-form", requirement: RequirementConditional}},
+		},
+		configs: map[string]AuthenticatorConfigRepresentation{},
+	}
+	var client = newTestClient(t, api)
+
+	var execution, err = GetAuthenticationExecutionByProviderID(client, "token", "test", "browser", "auth-otp-form")
+	assert.Nil(t, err)
+	assert.Equal(t, "otp", *execution.ID)
+	execution, err = GetAuthenticationExecutionByDisplayName(client, "token", "test", "browser", "forms")
+	assert.Nil(t, err)
+	assert.Equal(t, "forms", *execution.ID)
+	_, err = GetAuthenticationExecutionByProviderID(client, "token", "test", "browser", "auth-spnego")
+	assert.Equal(t, Error{Code: MsgErrNotFound, Detail: ExecutionNotFound, Err: ErrNotFound}, err)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	assert.Nil(t, SetAuthenticationExecutionRequirement(client, "token", "test", "browser", "auth-otp-form", RequirementRequired))
+	assert.Nil(t, SetAuthenticationExecutionRequirement(client, "token", "test", "browser", "forms", RequirementRequired))
+	assert.Nil(t, SetAuthenticationExecutionRequirement(client, "token", "test", "browser", "auth-cookie", RequirementAlternative))
+	assert.Equal(t, []string{"PUT /flows/browser/executions", "PUT /flows/browser/executions"}, api.requests)
+	assert.Equal(t, RequirementRequired, api.flows["forms"][0].requirement)
+	assert.Equal(t, RequirementRequired, api.flows["browser"][1].requirement)
+	err = SetAuthenticationExecutionRequirement(client, "token", "test", "browser", "auth-spnego", RequirementRequired)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
@@ -0,0 +1,383 @@
+package keycloak
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Requirements of the executions.
+const (
+	RequirementRequired    = "REQUIRED"
+	RequirementAlternative = "ALTERNATIVE"
+	RequirementConditional = "CONDITIONAL"
+	RequirementDisabled    = "DISABLED"
+)
+
+// Providers of the flows. The top level flows are basic or client flows, the sub flows basic or form flows.
+const (
+	FlowProviderBasic  = "basic-flow"
+	FlowProviderClient = "client-flow"
+	FlowProviderForm   = "form-flow"
+)
+
+// formFlowProvider is the authenticator of the form sub flows.
+const formFlowProvider = "registration-page-form"
+
+// FlowBinding is a slot of a realm which a top level flow is bound to.
+type FlowBinding string
+
+// Flow bindings of the realms.
+const (
+	FlowBindingBrowser              FlowBinding = "browserFlow"
+	FlowBindingRegistration         FlowBinding = "registrationFlow"
+	FlowBindingDirectGrant          FlowBinding = "directGrantFlow"
+	FlowBindingResetCredentials     FlowBinding = "resetCredentialsFlow"
+	FlowBindingClientAuthentication FlowBinding = "clientAuthenticationFlow"
+	FlowBindingDockerAuthentication FlowBinding = "dockerAuthenticationFlow"
+)
+
+// AuthenticationFlow describes a flow and its executions, in order, for SyncAuthenticationFlow:
+//
+//	var flow = keycloak.NewAuthenticationFlow("browser with otp", "Browser flow with conditional OTP").
+//		Execution("auth-cookie", keycloak.RequirementAlternative).
+//		SubFlow(keycloak.NewAuthenticationFlow("browser with otp forms", "").
+//			Execution("auth-username-password-form", keycloak.RequirementRequired).
+//			SubFlow(keycloak.NewAuthenticationFlow("browser with otp conditional", "").
+//				Execution("conditional-user-configured", keycloak.RequirementRequired).
+//				Execution("auth-otp-form", keycloak.RequirementRequired), keycloak.RequirementConditional),
+//			keycloak.RequirementAlternative)
+type AuthenticationFlow struct {
+	// Alias identifies the flow. It must be unique within the realm, sub flows included.
+	Alias       string
+	Description string
+	// ProviderID is one of the FlowProvider constants, FlowProviderBasic if empty.
+	ProviderID string
+	Executions []FlowExecution
+}
+
+// FlowExecution is an authenticator or a sub flow of a flow. An empty Requirement leaves that of an existing
+// execution as is.
+type FlowExecution struct {
+	// Authenticator is the provider of an authenticator, e.g. "auth-otp-form".
+	Authenticator string
+	// SubFlow is the flow of a sub flow, in which case Authenticator is empty.
+	SubFlow     *AuthenticationFlow
+	Requirement string
+	// Config is the configuration of the authenticator, if any.
+	Config *FlowAuthenticatorConfig
+}
+
+// FlowAuthenticatorConfig is the configuration of an authenticator.
+type FlowAuthenticatorConfig struct {
+	Alias  string
+	Config map[string]string
+}
+
+// NewAuthenticationFlow returns a basic flow, whose executions are added with the methods below.
+func NewAuthenticationFlow(alias string, description string) *AuthenticationFlow {
+	return &AuthenticationFlow{Alias: alias, Description: description, ProviderID: FlowProviderBasic}
+}
+
+// NewFormFlow returns a form flow, such as the registration form, whose executions are form actions.
+func NewFormFlow(alias string, description string) *AuthenticationFlow {
+	return &AuthenticationFlow{Alias: alias, Description: description, ProviderID: FlowProviderForm}
+}
+
+// Execution adds an authenticator to the flow.
+func (f *AuthenticationFlow) Execution(authenticator string, requirement string) *AuthenticationFlow {
+	f.Executions = append(f.Executions, FlowExecution{Authenticator: authenticator, Requirement: requirement})
+	return f
+}
+
+// ConfiguredExecution adds an authenticator and its configuration to the flow.
+func (f *AuthenticationFlow) ConfiguredExecution(authenticator string, requirement string, config FlowAuthenticatorConfig) *AuthenticationFlow {
+	f.Executions = append(f.Executions, FlowExecution{Authenticator: authenticator, Requirement: requirement, Config: &config})
+	return f
+}
+
+// SubFlow adds a sub flow to the flow.
+func (f *AuthenticationFlow) SubFlow(flow *AuthenticationFlow, requirement string) *AuthenticationFlow {
+	f.Executions = append(f.Executions, FlowExecution{SubFlow: flow, Requirement: requirement})
+	return f
+}
+
+// SyncAuthenticationFlow creates the top level flow, or converges the existing one towards it: it updates the
+// descriptions, adds the missing executions and sub flows, deletes the others, updates the requirements and the
+// configurations, and reorders the executions by raising their priority. The authenticators are matched by
+// provider and the sub flows by alias, a sub flow whose provider changed being recreated. The built in flows
+// cannot be synchronised, and neither can the provider of an existing top level flow change.
+func SyncAuthenticationFlow(client KeycloakAdmin, accessToken string, realmName string, flow AuthenticationFlow) error {
+	if flow.Alias == "" {
+		return newError(MsgErrMissingParam, FlowAlias, nil)
+	}
+	var flows, err = client.GetAuthenticationFlows(accessToken, realmName)
+	if err != nil {
+		return err
+	}
+	var existing *AuthenticationFlowRepresentation
+	for i := range flows {
+		if flows[i].Alias != nil && *flows[i].Alias == flow.Alias {
+			existing = &flows[i]
+		}
+	}
+
+	var providerID = orDefault(flow.ProviderID, FlowProviderBasic)
+	switch {
+	case existing == nil:
+		var topLevel, builtIn = true, false
+		err = client.CreateAuthenticationFlow(accessToken, realmName, AuthenticationFlowRepresentation{
+			Alias:       &flow.Alias,
+			Description: &flow.Description,
+			ProviderID:  &providerID,
+			TopLevel:    &topLevel,
+			BuiltIn:     &builtIn,
+		})
+		if errors.Is(err, ErrConflict) {
+			// The alias is that of a sub flow, which GetAuthenticationFlows does not list.
+			return newError(MsgErrExistingValue, FlowAlias, fmt.Errorf("flow %s: %w", flow.Alias, err))
+		}
+	case existing.BuiltIn != nil && *existing.BuiltIn:
+		return newError(MsgErrReadOnly, BuiltInFlow, nil)
+	case existing.ProviderID != nil && *existing.ProviderID != providerID:
+		return newError(MsgErrReadOnly, FlowProvider, nil)
+	default:
+		err = updateFlowDescription(client, accessToken, realmName, *existing, flow.Description)
+	}
+	if err != nil {
+		return err
+	}
+	return syncExecutions(client, accessToken, realmName, flow)
+}
+
+// updateFlowDescription updates the description of the existing flow, if it changed.
+func updateFlowDescription(client KeycloakAdmin, accessToken string, realmName string, existing AuthenticationFlowRepresentation, description string) error {
+	if existing.Description != nil && *existing.Description == description || existing.Description == nil && description == "" {
+		return nil
+	}
+	existing.Description, existing.AuthenticationExecutions = &description, nil
+	return client.UpdateAuthenticationFlow(accessToken, realmName, *existing.ID, existing)
+}
+
+// syncExecutions converges the executions of the existing flow, then those of its sub flows.
+func syncExecutions(client KeycloakAdmin, accessToken string, realmName string, flow AuthenticationFlow) error {
+	var current, err = flowChildren(client, accessToken, realmName, flow.Alias)
+	if err != nil {
+		return err
+	}
+	var matched = matchExecutions(flow.Executions, current)
+	if err = syncSubFlows(client, accessToken, realmName, flow.Executions, matched); err != nil {
+		return err
+	}
+
+	var changed bool
+	for _, execution := range current {
+		if !containsExecution(matched, execution) {
+			if err = client.DeleteAuthenticationExecution(accessToken, realmName, *execution.ID); err != nil {
+				return err
+			}
+			changed = true
+		}
+	}
+	for i, execution := range flow.Executions {
+		if matched[i] != nil {
+			continue
+		}
+		if execution.SubFlow != nil {
+			var sub = execution.SubFlow
+			var flowType, provider = FlowProviderBasic, ""
+			if sub.ProviderID == FlowProviderForm {
+				flowType, provider = FlowProviderForm, formFlowProvider
+			}
+			_, err = client.CreateFlowWithExecutionForExistingFlow(accessToken, realmName, flow.Alias, sub.Alias, flowType, provider, sub.Description)
+			switch {
+			case errors.Is(err, ErrConflict):
+				return newError(MsgErrExistingValue, FlowAlias, fmt.Errorf("sub flow %s of flow %s: %w", sub.Alias, flow.Alias, err))
+			case err != nil:
+				return newError(MsgErrCannotCreate, AuthenticationExecutions, fmt.Errorf("sub flow %s of flow %s: %w", sub.Alias, flow.Alias, err))
+			}
+		} else {
+			_, err = client.CreateAuthenticationExecutionForFlow(accessToken, realmName, flow.Alias, execution.Authenticator)
+			if err != nil {
+				return newError(MsgErrCannotCreate, AuthenticationExecutions, fmt.Errorf("authenticator %s of flow %s: %w", execution.Authenticator, flow.Alias, err))
+			}
+		}
+		changed = true
+	}
+	if changed {
+		if current, err = flowChildren(client, accessToken, realmName, flow.Alias); err != nil {
+			return err
+		}
+		matched = matchExecutions(flow.Executions, current)
+	}
+
+	for i, execution := range flow.Executions {
+		if matched[i] == nil {
+			return newError(MsgErrCannotObtain, AuthenticationExecutions, nil)
+		}
+		if err = syncExecution(client, accessToken, realmName, flow.Alias, execution, *matched[i]); err != nil {
+			return err
+		}
+	}
+	if err = reorderExecutions(client, accessToken, realmName, matched, current); err != nil {
+		return err
+	}
+	for _, execution := range flow.Executions {
+		if execution.SubFlow != nil {
+			if err = syncExecutions(client, accessToken, realmName, *execution.SubFlow); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncSubFlows updates the descriptions of the matched sub flows and unmatches those whose provider changed,
+// for them to be recreated.
+func syncSubFlows(client KeycloakAdmin, accessToken string, realmName string, desired []FlowExecution, matched []*AuthenticationExecutionInfoRepresentation) error {
+	for i, execution := range desired {
+		if execution.SubFlow == nil || matched[i] == nil || matched[i].FlowID == nil {
+			continue
+		}
+		var existing, err = client.GetAuthenticationFlow(accessToken, realmName, *matched[i].FlowID)
+		if err != nil {
+			return err
+		}
+		if existing.ProviderID != nil && *existing.ProviderID != orDefault(execution.SubFlow.ProviderID, FlowProviderBasic) {
+			matched[i] = nil
+			continue
+		}
+		if err = updateFlowDescription(client, accessToken, realmName, existing, execution.SubFlow.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flowChildren returns the executions at the first level of the flow, in order.
+func flowChildren(client KeycloakAdmin, accessToken string, realmName string, flowAlias string) ([]AuthenticationExecutionInfoRepresentation, error) {
+	var executions, err = client.GetAuthenticationExecutionForFlow(accessToken, realmName, flowAlias)
+	if err != nil {
+		return nil, err
+	}
+	var children = []AuthenticationExecutionInfoRepresentation{}
+	for _, node := range AuthenticationExecutionTree(executions) {
+		children = append(children, node.Execution)
+	}
+	return children, nil
+}
+
+// matchExecutions returns, for each desired execution, the current one it matches, if any.
+func matchExecutions(desired []FlowExecution, current []AuthenticationExecutionInfoRepresentation) []*AuthenticationExecutionInfoRepresentation {
+	var matched = make([]*AuthenticationExecutionInfoRepresentation, len(desired))
+	var used = make([]bool, len(current))
+	for i, execution := range desired {
+		for j := range current {
+			if !used[j] && execution.matches(current[j]) {
+				matched[i], used[j] = &current[j], true
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func (e FlowExecution) matches(execution AuthenticationExecutionInfoRepresentation) bool {
+	var isFlow = execution.AuthenticationFlow != nil && *execution.AuthenticationFlow
+	if e.SubFlow != nil {
+		return isFlow && execution.DisplayName != nil && *execution.DisplayName == e.SubFlow.Alias
+	}
+	return !isFlow && execution.ProviderID != nil && *execution.ProviderID == e.Authenticator
+}
+
+func containsExecution(executions []*AuthenticationExecutionInfoRepresentation, execution AuthenticationExecutionInfoRepresentation) bool {
+	for _, e := range executions {
+		if e != nil && *e.ID == *execution.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// syncExecution updates the requirement and the configuration of the current execution.
+func syncExecution(client KeycloakAdmin, accessToken string, realmName string, flowAlias string, desired FlowExecution, current AuthenticationExecutionInfoRepresentation) error {
+	if desired.Requirement != "" && (current.Requirement == nil || *current.Requirement != desired.Requirement) {
+		var update = current
+		update.Requirement = &desired.Requirement
+		if err := client.UpdateAuthenticationExecutionForFlow(accessToken, realmName, flowAlias, update); err != nil {
+			return err
+		}
+	}
+	if desired.Config == nil {
+		return nil
+	}
+
+	var values = map[string]interface{}{}
+	for key, value := range desired.Config.Config {
+		values[key] = value
+	}
+	var config = AuthenticatorConfigRepresentation{Alias: &desired.Config.Alias, Config: &values}
+	if current.AuthenticationConfig == nil || *current.AuthenticationConfig == "" {
+		return client.UpdateAuthenticationExecution(accessToken, realmName, *current.ID, config)
+	}
+	var existing, err = client.GetAuthenticatorConfig(accessToken, realmName, *current.AuthenticationConfig)
+	if err != nil {
+		return err
+	}
+	if existing.Alias != nil && *existing.Alias == desired.Config.Alias && existing.Config != nil && reflect.DeepEqual(*existing.Config, values) {
+		return nil
+	}
+	config.ID = current.AuthenticationConfig
+	return client.UpdateAuthenticatorConfig(accessToken, realmName, *current.AuthenticationConfig, config)
+}
+
+// reorderExecutions raises the priority of the executions until they are in the order of matched. Raising the
+// priority of an execution swaps it with the previous one.
+func reorderExecutions(client KeycloakAdmin, accessToken string, realmName string, matched []*AuthenticationExecutionInfoRepresentation, current []AuthenticationExecutionInfoRepresentation) error {
+	var order []string
+	for _, execution := range current {
+		order = append(order, *execution.ID)
+	}
+	for i, execution := range matched {
+		var position = indexOf(order, *execution.ID)
+		for ; position > i; position-- {
+			if err := client.RaiseExecutionPriority(accessToken, realmName, *execution.ID); err != nil {
+				return err
+			}
+			order[position-1], order[position] = order[position], order[position-1]
+		}
+	}
+	return nil
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// BindAuthenticationFlow binds the top level flow to a slot of the realm, e.g. FlowBindingBrowser to make it the
+// browser flow.
+func BindAuthenticationFlow(client KeycloakAdmin, accessToken string, realmName string, binding FlowBinding, flowAlias string) error {
+	var realm = RealmRepresentation{}
+	switch binding {
+	case FlowBindingBrowser:
+		realm.BrowserFlow = &flowAlias
+	case FlowBindingRegistration:
+		realm.RegistrationFlow = &flowAlias
+	case FlowBindingDirectGrant:
+		realm.DirectGrantFlow = &flowAlias
+	case FlowBindingResetCredentials:
+		realm.ResetCredentialsFlow = &flowAlias
+	case FlowBindingClientAuthentication:
+		realm.ClientAuthenticationFlow = &flowAlias
+	case FlowBindingDockerAuthentication:
+		realm.DockerAuthenticationFlow = &flowAlias
+	default:
+		return newError(MsgErrInvalidParam, InvalidFlowBinding, nil)
+	}
+	return client.UpdateRealm(accessToken, realmName, realm)
+}
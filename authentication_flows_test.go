@@ -0,0 +1,300 @@
+package keycloak
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testExecution struct {
+	id, provider, subFlow, requirement, config string
+}
+
+// testFlows is a minimal authentication management API, enough for SyncAuthenticationFlow.
+type testFlows struct {
+	flows    map[string][]*testExecution
+	configs  map[string]AuthenticatorConfigRepresentation
+	builtIn  map[string]bool
+	requests []string
+	lastID   int
+	// The providers and the descriptions of the flows, the flows being identified by their alias.
+	providers, descriptions map[string]string
+}
+
+func (f *testFlows) describe(alias, provider, description string) {
+	if f.providers == nil {
+		f.providers, f.descriptions = map[string]string{}, map[string]string{}
+	}
+	f.providers[alias], f.descriptions[alias] = provider, description
+}
+
+func (f *testFlows) flow(alias string) AuthenticationFlowRepresentation {
+	var topLevel = true
+	for _, executions := range f.flows {
+		for _, e := range executions {
+			topLevel = topLevel && e.subFlow != alias
+		}
+	}
+	return AuthenticationFlowRepresentation{
+		ID:          strPtr(alias),
+		Alias:       strPtr(alias),
+		ProviderID:  strPtr(orDefault(f.providers[alias], FlowProviderBasic)),
+		Description: strPtr(f.descriptions[alias]),
+		TopLevel:    &topLevel,
+		BuiltIn:     boolPtr(f.builtIn[alias]),
+	}
+}
+
+func (f *testFlows) newID() string {
+	f.lastID++
+	return fmt.Sprintf("id%d", f.lastID)
+}
+
+func (f *testFlows) find(id string) (string, int) {
+	for alias, executions := range f.flows {
+		for i, e := range executions {
+			if e.id == id {
+				return alias, i
+			}
+		}
+	}
+	return "", -1
+}
+
+func (f *testFlows) list(alias string, level int32) []AuthenticationExecutionInfoRepresentation {
+	var res []AuthenticationExecutionInfoRepresentation
+	for i, e := range f.flows[alias] {
+		var info = AuthenticationExecutionInfoRepresentation{ID: strPtr(e.id), Requirement: strPtr(e.requirement), Level: int32Ptr(level), Index: int32Ptr(int32(i))}
+		if e.subFlow != "" {
+			info.AuthenticationFlow, info.DisplayName, info.FlowID = boolPtr(true), strPtr(e.subFlow), strPtr(e.subFlow)
+		} else {
+			info.ProviderID, info.DisplayName = strPtr(e.provider), strPtr(e.provider)
+		}
+		if e.config != "" {
+			info.AuthenticationConfig = strPtr(e.config)
+		}
+		res = append(res, info)
+		if e.subFlow != "" {
+			res = append(res, f.list(e.subFlow, level+1)...)
+		}
+	}
+	return res
+}
+
+func (f *testFlows) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var path = strings.TrimPrefix(r.URL.Path, "/auth/admin/realms/test/authentication")
+	var parts = strings.Split(strings.TrimPrefix(path, "/"), "/")
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	var reply = func(v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	if r.Method != http.MethodGet {
+		f.requests = append(f.requests, r.Method+" "+path)
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "/flows":
+		var flows = []AuthenticationFlowRepresentation{}
+		for alias := range f.flows {
+			if flow := f.flow(alias); *flow.TopLevel {
+				flows = append(flows, flow)
+			}
+		}
+		reply(flows)
+	case r.Method == http.MethodPost && path == "/flows":
+		if _, ok := f.flows[body["alias"].(string)]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.flows[body["alias"].(string)] = nil
+		f.describe(body["alias"].(string), body["providerId"].(string), body["description"].(string))
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "flows":
+		reply(f.flow(parts[1]))
+	case r.Method == http.MethodPut && len(parts) == 2 && parts[0] == "flows":
+		f.describe(parts[1], f.providers[parts[1]], body["description"].(string))
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "executions":
+		reply(f.list(parts[1], 0))
+	case r.Method == http.MethodPut && len(parts) == 3 && parts[2] == "executions":
+		var alias, i = f.find(body["id"].(string))
+		f.flows[alias][i].requirement = body["requirement"].(string)
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[3] == "execution":
+		if body["provider"] == "auth-unknown" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.flows[parts[1]] = append(f.flows[parts[1]], &testExecution{id: f.newID(), provider: body["provider"].(string), requirement: RequirementDisabled})
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[3] == "flow":
+		if _, ok := f.flows[body["alias"].(string)]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.flows[parts[1]] = append(f.flows[parts[1]], &testExecution{id: f.newID(), subFlow: body["alias"].(string), requirement: RequirementDisabled})
+		f.flows[body["alias"].(string)] = nil
+		f.describe(body["alias"].(string), body["type"].(string), body["description"].(string))
+	case r.Method == http.MethodDelete && parts[0] == "executions":
+		var alias, i = f.find(parts[1])
+		if f.flows[alias][i].subFlow != "" {
+			delete(f.flows, f.flows[alias][i].subFlow)
+		}
+		f.flows[alias] = append(f.flows[alias][:i], f.flows[alias][i+1:]...)
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "raise-priority":
+		var alias, i = f.find(parts[1])
+		if i > 0 {
+			f.flows[alias][i-1], f.flows[alias][i] = f.flows[alias][i], f.flows[alias][i-1]
+		}
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "config":
+		var alias, i = f.find(parts[1])
+		var id = f.newID()
+		f.flows[alias][i].config = id
+		f.configs[id] = AuthenticatorConfigRepresentation{ID: &id, Alias: strPtr(body["alias"].(string)), Config: mapPtr(body["config"].(map[string]interface{}))}
+	case r.Method == http.MethodGet && parts[0] == "config":
+		reply(f.configs[parts[1]])
+	case r.Method == http.MethodPut && parts[0] == "config":
+		f.configs[parts[1]] = AuthenticatorConfigRepresentation{ID: strPtr(parts[1]), Alias: strPtr(body["alias"].(string)), Config: mapPtr(body["config"].(map[string]interface{}))}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func mapPtr(value map[string]interface{}) *map[string]interface{} {
+	return &value
+}
+
+func TestSyncAuthenticationFlow(t *testing.T) {
+	var api = &testFlows{
+		flows: map[string][]*testExecution{
+			"browser": nil,
+			"custom": {
+				{id: "otp", provider: "auth-otp-form", requirement: RequirementRequired},
+				{id: "legacy", provider: "auth-spnego", requirement: RequirementDisabled},
+				{id: "cookie", provider: "auth-cookie", requirement: RequirementRequired},
+			},
+		},
+		configs: map[string]AuthenticatorConfigRepresentation{},
+		builtIn: map[string]bool{"browser": true},
+	}
+	var client = newTestClient(t, api)
+
+	var flow = NewAuthenticationFlow("custom", "Custom browser flow").
+		Execution("auth-cookie", RequirementAlternative).
+		SubFlow(NewAuthenticationFlow("custom forms", "").
+			Execution("auth-username-password-form", RequirementRequired).
+			ConfiguredExecution("auth-otp-form", RequirementRequired, FlowAuthenticatorConfig{Alias: "otp", Config: map[string]string{"x": "1"}}),
+			RequirementAlternative)
+
+	assert.Nil(t, SyncAuthenticationFlow(client, "token", "test", *flow))
+	assert.Equal(t, []string{"cookie", "id1"}, testExecutionIDs(api.flows["custom"]))
+	assert.Equal(t, []string{RequirementAlternative, RequirementAlternative}, []string{api.flows["custom"][0].requirement, api.flows["custom"][1].requirement})
+	var forms = api.flows["custom forms"]
+	assert.Len(t, forms, 2)
+	assert.Equal(t, "auth-username-password-form", forms[0].provider)
+	assert.Equal(t, "auth-otp-form", forms[1].provider)
+	assert.Equal(t, RequirementRequired, forms[1].requirement)
+	assert.Equal(t, map[string]interface{}{"x": "1"}, *api.configs[forms[1].config].Config)
+
+	api.requests = nil
+	assert.Nil(t, SyncAuthenticationFlow(client, "token", "test", *flow))
+	assert.Empty(t, api.requests, "a converged flow must not be modified")
+
+	var otp = *forms[1]
+	flow.Executions[1].SubFlow.Executions[1].Config.Config["x"] = "2"
+	flow.Executions[1].SubFlow.Executions[0], flow.Executions[1].SubFlow.Executions[1] = flow.Executions[1].SubFlow.Executions[1], flow.Executions[1].SubFlow.Executions[0]
+	assert.Nil(t, SyncAuthenticationFlow(client, "token", "test", *flow))
+	assert.Equal(t, []string{
+		"PUT /config/" + otp.config,
+		"POST /executions/" + otp.id + "/raise-priority",
+	}, api.requests)
+	assert.Equal(t, otp.id, api.flows["custom forms"][0].id)
+	assert.Equal(t, map[string]interface{}{"x": "2"}, *api.configs[otp.config].Config)
+
+	var err = SyncAuthenticationFlow(client, "token", "test", AuthenticationFlow{Alias: "browser"})
+	assert.Equal(t, Error{Code: MsgErrReadOnly, Detail: BuiltInFlow}, err)
+	err = SyncAuthenticationFlow(client, "token", "test", AuthenticationFlow{})
+	assert.Equal(t, Error{Code: MsgErrMissingParam, Detail: FlowAlias}, err)
+}
+
+func TestSyncAuthenticationFlowProviders(t *testing.T) {
+	var api = &testFlows{flows: map[string][]*testExecution{}, configs: map[string]AuthenticatorConfigRepresentation{}}
+	var client = newTestClient(t, api)
+
+	var flow = NewAuthenticationFlow("custom", "Custom browser flow").
+		SubFlow(NewAuthenticationFlow("custom forms", "Forms").Execution("auth-otp-form", RequirementRequired), RequirementRequired)
+	assert.Nil(t, SyncAuthenticationFlow(client, "token", "test", *flow))
+	var forms = api.flows["custom"][0]
+
+	api.requests = nil
+	flow.Description, flow.Executions[0].SubFlow.Description = "Custom flow", "Custom forms"
+	assert.Nil(t, SyncAuthenticationFlow(client, "token", "test", *flow))
+	assert.Equal(t, []string{"PUT /flows/custom", "PUT /flows/custom forms"}, api.requests)
+	assert.Equal(t, "Custom flow", api.descriptions["custom"])
+	assert.Equal(t, "Custom forms", api.descriptions["custom forms"])
+
+	flow.Executions[0].SubFlow.ProviderID = FlowProviderForm
+	assert.Nil(t, SyncAuthenticationFlow(client, "token", "test", *flow))
+	assert.NotEqual(t, forms.id, api.flows["custom"][0].id, "a sub flow whose provider changed must be recreated")
+	assert.Equal(t, FlowProviderForm, api.providers["custom forms"])
+	assert.Equal(t, RequirementRequired, api.flows["custom"][0].requirement)
+	assert.Equal(t, "auth-otp-form", api.flows["custom forms"][0].provider)
+
+	flow.ProviderID = FlowProviderForm
+	var err = SyncAuthenticationFlow(client, "token", "test", *flow)
+	assert.Equal(t, Error{Code: MsgErrReadOnly, Detail: FlowProvider}, err)
+
+	err = SyncAuthenticationFlow(client, "token", "test", AuthenticationFlow{Alias: "custom forms"})
+	assert.Equal(t, MsgErrExistingValue, err.(Error).Code)
+	assert.Equal(t, FlowAlias, err.(Error).Detail)
+	assert.Contains(t, err.Error(), "flow custom forms")
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	err = SyncAuthenticationFlow(client, "token", "test", *NewAuthenticationFlow("other", "").SubFlow(NewAuthenticationFlow("custom forms", ""), RequirementRequired))
+	assert.Equal(t, MsgErrExistingValue, err.(Error).Code)
+	assert.Contains(t, err.Error(), "sub flow custom forms of flow other")
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	err = SyncAuthenticationFlow(client, "token", "test", *NewAuthenticationFlow("other", "").Execution("auth-unknown", RequirementRequired))
+	assert.Equal(t, MsgErrCannotCreate, err.(Error).Code)
+	assert.Equal(t, AuthenticationExecutions, err.(Error).Detail)
+	assert.Contains(t, err.Error(), "authenticator auth-unknown of flow other")
+	assert.True(t, errors.Is(err, ErrBadRequest))
+}
+
+func testExecutionIDs(executions []*testExecution) []string {
+	var ids []string
+	for _, e := range executions {
+		ids = append(ids, e.id)
+	}
+	return ids
+}
+
+func TestBindAuthenticationFlow(t *testing.T) {
+	var requests []string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, fmt.Sprintf("%s %s %v", r.Method, r.URL.Path, body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	assert.Nil(t, BindAuthenticationFlow(client, "token", "test", FlowBindingDirectGrant, "custom"))
+	assert.Equal(t, []string{"PUT /auth/admin/realms/test map[directGrantFlow:custom]"}, requests)
+	assert.Equal(t, Error{Code: MsgErrInvalidParam, Detail: InvalidFlowBinding}, BindAuthenticationFlow(client, "token", "test", "otherFlow", "custom"))
+}
@@ -100,9 +100,10 @@ func (c *Client) CopyExistingAuthenticationFlow(accessToken string, realmName, f
 	return err
 }
 
-// GetAuthenticationExecutionForFlow returns the authentication executions for a flow.
-func (c *Client) GetAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias string) (AuthenticationExecutionInfoRepresentation, error) {
-	var resp = AuthenticationExecutionInfoRepresentation{}
+// GetAuthenticationExecutionForFlow returns the executions of the flow and of its sub flows, depth first. Their
+// Level and Index give their position, see AuthenticationExecutionTree.
+func (c *Client) GetAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias string) ([]AuthenticationExecutionInfoRepresentation, error) {
+	var resp = []AuthenticationExecutionInfoRepresentation{}
 	var err = c.get(accessToken, &resp, url.Path(authenticationManagementPath+"/flows/:flowAlias/executions"), url.Param("realm", realmName), url.Param("flowAlias", flowAlias))
 	return resp, err
 }
@@ -133,6 +134,11 @@ func (c *Client) GetAuthenticationFlow(accessToken string, realmName, flowID str
 	return resp, err
 }
 
+// UpdateAuthenticationFlow updates the alias and the description of an authentication flow.
+func (c *Client) UpdateAuthenticationFlow(accessToken string, realmName, flowID string, authFlow AuthenticationFlowRepresentation) error {
+	return c.put(accessToken, url.Path(authenticationManagementPath+"/flows/:id"), url.Param("realm", realmName), url.Param("id", flowID), body.JSON(authFlow))
+}
+
 // DeleteAuthenticationFlow deletes an authentication flow.
 func (c *Client) DeleteAuthenticationFlow(accessToken string, realmName, flowID string) error {
 	return c.delete(accessToken, url.Path(authenticationManagementPath+"/flows/:id"), url.Param("realm", realmName), url.Param("id", flowID))
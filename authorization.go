@@ -0,0 +1,235 @@
+package keycloak
+
+import (
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/query"
+	"gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	resourceServerPath          = clientIDPath + "/authz/resource-server"
+	resourcesPath               = resourceServerPath + "/resource"
+	resourceIDPath              = resourcesPath + "/:resourceId"
+	authorizationScopesPath     = resourceServerPath + "/scope"
+	authorizationScopeIDPath    = authorizationScopesPath + "/:scopeId"
+	policiesPath                = resourceServerPath + "/policy"
+	policyIDPath                = policiesPath + "/:policyId"
+	policyDependentPoliciesPath = policyIDPath + "/dependentPolicies"
+	typedPoliciesPath           = policiesPath + "/:type"
+	typedPolicyIDPath           = typedPoliciesPath + "/:policyId"
+	policyEvaluatePath          = policiesPath + "/evaluate"
+	permissionsPath             = resourceServerPath + "/permission"
+	permissionIDPath            = permissionsPath + "/:policyId"
+	typedPermissionsPath        = permissionsPath + "/:type"
+	typedPermissionIDPath       = typedPermissionsPath + "/:policyId"
+)
+
+// policiesOnly excludes the permissions from the policies listed.
+var policiesOnly = query.Add("permission", "false")
+
+// GetResourceServer returns the authorization settings of the client, whose authorization services must be
+// enabled. idClient is the id of client (not client-id).
+func (c *Client) GetResourceServer(accessToken string, realmName, idClient string) (ResourceServerRepresentation, error) {
+	var resp = ResourceServerRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(resourceServerPath), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
+
+// UpdateResourceServer updates the policy enforcement mode, the decision strategy and the remote resource
+// management of the client. Its resources and policies are managed with their own methods.
+func (c *Client) UpdateResourceServer(accessToken string, realmName, idClient string, resourceServer ResourceServerRepresentation) error {
+	return c.put(accessToken, url.Path(resourceServerPath), url.Param("realm", realmName), url.Param("id", idClient), body.JSON(resourceServer))
+}
+
+// GetResources returns the resources of the client, filtered according to the query.
+func (c *Client) GetResources(accessToken string, realmName, idClient string, query ResourceQuery) ([]ResourceRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []ResourceRepresentation{}
+	var plugins = append(query.plugins(), url.Path(resourcesPath), url.Param("realm", realmName), url.Param("id", idClient))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetResource returns a resource of the client.
+func (c *Client) GetResource(accessToken string, realmName, idClient, resourceID string) (ResourceRepresentation, error) {
+	var resp = ResourceRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(resourceIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("resourceId", resourceID))
+	return resp, err
+}
+
+// CreateResource creates a resource and returns it, along with its id. Its scopes are referred to by name and
+// created if they do not exist.
+func (c *Client) CreateResource(accessToken string, realmName, idClient string, resource ResourceRepresentation) (ResourceRepresentation, error) {
+	var resp = ResourceRepresentation{}
+	var _, err = c.post(accessToken, &resp, url.Path(resourcesPath), url.Param("realm", realmName), url.Param("id", idClient), body.JSON(resource))
+	return resp, err
+}
+
+// UpdateResource updates a resource of the client.
+func (c *Client) UpdateResource(accessToken string, realmName, idClient, resourceID string, resource ResourceRepresentation) error {
+	return c.put(accessToken, url.Path(resourceIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("resourceId", resourceID), body.JSON(resource))
+}
+
+// DeleteResource deletes a resource of the client.
+func (c *Client) DeleteResource(accessToken string, realmName, idClient, resourceID string) error {
+	return c.delete(accessToken, url.Path(resourceIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("resourceId", resourceID))
+}
+
+// GetAuthorizationScopes returns the authorization scopes of the client, filtered according to the query.
+func (c *Client) GetAuthorizationScopes(accessToken string, realmName, idClient string, query AuthorizationScopeQuery) ([]ScopeRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []ScopeRepresentation{}
+	var plugins = append(query.plugins(), url.Path(authorizationScopesPath), url.Param("realm", realmName), url.Param("id", idClient))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetAuthorizationScope returns an authorization scope of the client.
+func (c *Client) GetAuthorizationScope(accessToken string, realmName, idClient, scopeID string) (ScopeRepresentation, error) {
+	var resp = ScopeRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(authorizationScopeIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("scopeId", scopeID))
+	return resp, err
+}
+
+// CreateAuthorizationScope creates an authorization scope and returns it, along with its id.
+func (c *Client) CreateAuthorizationScope(accessToken string, realmName, idClient string, scope ScopeRepresentation) (ScopeRepresentation, error) {
+	var resp = ScopeRepresentation{}
+	var _, err = c.post(accessToken, &resp, url.Path(authorizationScopesPath), url.Param("realm", realmName), url.Param("id", idClient), body.JSON(scope))
+	return resp, err
+}
+
+// UpdateAuthorizationScope updates an authorization scope of the client.
+func (c *Client) UpdateAuthorizationScope(accessToken string, realmName, idClient, scopeID string, scope ScopeRepresentation) error {
+	return c.put(accessToken, url.Path(authorizationScopeIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("scopeId", scopeID), body.JSON(scope))
+}
+
+// DeleteAuthorizationScope deletes an authorization scope of the client.
+func (c *Client) DeleteAuthorizationScope(accessToken string, realmName, idClient, scopeID string) error {
+	return c.delete(accessToken, url.Path(authorizationScopeIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("scopeId", scopeID))
+}
+
+// GetPolicies returns the policies of the client, but its permissions, filtered according to the query.
+func (c *Client) GetPolicies(accessToken string, realmName, idClient string, query PolicyQuery) ([]PolicyRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []PolicyRepresentation{}
+	var plugins = append(query.plugins(), policiesOnly, url.Path(policiesPath), url.Param("realm", realmName), url.Param("id", idClient))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetPolicy returns a policy of the client. Its type specific fields are flattened in its Config, GetTypedPolicy
+// returns them typed.
+func (c *Client) GetPolicy(accessToken string, realmName, idClient, policyID string) (PolicyRepresentation, error) {
+	var resp = PolicyRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(policyIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("policyId", policyID))
+	return resp, err
+}
+
+// GetTypedPolicy decodes a policy of the client into policy, a pointer to the representation of its type, e.g.
+// a *RolePolicyRepresentation.
+func (c *Client) GetTypedPolicy(accessToken string, realmName, idClient, policyID string, policy TypedPolicy) error {
+	return c.get(accessToken, policy, url.Path(typedPolicyIDPath), url.Param("realm", realmName), url.Param("id", idClient),
+		url.Param("type", policy.PolicyType()), url.Param("policyId", policyID))
+}
+
+// CreatePolicy creates a policy and returns it, along with its id. Its name must be unique within the client.
+func (c *Client) CreatePolicy(accessToken string, realmName, idClient string, policy TypedPolicy) (PolicyRepresentation, error) {
+	if IsPermissionType(policy.PolicyType()) {
+		return PolicyRepresentation{}, newError(MsgErrInvalidParam, PolicyType, nil)
+	}
+	var resp = PolicyRepresentation{}
+	var _, err = c.post(accessToken, &resp, url.Path(typedPoliciesPath), url.Param("realm", realmName), url.Param("id", idClient),
+		url.Param("type", policy.PolicyType()), body.JSON(policy))
+	return resp, err
+}
+
+// UpdatePolicy updates a policy of the client.
+func (c *Client) UpdatePolicy(accessToken string, realmName, idClient, policyID string, policy TypedPolicy) error {
+	if IsPermissionType(policy.PolicyType()) {
+		return newError(MsgErrInvalidParam, PolicyType, nil)
+	}
+	return c.put(accessToken, url.Path(typedPolicyIDPath), url.Param("realm", realmName), url.Param("id", idClient),
+		url.Param("type", policy.PolicyType()), url.Param("policyId", policyID), body.JSON(policy))
+}
+
+// DeletePolicy deletes a policy or a permission of the client. The policies and permissions it is associated to
+// are kept.
+func (c *Client) DeletePolicy(accessToken string, realmName, idClient, policyID string) error {
+	return c.delete(accessToken, url.Path(policyIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("policyId", policyID))
+}
+
+// GetDependentPolicies returns the policies and permissions associated to a policy of the client.
+func (c *Client) GetDependentPolicies(accessToken string, realmName, idClient, policyID string) ([]PolicyRepresentation, error) {
+	var resp = []PolicyRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(policyDependentPoliciesPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("policyId", policyID))
+	return resp, err
+}
+
+// GetPermissions returns the permissions of the client, filtered according to the query.
+func (c *Client) GetPermissions(accessToken string, realmName, idClient string, query PolicyQuery) ([]PolicyRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []PolicyRepresentation{}
+	var plugins = append(query.plugins(), url.Path(permissionsPath), url.Param("realm", realmName), url.Param("id", idClient))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetPermission returns a permission of the client.
+func (c *Client) GetPermission(accessToken string, realmName, idClient, permissionID string) (PolicyRepresentation, error) {
+	var resp = PolicyRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(permissionIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("policyId", permissionID))
+	return resp, err
+}
+
+// GetTypedPermission decodes a permission of the client into permission, a *ResourcePermissionRepresentation or
+// a *ScopePermissionRepresentation.
+func (c *Client) GetTypedPermission(accessToken string, realmName, idClient, permissionID string, permission TypedPolicy) error {
+	return c.get(accessToken, permission, url.Path(typedPermissionIDPath), url.Param("realm", realmName), url.Param("id", idClient),
+		url.Param("type", permission.PolicyType()), url.Param("policyId", permissionID))
+}
+
+// CreatePermission creates a permission and returns it, along with its id. Its name must be unique within the
+// client, among the policies too.
+func (c *Client) CreatePermission(accessToken string, realmName, idClient string, permission TypedPolicy) (PolicyRepresentation, error) {
+	if !IsPermissionType(permission.PolicyType()) {
+		return PolicyRepresentation{}, newError(MsgErrInvalidParam, PolicyType, nil)
+	}
+	var resp = PolicyRepresentation{}
+	var _, err = c.post(accessToken, &resp, url.Path(typedPermissionsPath), url.Param("realm", realmName), url.Param("id", idClient),
+		url.Param("type", permission.PolicyType()), body.JSON(permission))
+	return resp, err
+}
+
+// UpdatePermission updates a permission of the client.
+func (c *Client) UpdatePermission(accessToken string, realmName, idClient, permissionID string, permission TypedPolicy) error {
+	if !IsPermissionType(permission.PolicyType()) {
+		return newError(MsgErrInvalidParam, PolicyType, nil)
+	}
+	return c.put(accessToken, url.Path(typedPermissionIDPath), url.Param("realm", realmName), url.Param("id", idClient),
+		url.Param("type", permission.PolicyType()), url.Param("policyId", permissionID), body.JSON(permission))
+}
+
+// DeletePermission deletes a permission of the client.
+func (c *Client) DeletePermission(accessToken string, realmName, idClient, permissionID string) error {
+	return c.delete(accessToken, url.Path(permissionIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("policyId", permissionID))
+}
+
+// EvaluatePolicies evaluates the permissions of the client for a user, or a set of roles, and a context, without
+// issuing any token. It returns the decision for each resource and scope, with the policies which lead to it.
+func (c *Client) EvaluatePolicies(accessToken string, realmName, idClient string, request PolicyEvaluationRequest) (PolicyEvaluationResponse, error) {
+	var resp = PolicyEvaluationResponse{}
+	var _, err = c.post(accessToken, &resp, url.Path(policyEvaluatePath), url.Param("realm", realmName), url.Param("id", idClient), body.JSON(request))
+	return resp, err
+}
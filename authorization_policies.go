@@ -0,0 +1,171 @@
+package keycloak
+
+// Types of the policies.
+const (
+	PolicyTypeRole      = "role"
+	PolicyTypeUser      = "user"
+	PolicyTypeGroup     = "group"
+	PolicyTypeClient    = "client"
+	PolicyTypeTime      = "time"
+	PolicyTypeAggregate = "aggregate"
+	PolicyTypeJS        = "js"
+)
+
+// Types of the permissions.
+const (
+	PermissionTypeResource = "resource"
+	PermissionTypeScope    = "scope"
+)
+
+// Decision strategies of the resource servers and of the permissions.
+const (
+	DecisionStrategyUnanimous   = "UNANIMOUS"
+	DecisionStrategyAffirmative = "AFFIRMATIVE"
+	DecisionStrategyConsensus   = "CONSENSUS"
+)
+
+// Logics of the policies. A negative policy grants access when its conditions are not met.
+const (
+	LogicPositive = "POSITIVE"
+	LogicNegative = "NEGATIVE"
+)
+
+// Policy enforcement modes of the resource servers.
+const (
+	PolicyEnforcementModeEnforcing  = "ENFORCING"
+	PolicyEnforcementModePermissive = "PERMISSIVE"
+	PolicyEnforcementModeDisabled   = "DISABLED"
+)
+
+// Decisions returned by EvaluatePolicies.
+const (
+	DecisionPermit = "PERMIT"
+	DecisionDeny   = "DENY"
+)
+
+// TimePolicyDateFormat is the layout of the NotBefore and NotOnOrAfter dates of the time policies.
+const TimePolicyDateFormat = "2006-01-02 15:04:05"
+
+// TypedPolicy is implemented by the representations of the policies and permissions of a given type. Their
+// common fields are those of the embedded PolicyRepresentation, whose Config is not used.
+type TypedPolicy interface {
+	PolicyType() string
+}
+
+// RolePolicyRepresentation grants access to the users holding the roles.
+type RolePolicyRepresentation struct {
+	PolicyRepresentation
+	Roles *[]RolePolicyRoleDefinition `json:"roles,omitempty"`
+	// FetchRoles reads the roles of the user from the database rather than from the access token.
+	FetchRoles *bool `json:"fetchRoles,omitempty"`
+}
+
+// RolePolicyRoleDefinition is a role of a role policy. A required role must be held, the others are alternatives.
+type RolePolicyRoleDefinition struct {
+	ID       *string `json:"id,omitempty"`
+	Required *bool   `json:"required,omitempty"`
+}
+
+// UserPolicyRepresentation grants access to the users, given by id.
+type UserPolicyRepresentation struct {
+	PolicyRepresentation
+	Users *[]string `json:"users,omitempty"`
+}
+
+// GroupPolicyRepresentation grants access to the members of the groups.
+type GroupPolicyRepresentation struct {
+	PolicyRepresentation
+	Groups *[]GroupPolicyGroupDefinition `json:"groups,omitempty"`
+	// GroupsClaim is the claim of the token holding the groups of the user, which are read from the database
+	// if empty.
+	GroupsClaim *string `json:"groupsClaim,omitempty"`
+}
+
+// GroupPolicyGroupDefinition is a group of a group policy. ExtendChildren includes the members of its sub groups.
+type GroupPolicyGroupDefinition struct {
+	ID             *string `json:"id,omitempty"`
+	Path           *string `json:"path,omitempty"`
+	ExtendChildren *bool   `json:"extendChildren,omitempty"`
+}
+
+// ClientPolicyRepresentation grants access to the tokens issued to the clients, given by id.
+type ClientPolicyRepresentation struct {
+	PolicyRepresentation
+	Clients *[]string `json:"clients,omitempty"`
+}
+
+// TimePolicyRepresentation grants access within a period. NotBefore and NotOnOrAfter are formatted with
+// TimePolicyDateFormat, the other fields are the bounds of the recurring periods, e.g. Hour "9" and HourEnd "17".
+type TimePolicyRepresentation struct {
+	PolicyRepresentation
+	NotBefore    *string `json:"notBefore,omitempty"`
+	NotOnOrAfter *string `json:"notOnOrAfter,omitempty"`
+	DayMonth     *string `json:"dayMonth,omitempty"`
+	DayMonthEnd  *string `json:"dayMonthEnd,omitempty"`
+	Month        *string `json:"month,omitempty"`
+	MonthEnd     *string `json:"monthEnd,omitempty"`
+	Year         *string `json:"year,omitempty"`
+	YearEnd      *string `json:"yearEnd,omitempty"`
+	Hour         *string `json:"hour,omitempty"`
+	HourEnd      *string `json:"hourEnd,omitempty"`
+	Minute       *string `json:"minute,omitempty"`
+	MinuteEnd    *string `json:"minuteEnd,omitempty"`
+}
+
+// AggregatePolicyRepresentation combines the Policies with its DecisionStrategy.
+type AggregatePolicyRepresentation struct {
+	PolicyRepresentation
+}
+
+// JSPolicyRepresentation grants access according to a JavaScript code. Recent Keycloak versions only accept
+// the scripts deployed on the server.
+type JSPolicyRepresentation struct {
+	PolicyRepresentation
+	Code *string `json:"code,omitempty"`
+}
+
+// ResourcePermissionRepresentation applies the Policies to the Resources, or to all the resources of
+// ResourceType.
+type ResourcePermissionRepresentation struct {
+	PolicyRepresentation
+	ResourceType *string `json:"resourceType,omitempty"`
+}
+
+// ScopePermissionRepresentation applies the Policies to the Scopes of the Resources, or of all the resources
+// of ResourceType.
+type ScopePermissionRepresentation struct {
+	PolicyRepresentation
+	ResourceType *string `json:"resourceType,omitempty"`
+}
+
+// PolicyType returns PolicyTypeRole.
+func (RolePolicyRepresentation) PolicyType() string { return PolicyTypeRole }
+
+// PolicyType returns PolicyTypeUser.
+func (UserPolicyRepresentation) PolicyType() string { return PolicyTypeUser }
+
+// PolicyType returns PolicyTypeGroup.
+func (GroupPolicyRepresentation) PolicyType() string { return PolicyTypeGroup }
+
+// PolicyType returns PolicyTypeClient.
+func (ClientPolicyRepresentation) PolicyType() string { return PolicyTypeClient }
+
+// PolicyType returns PolicyTypeTime.
+func (TimePolicyRepresentation) PolicyType() string { return PolicyTypeTime }
+
+// PolicyType returns PolicyTypeAggregate.
+func (AggregatePolicyRepresentation) PolicyType() string { return PolicyTypeAggregate }
+
+// PolicyType returns PolicyTypeJS.
+func (JSPolicyRepresentation) PolicyType() string { return PolicyTypeJS }
+
+// PolicyType returns PermissionTypeResource.
+func (ResourcePermissionRepresentation) PolicyType() string { return PermissionTypeResource }
+
+// PolicyType returns PermissionTypeScope.
+func (ScopePermissionRepresentation) PolicyType() string { return PermissionTypeScope }
+
+// IsPermissionType tells whether the policy type is that of a permission.
+func IsPermissionType(policyType string) bool {
+	return policyType == PermissionTypeResource || policyType == PermissionTypeScope
+}
@@ -0,0 +1,61 @@
+package keycloak
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePolicy(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var content, _ = ioutil.ReadAll(r.Body)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/auth/admin/realms/test/clients/c1/authz/resource-server/policy/role", r.URL.Path)
+		assert.JSONEq(t, `{"name":"admins","logic":"POSITIVE","roles":[{"id":"admin","required":true}]}`, string(content))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"p1","name":"admins","type":"role","logic":"POSITIVE","roles":[{"id":"r1","required":true}]}`))
+	}))
+
+	var name, logic, role, required = "admins", LogicPositive, "admin", true
+	var policy, err = client.CreatePolicy("token", "test", "c1", RolePolicyRepresentation{
+		PolicyRepresentation: PolicyRepresentation{Name: &name, Logic: &logic},
+		Roles:                &[]RolePolicyRoleDefinition{{ID: &role, Required: &required}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "p1", *policy.ID)
+	assert.Equal(t, PolicyTypeRole, *policy.Type)
+
+	_, err = client.CreatePolicy("token", "test", "c1", ScopePermissionRepresentation{})
+	assert.Equal(t, Error{Code: MsgErrInvalidParam, Detail: PolicyType}, err)
+}
+
+func TestGetTypedPolicy(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/test/clients/c1/authz/resource-server/permission/scope/p2", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"p2","name":"read documents","type":"scope","resourceType":"urn:app:document","scopes":["s1"]}`))
+	}))
+
+	var permission ScopePermissionRepresentation
+	assert.Nil(t, client.GetTypedPermission("token", "test", "c1", "p2", &permission))
+	assert.Equal(t, "read documents", *permission.Name)
+	assert.Equal(t, "urn:app:document", *permission.ResourceType)
+	assert.Equal(t, []string{"s1"}, *permission.Scopes)
+}
+
+func TestGetPolicies(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/test/clients/c1/authz/resource-server/policy", r.URL.Path)
+		assert.Equal(t, "false", r.URL.Query().Get("permission"))
+		assert.Equal(t, "role", r.URL.Query().Get("type"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"p1","name":"admins","type":"role"}]`))
+	}))
+
+	var policies, err = client.GetPolicies("token", "test", "c1", PolicyQuery{Type: PolicyTypeRole})
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+}
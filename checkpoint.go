@@ -0,0 +1,159 @@
+package keycloak
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoint is the position of an EventStream: the time of the last event delivered, in milliseconds since the
+// epoch, and the number of events delivered at that very millisecond by hash, as distinct events may have the
+// same content.
+type Checkpoint struct {
+	Time   int64          `json:"time"`
+	Hashes map[string]int `json:"hashes,omitempty"`
+}
+
+// CheckpointStore persists the checkpoints of event streams. Load returns the zero Checkpoint for an unknown key.
+type CheckpointStore interface {
+	Load(key string) (Checkpoint, error)
+	Save(key string, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoints in memory. It is safe for concurrent use.
+type MemoryCheckpointStore struct {
+	mutex       sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointStore returns an empty in-memory store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: map[string]Checkpoint{}}
+}
+
+// Load returns the checkpoint saved under key.
+func (s *MemoryCheckpointStore) Load(key string) (Checkpoint, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return copyCheckpoint(s.checkpoints[key]), nil
+}
+
+// Save records checkpoint under key.
+func (s *MemoryCheckpointStore) Save(key string, checkpoint Checkpoint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.checkpoints[key] = copyCheckpoint(checkpoint)
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoints in a JSON file, so that a stream resumes where it stopped after a
+// restart. The file is replaced atomically on each save. It is safe for concurrent use within a process, but
+// the file must not be shared between processes.
+type FileCheckpointStore struct {
+	path string
+
+	mutex       sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewFileCheckpointStore returns a store backed by the file at path, which is created on the first save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load returns the checkpoint saved under key.
+func (s *FileCheckpointStore) Load(key string) (Checkpoint, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.read(); err != nil {
+		return Checkpoint{}, err
+	}
+	return copyCheckpoint(s.checkpoints[key]), nil
+}
+
+// Save records checkpoint under key.
+func (s *FileCheckpointStore) Save(key string, checkpoint Checkpoint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.read(); err != nil {
+		return err
+	}
+
+	var previous, existed = s.checkpoints[key]
+	s.checkpoints[key] = copyCheckpoint(checkpoint)
+	if err := s.write(); err != nil {
+		if existed {
+			s.checkpoints[key] = previous
+		} else {
+			delete(s.checkpoints, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// read loads the file the first time the store is used.
+func (s *FileCheckpointStore) read() error {
+	if s.checkpoints != nil {
+		return nil
+	}
+
+	var content, err = ioutil.ReadFile(s.path)
+	switch {
+	case os.IsNotExist(err):
+		s.checkpoints = map[string]Checkpoint{}
+		return nil
+	case err != nil:
+		return newError(MsgErrCannotObtain, CheckpointFile, err)
+	}
+
+	var checkpoints = map[string]Checkpoint{}
+	if err = json.Unmarshal(content, &checkpoints); err != nil {
+		return newError(MsgErrCannotUnmarshal, CheckpointFile, err)
+	}
+	s.checkpoints = checkpoints
+	return nil
+}
+
+// write replaces the file with a temporary one, so that a crash never leaves a truncated file behind.
+func (s *FileCheckpointStore) write() error {
+	var content, err = json.MarshalIndent(s.checkpoints, "", "  ")
+	if err != nil {
+		return newError(MsgErrCannotMarshal, CheckpointFile, err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return newError(MsgErrCannotCreate, CheckpointFile, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(content); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		return newError(MsgErrCannotCreate, CheckpointFile, err)
+	}
+	return nil
+}
+
+func copyCheckpoint(checkpoint Checkpoint) Checkpoint {
+	if checkpoint.Hashes != nil {
+		var hashes = map[string]int{}
+		for hash, count := range checkpoint.Hashes {
+			hashes[hash] = count
+		}
+		checkpoint.Hashes = hashes
+	}
+	return checkpoint
+}
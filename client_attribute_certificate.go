@@ -1,8 +1,6 @@
 package keycloak
 
 import (
-	"bytes"
-
 	"gopkg.in/h2non/gentleman.v2/plugins/body"
 	"gopkg.in/h2non/gentleman.v2/plugins/url"
 )
@@ -42,13 +40,13 @@ func (c *Client) GenerateKeyPairAndCertificate(accessToken string, realmName, id
 // UploadCertificatePrivateKey uploads a certificate and eventually a private key.
 func (c *Client) UploadCertificatePrivateKey(accessToken string, realmName, idClient, attr string, file []byte) (CertificateRepresentation, error) {
 	var resp = CertificateRepresentation{}
-	_, err := c.post(accessToken, &resp, url.Path(clientAttrCertPath+"/upload"), url.Param("realm", realmName), url.Param("id", idClient), url.Param("attr", attr), body.Reader(bytes.NewReader(file)))
+	_, err := c.post(accessToken, &resp, url.Path(clientAttrCertPath+"/upload"), url.Param("realm", realmName), url.Param("id", idClient), url.Param("attr", attr), body.String(string(file)))
 	return resp, err
 }
 
 // UploadCertificate uploads only a certificate, not the private key.
 func (c *Client) UploadCertificate(accessToken string, realmName, idClient, attr string, file []byte) (CertificateRepresentation, error) {
 	var resp = CertificateRepresentation{}
-	_, err := c.post(accessToken, &resp, url.Path(clientAttrCertPath+"/upload-certificate"), url.Param("realm", realmName), url.Param("id", idClient), url.Param("attr", attr), body.Reader(bytes.NewReader(file)))
+	_, err := c.post(accessToken, &resp, url.Path(clientAttrCertPath+"/upload-certificate"), url.Param("realm", realmName), url.Param("id", idClient), url.Param("attr", attr), body.String(string(file)))
 	return resp, err
 }
@@ -0,0 +1,89 @@
+package keycloak
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// Installation providers of the clients, see GetClientInstallationProvider.
+const (
+	InstallationProviderKeycloakOIDCJSON  = "keycloak-oidc-keycloak-json"
+	InstallationProviderKeycloakOIDCJBoss = "keycloak-oidc-jboss-subsystem"
+	InstallationProviderKeycloakSAML      = "keycloak-saml"
+	InstallationProviderKeycloakSAMLJBoss = "keycloak-saml-subsystem"
+	InstallationProviderSAMLIDPDescriptor = "saml-idp-descriptor"
+	InstallationProviderSAMLSPDescriptor  = "saml-sp-descriptor"
+	InstallationProviderModAuthMellon     = "mod-auth-mellon"
+)
+
+// KeycloakOIDCInstallation is the keycloak.json configuration of the OpenID Connect adapters.
+type KeycloakOIDCInstallation struct {
+	Realm                   string                 `json:"realm"`
+	AuthServerURL           string                 `json:"auth-server-url"`
+	SSLRequired             string                 `json:"ssl-required"`
+	Resource                string                 `json:"resource"`
+	PublicClient            bool                   `json:"public-client,omitempty"`
+	BearerOnly              bool                   `json:"bearer-only,omitempty"`
+	VerifyTokenAudience     bool                   `json:"verify-token-audience,omitempty"`
+	UseResourceRoleMappings bool                   `json:"use-resource-role-mappings,omitempty"`
+	ConfidentialPort        int                    `json:"confidential-port"`
+	Credentials             map[string]interface{} `json:"credentials,omitempty"`
+	PolicyEnforcer          map[string]interface{} `json:"policy-enforcer,omitempty"`
+}
+
+// SAMLEntityDescriptor is the SAML metadata of an identity or service provider.
+type SAMLEntityDescriptor struct {
+	EntityID         string              `xml:"entityID,attr"`
+	IDPSSODescriptor *SAMLRoleDescriptor `xml:"IDPSSODescriptor"`
+	SPSSODescriptor  *SAMLRoleDescriptor `xml:"SPSSODescriptor"`
+}
+
+// SAMLRoleDescriptor describes the keys and endpoints of a SAML identity or service provider.
+type SAMLRoleDescriptor struct {
+	KeyDescriptors            []SAMLKeyDescriptor `xml:"KeyDescriptor"`
+	NameIDFormats             []string            `xml:"NameIDFormat"`
+	SingleSignOnServices      []SAMLEndpoint      `xml:"SingleSignOnService"`
+	SingleLogoutServices      []SAMLEndpoint      `xml:"SingleLogoutService"`
+	AssertionConsumerServices []SAMLEndpoint      `xml:"AssertionConsumerService"`
+}
+
+// SAMLKeyDescriptor is a certificate of a SAML provider, base64 encoded. Use is "signing", "encryption" or empty
+// for both.
+type SAMLKeyDescriptor struct {
+	Use         string `xml:"use,attr"`
+	Certificate string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+// SAMLEndpoint is an endpoint of a SAML provider.
+type SAMLEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+	Index    int    `xml:"index,attr,omitempty"`
+}
+
+// GetKeycloakOIDCInstallation returns the keycloak.json configuration of the OpenID Connect adapter of the client.
+func GetKeycloakOIDCInstallation(client KeycloakAdmin, accessToken string, realmName, idClient string) (KeycloakOIDCInstallation, error) {
+	var installation = KeycloakOIDCInstallation{}
+	var content, err = client.GetClientInstallationProvider(accessToken, realmName, idClient, InstallationProviderKeycloakOIDCJSON)
+	if err != nil {
+		return installation, err
+	}
+	if err = json.Unmarshal(content, &installation); err != nil {
+		return installation, newError(MsgErrCannotUnmarshal, Response, err)
+	}
+	return installation, nil
+}
+
+// GetSAMLDescriptor returns the SAML metadata generated by the installation provider, either
+// InstallationProviderSAMLIDPDescriptor or InstallationProviderSAMLSPDescriptor.
+func GetSAMLDescriptor(client KeycloakAdmin, accessToken string, realmName, idClient, providerID string) (SAMLEntityDescriptor, error) {
+	var descriptor = SAMLEntityDescriptor{}
+	var content, err = client.GetClientInstallationProvider(accessToken, realmName, idClient, providerID)
+	if err != nil {
+		return descriptor, err
+	}
+	if err = xml.Unmarshal(content, &descriptor); err != nil {
+		return descriptor, newError(MsgErrCannotUnmarshal, Response, err)
+	}
+	return descriptor, nil
+}
@@ -0,0 +1,16 @@
+package keycloak
+
+// GetClientByClientID returns the client whose client-id is clientID, e.g. to find out its id. It fails with
+// ErrNotFound if there is no such client.
+func GetClientByClientID(client KeycloakAdmin, accessToken string, realmName, clientID string) (ClientRepresentation, error) {
+	var clients, err = client.GetClients(accessToken, realmName, ClientQuery{ClientID: clientID})
+	if err != nil {
+		return ClientRepresentation{}, err
+	}
+	for _, c := range clients {
+		if c.ClientID != nil && *c.ClientID == clientID {
+			return c, nil
+		}
+	}
+	return ClientRepresentation{}, newError(MsgErrNotFound, ClientNotFound, ErrNotFound)
+}
@@ -6,10 +6,22 @@ import (
 )
 
 const (
-	clientRoleMappingPath = "/auth/admin/realms/:realm/users/:id/role-mappings/clients/:client"
-	realmRoleMappingPath  = "/auth/admin/realms/:realm/users/:id/role-mappings/realm"
+	roleMappingPath                = "/auth/admin/realms/:realm/users/:id/role-mappings"
+	clientRoleMappingPath          = roleMappingPath + "/clients/:client"
+	clientRoleMappingCompositePath = clientRoleMappingPath + "/composite"
+	clientRoleMappingAvailablePath = clientRoleMappingPath + "/available"
+	realmRoleMappingPath           = roleMappingPath + "/realm"
+	realmRoleMappingCompositePath  = realmRoleMappingPath + "/composite"
+	realmRoleMappingAvailablePath  = realmRoleMappingPath + "/available"
 )
 
+// GetRoleMappings gets the realm and client roles directly granted to the user.
+func (c *Client) GetRoleMappings(accessToken string, realmName, userID string) (MappingsRepresentation, error) {
+	var resp = MappingsRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(roleMappingPath), url.Param("realm", realmName), url.Param("id", userID))
+	return resp, err
+}
+
 // AddClientRolesToUserRoleMapping add client-level roles to the user role mapping.
 func (c *Client) AddClientRolesToUserRoleMapping(accessToken string, realmName, userID, clientID string, roles []RoleRepresentation) error {
 	_, err := c.post(accessToken, nil, url.Path(clientRoleMappingPath), url.Param("realm", realmName), url.Param("id", userID), url.Param("client", clientID), body.JSON(roles))
@@ -24,8 +36,23 @@ func (c *Client) GetClientRoleMappings(accessToken string, realmName, userID, cl
 }
 
 // DeleteClientRolesFromUserRoleMapping deletes client-level roles from user role mapping.
-func (c *Client) DeleteClientRolesFromUserRoleMapping(accessToken string, realmName, userID, clientID string) error {
-	return c.delete(accessToken, url.Path(clientRoleMappingPath), url.Param("realm", realmName), url.Param("id", userID), url.Param("client", clientID))
+func (c *Client) DeleteClientRolesFromUserRoleMapping(accessToken string, realmName, userID, clientID string, roles []RoleRepresentation) error {
+	return c.delete(accessToken, url.Path(clientRoleMappingPath), url.Param("realm", realmName), url.Param("id", userID), url.Param("client", clientID), body.JSON(roles))
+}
+
+// GetEffectiveClientRoleMappings gets the client-level roles granted to the user, directly, through composite
+// roles or through groups.
+func (c *Client) GetEffectiveClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientRoleMappingCompositePath), url.Param("realm", realmName), url.Param("id", userID), url.Param("client", clientID))
+	return resp, err
+}
+
+// GetAvailableClientRoleMappings gets the client-level roles which can still be granted to the user.
+func (c *Client) GetAvailableClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientRoleMappingAvailablePath), url.Param("realm", realmName), url.Param("id", userID), url.Param("client", clientID))
+	return resp, err
 }
 
 // GetRealmLevelRoleMappings gets realm level role mappings
@@ -34,3 +61,29 @@ func (c *Client) GetRealmLevelRoleMappings(accessToken string, realmName, userID
 	var err = c.get(accessToken, &resp, url.Path(realmRoleMappingPath), url.Param("realm", realmName), url.Param("id", userID))
 	return resp, err
 }
+
+// AddRealmRolesToUserRoleMapping adds realm-level roles to the user role mapping.
+func (c *Client) AddRealmRolesToUserRoleMapping(accessToken string, realmName, userID string, roles []RoleRepresentation) error {
+	_, err := c.post(accessToken, nil, url.Path(realmRoleMappingPath), url.Param("realm", realmName), url.Param("id", userID), body.JSON(roles))
+	return err
+}
+
+// DeleteRealmRolesFromUserRoleMapping deletes realm-level roles from the user role mapping.
+func (c *Client) DeleteRealmRolesFromUserRoleMapping(accessToken string, realmName, userID string, roles []RoleRepresentation) error {
+	return c.delete(accessToken, url.Path(realmRoleMappingPath), url.Param("realm", realmName), url.Param("id", userID), body.JSON(roles))
+}
+
+// GetEffectiveRealmRoleMappings gets the realm-level roles granted to the user, directly, through composite roles
+// or through groups.
+func (c *Client) GetEffectiveRealmRoleMappings(accessToken string, realmName, userID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(realmRoleMappingCompositePath), url.Param("realm", realmName), url.Param("id", userID))
+	return resp, err
+}
+
+// GetAvailableRealmRoleMappings gets the realm-level roles which can still be granted to the user.
+func (c *Client) GetAvailableRealmRoleMappings(accessToken string, realmName, userID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(realmRoleMappingAvailablePath), url.Param("realm", realmName), url.Param("id", userID))
+	return resp, err
+}
@@ -0,0 +1,26 @@
+package keycloak
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRoleMappings(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/test/users/u1/role-mappings", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"realmMappings": [{"id": "r1", "name": "admin"}],
+			"clientMappings": {"app": {"id": "c1", "client": "app", "mappings": [{"id": "r2", "name": "view"}]}}
+		}`))
+	}))
+
+	var mappings, err = client.GetRoleMappings("token", "test", "u1")
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", *(*mappings.RealmMappings)[0].Name)
+	var app = (*mappings.ClientMappings)["app"]
+	assert.Equal(t, "c1", *app.ID)
+	assert.Equal(t, "view", *(*app.Mappings)[0].Name)
+}
@@ -0,0 +1,229 @@
+package keycloak
+
+import (
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	clientScopesPath                            = "/auth/admin/realms/:realm/client-scopes"
+	clientScopeIDPath                           = clientScopesPath + "/:id"
+	clientScopeMappersPath                      = clientScopeIDPath + "/protocol-mappers/models"
+	clientScopeMapperIDPath                     = clientScopeMappersPath + "/:mapperId"
+	clientScopeScopeMappingsPath                = clientScopeIDPath + "/scope-mappings"
+	clientScopeRealmScopeMappingsPath           = clientScopeScopeMappingsPath + "/realm"
+	clientScopeAvailableRealmScopeMappingsPath  = clientScopeRealmScopeMappingsPath + "/available"
+	clientScopeEffectiveRealmScopeMappingsPath  = clientScopeRealmScopeMappingsPath + "/composite"
+	clientScopeClientScopeMappingsPath          = clientScopeScopeMappingsPath + "/clients/:client"
+	clientScopeAvailableClientScopeMappingsPath = clientScopeClientScopeMappingsPath + "/available"
+	clientScopeEffectiveClientScopeMappingsPath = clientScopeClientScopeMappingsPath + "/composite"
+	realmDefaultClientScopesPath                = "/auth/admin/realms/:realm/default-default-client-scopes"
+	realmDefaultClientScopeIDPath               = realmDefaultClientScopesPath + "/:clientScopeId"
+	realmOptionalClientScopesPath               = "/auth/admin/realms/:realm/default-optional-client-scopes"
+	realmOptionalClientScopeIDPath              = realmOptionalClientScopesPath + "/:clientScopeId"
+	clientDefaultScopesPath                     = clientIDPath + "/default-client-scopes"
+	clientDefaultScopeIDPath                    = clientDefaultScopesPath + "/:clientScopeId"
+	clientOptionalScopesPath                    = clientIDPath + "/optional-client-scopes"
+	clientOptionalScopeIDPath                   = clientOptionalScopesPath + "/:clientScopeId"
+)
+
+// GetClientScopes returns the client scopes of the realm.
+func (c *Client) GetClientScopes(accessToken string, realmName string) ([]ClientScopeRepresentation, error) {
+	var resp = []ClientScopeRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopesPath), url.Param("realm", realmName))
+	return resp, err
+}
+
+// GetClientScope returns the client scope, along with its protocol mappers.
+func (c *Client) GetClientScope(accessToken string, realmName, scopeID string) (ClientScopeRepresentation, error) {
+	var resp = ClientScopeRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeIDPath), url.Param("realm", realmName), url.Param("id", scopeID))
+	return resp, err
+}
+
+// CreateClientScope creates a client scope. Its name must be unique within the realm.
+func (c *Client) CreateClientScope(accessToken string, realmName string, scope ClientScopeRepresentation) (string, error) {
+	return c.post(accessToken, nil, url.Path(clientScopesPath), url.Param("realm", realmName), body.JSON(scope))
+}
+
+// UpdateClientScope updates the client scope. Its protocol mappers are managed with the mapper methods.
+func (c *Client) UpdateClientScope(accessToken string, realmName, scopeID string, scope ClientScopeRepresentation) error {
+	return c.put(accessToken, url.Path(clientScopeIDPath), url.Param("realm", realmName), url.Param("id", scopeID), body.JSON(scope))
+}
+
+// DeleteClientScope deletes the client scope.
+func (c *Client) DeleteClientScope(accessToken string, realmName, scopeID string) error {
+	return c.delete(accessToken, url.Path(clientScopeIDPath), url.Param("realm", realmName), url.Param("id", scopeID))
+}
+
+// GetClientScopeMappers returns the protocol mappers of the client scope.
+func (c *Client) GetClientScopeMappers(accessToken string, realmName, scopeID string) ([]ProtocolMapperRepresentation, error) {
+	var resp = []ProtocolMapperRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeMappersPath), url.Param("realm", realmName), url.Param("id", scopeID))
+	return resp, err
+}
+
+// GetClientScopeMapper returns a protocol mapper of the client scope.
+func (c *Client) GetClientScopeMapper(accessToken string, realmName, scopeID, mapperID string) (ProtocolMapperRepresentation, error) {
+	var resp = ProtocolMapperRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeMapperIDPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("mapperId", mapperID))
+	return resp, err
+}
+
+// CreateClientScopeMapper adds a protocol mapper to the client scope. Its name must be unique within the scope.
+func (c *Client) CreateClientScopeMapper(accessToken string, realmName, scopeID string, mapper ProtocolMapperRepresentation) (string, error) {
+	return c.post(accessToken, nil, url.Path(clientScopeMappersPath), url.Param("realm", realmName), url.Param("id", scopeID), body.JSON(mapper))
+}
+
+// UpdateClientScopeMapper updates a protocol mapper of the client scope. The mapper must contain its ID.
+func (c *Client) UpdateClientScopeMapper(accessToken string, realmName, scopeID, mapperID string, mapper ProtocolMapperRepresentation) error {
+	return c.put(accessToken, url.Path(clientScopeMapperIDPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("mapperId", mapperID), body.JSON(mapper))
+}
+
+// DeleteClientScopeMapper deletes a protocol mapper of the client scope.
+func (c *Client) DeleteClientScopeMapper(accessToken string, realmName, scopeID, mapperID string) error {
+	return c.delete(accessToken, url.Path(clientScopeMapperIDPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("mapperId", mapperID))
+}
+
+// GetClientScopeScopeMappings returns the realm and client roles the client scope puts in the tokens.
+func (c *Client) GetClientScopeScopeMappings(accessToken string, realmName, scopeID string) (MappingsRepresentation, error) {
+	var resp = MappingsRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID))
+	return resp, err
+}
+
+// GetClientScopeRealmScopeMappings returns the realm roles mapped to the client scope.
+func (c *Client) GetClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeRealmScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID))
+	return resp, err
+}
+
+// AddClientScopeRealmScopeMappings maps realm roles to the client scope.
+func (c *Client) AddClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string, roles []RoleRepresentation) error {
+	var _, err = c.post(accessToken, nil, url.Path(clientScopeRealmScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID), body.JSON(roles))
+	return err
+}
+
+// DeleteClientScopeRealmScopeMappings unmaps realm roles from the client scope.
+func (c *Client) DeleteClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string, roles []RoleRepresentation) error {
+	return c.delete(accessToken, url.Path(clientScopeRealmScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID), body.JSON(roles))
+}
+
+// GetAvailableClientScopeRealmScopeMappings returns the realm roles which can still be mapped to the client scope.
+func (c *Client) GetAvailableClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeAvailableRealmScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID))
+	return resp, err
+}
+
+// GetEffectiveClientScopeRealmScopeMappings returns the realm roles mapped to the client scope, directly or through
+// composite roles.
+func (c *Client) GetEffectiveClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeEffectiveRealmScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID))
+	return resp, err
+}
+
+// GetClientScopeClientScopeMappings returns the roles of the client mapped to the client scope. idClient is the id
+// of client (not client-id).
+func (c *Client) GetClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeClientScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("client", idClient))
+	return resp, err
+}
+
+// AddClientScopeClientScopeMappings maps roles of the client to the client scope.
+func (c *Client) AddClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string, roles []RoleRepresentation) error {
+	var _, err = c.post(accessToken, nil, url.Path(clientScopeClientScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("client", idClient), body.JSON(roles))
+	return err
+}
+
+// DeleteClientScopeClientScopeMappings unmaps roles of the client from the client scope.
+func (c *Client) DeleteClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string, roles []RoleRepresentation) error {
+	return c.delete(accessToken, url.Path(clientScopeClientScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("client", idClient), body.JSON(roles))
+}
+
+// GetAvailableClientScopeClientScopeMappings returns the roles of the client which can still be mapped to the
+// client scope.
+func (c *Client) GetAvailableClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeAvailableClientScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("client", idClient))
+	return resp, err
+}
+
+// GetEffectiveClientScopeClientScopeMappings returns the roles of the client mapped to the client scope, directly
+// or through composite roles.
+func (c *Client) GetEffectiveClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientScopeEffectiveClientScopeMappingsPath), url.Param("realm", realmName), url.Param("id", scopeID), url.Param("client", idClient))
+	return resp, err
+}
+
+// GetRealmDefaultClientScopes returns the client scopes assigned as default scopes to the new clients.
+func (c *Client) GetRealmDefaultClientScopes(accessToken string, realmName string) ([]ClientScopeRepresentation, error) {
+	var resp = []ClientScopeRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(realmDefaultClientScopesPath), url.Param("realm", realmName))
+	return resp, err
+}
+
+// AddRealmDefaultClientScope assigns the client scope as a default scope to the new clients.
+func (c *Client) AddRealmDefaultClientScope(accessToken string, realmName, scopeID string) error {
+	return c.put(accessToken, url.Path(realmDefaultClientScopeIDPath), url.Param("realm", realmName), url.Param("clientScopeId", scopeID))
+}
+
+// RemoveRealmDefaultClientScope stops assigning the client scope as a default scope to the new clients.
+func (c *Client) RemoveRealmDefaultClientScope(accessToken string, realmName, scopeID string) error {
+	return c.delete(accessToken, url.Path(realmDefaultClientScopeIDPath), url.Param("realm", realmName), url.Param("clientScopeId", scopeID))
+}
+
+// GetRealmOptionalClientScopes returns the client scopes assigned as optional scopes to the new clients.
+func (c *Client) GetRealmOptionalClientScopes(accessToken string, realmName string) ([]ClientScopeRepresentation, error) {
+	var resp = []ClientScopeRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(realmOptionalClientScopesPath), url.Param("realm", realmName))
+	return resp, err
+}
+
+// AddRealmOptionalClientScope assigns the client scope as an optional scope to the new clients.
+func (c *Client) AddRealmOptionalClientScope(accessToken string, realmName, scopeID string) error {
+	return c.put(accessToken, url.Path(realmOptionalClientScopeIDPath), url.Param("realm", realmName), url.Param("clientScopeId", scopeID))
+}
+
+// RemoveRealmOptionalClientScope stops assigning the client scope as an optional scope to the new clients.
+func (c *Client) RemoveRealmOptionalClientScope(accessToken string, realmName, scopeID string) error {
+	return c.delete(accessToken, url.Path(realmOptionalClientScopeIDPath), url.Param("realm", realmName), url.Param("clientScopeId", scopeID))
+}
+
+// GetClientDefaultScopes returns the default client scopes of the client, always applied to its tokens.
+func (c *Client) GetClientDefaultScopes(accessToken string, realmName, idClient string) ([]ClientScopeRepresentation, error) {
+	var resp = []ClientScopeRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientDefaultScopesPath), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
+
+// AddClientDefaultScope assigns the client scope as a default scope of the client.
+func (c *Client) AddClientDefaultScope(accessToken string, realmName, idClient, scopeID string) error {
+	return c.put(accessToken, url.Path(clientDefaultScopeIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("clientScopeId", scopeID))
+}
+
+// RemoveClientDefaultScope removes a default client scope from the client.
+func (c *Client) RemoveClientDefaultScope(accessToken string, realmName, idClient, scopeID string) error {
+	return c.delete(accessToken, url.Path(clientDefaultScopeIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("clientScopeId", scopeID))
+}
+
+// GetClientOptionalScopes returns the optional client scopes of the client, applied to its tokens on request.
+func (c *Client) GetClientOptionalScopes(accessToken string, realmName, idClient string) ([]ClientScopeRepresentation, error) {
+	var resp = []ClientScopeRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientOptionalScopesPath), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
+
+// AddClientOptionalScope assigns the client scope as an optional scope of the client.
+func (c *Client) AddClientOptionalScope(accessToken string, realmName, idClient, scopeID string) error {
+	return c.put(accessToken, url.Path(clientOptionalScopeIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("clientScopeId", scopeID))
+}
+
+// RemoveClientOptionalScope removes an optional client scope from the client.
+func (c *Client) RemoveClientOptionalScope(accessToken string, realmName, idClient, scopeID string) error {
+	return c.delete(accessToken, url.Path(clientOptionalScopeIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("clientScopeId", scopeID))
+}
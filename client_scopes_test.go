@@ -0,0 +1,29 @@
+package keycloak
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientScopeAssignment(t *testing.T) {
+	var requests []string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var content, _ = ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(content))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	assert.Nil(t, client.AddClientDefaultScope("token", "test", "c1", "s1"))
+	assert.Nil(t, client.RemoveClientOptionalScope("token", "test", "c1", "s1"))
+	assert.Nil(t, client.AddRealmOptionalClientScope("token", "test", "s1"))
+	assert.Nil(t, client.DeleteClientScopeRealmScopeMappings("token", "test", "s1", []RoleRepresentation{{Name: strPtr("reader")}}))
+	assert.Equal(t, []string{
+		"PUT /auth/admin/realms/test/clients/c1/default-client-scopes/s1 ",
+		"DELETE /auth/admin/realms/test/clients/c1/optional-client-scopes/s1 ",
+		"PUT /auth/admin/realms/test/default-optional-client-scopes/s1 ",
+		`DELETE /auth/admin/realms/test/client-scopes/s1/scope-mappings/realm [{"name":"reader"}]` + "\n",
+	}, requests)
+}
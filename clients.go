@@ -1,29 +1,29 @@
 package keycloak
 
 import (
-	"errors"
-
 	"gopkg.in/h2non/gentleman.v2/plugins/body"
 	"gopkg.in/h2non/gentleman.v2/plugins/url"
 )
 
 const (
-	clientsPath       = "/auth/admin/realms/:realm/clients"
-	clientIDPath      = clientsPath + "/:id"
-	clientSecret      = clientsPath + "/client-secret"
-	clientMappersPath = clientIDPath + "/evaluate-scopes/protocol-mappers"
+	clientsPath                  = "/auth/admin/realms/:realm/clients"
+	clientIDPath                 = clientsPath + "/:id"
+	clientSecret                 = clientIDPath + "/client-secret"
+	clientMappersPath            = clientIDPath + "/evaluate-scopes/protocol-mappers"
+	clientServiceAccountUserPath = clientIDPath + "/service-account-user"
+	clientInstallationPath       = clientIDPath + "/installation/providers/:providerId"
+	clientPushRevocationPath     = clientIDPath + "/push-revocation"
+	clientTestNodesPath          = clientIDPath + "/test-nodes-available"
 )
 
-// GetClients returns a list of clients belonging to the realm.
-// Parameters: clientId (filter by clientId),
-// viewableOnly (filter clients that cannot be viewed in full by admin, default="false")
-func (c *Client) GetClients(accessToken string, realmName string, paramKV ...string) ([]ClientRepresentation, error) {
-	if len(paramKV)%2 != 0 {
-		return nil, errors.New(MsgErrInvalidParam + "." + EvenParams)
+// GetClients returns a list of clients belonging to the realm, filtered according to the query.
+func (c *Client) GetClients(accessToken string, realmName string, query ClientQuery) ([]ClientRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
 	}
 
 	var resp = []ClientRepresentation{}
-	var plugins = append(createQueryPlugins(paramKV...), url.Path(clientsPath), url.Param("realm", realmName))
+	var plugins = append(query.plugins(), url.Path(clientsPath), url.Param("realm", realmName))
 	var err = c.get(accessToken, &resp, plugins...)
 	return resp, err
 }
@@ -46,9 +46,9 @@ func (c *Client) CreateClient(accessToken string, realmName string, clientRep Cl
 }
 
 // GetClientMappers gets mappers of the client specified by id
-func (c *Client) GetClientMappers(accessToke string, realmName, idClient string) ([]ClientMapperRepresentation, error) {
+func (c *Client) GetClientMappers(accessToken string, realmName, idClient string) ([]ClientMapperRepresentation, error) {
 	var resp = []ClientMapperRepresentation{}
-	var err = c.get(accessToke, &resp, url.Path(clientMappersPath), url.Param("realm", realmName), url.Param("id", idClient))
+	var err = c.get(accessToken, &resp, url.Path(clientMappersPath), url.Param("realm", realmName), url.Param("id", idClient))
 	return resp, err
 }
 
@@ -58,3 +58,46 @@ func (c *Client) GetSecret(accessToken string, realmName, idClient string) (Cred
 	var err = c.get(accessToken, &resp, url.Path(clientSecret), url.Param("realm", realmName), url.Param("id", idClient))
 	return resp, err
 }
+
+// RegenerateSecret generates a new secret for the client and returns it. idClient is the id of client (not
+// client-id).
+func (c *Client) RegenerateSecret(accessToken string, realmName, idClient string) (CredentialRepresentation, error) {
+	var resp = CredentialRepresentation{}
+	var _, err = c.post(accessToken, &resp, url.Path(clientSecret), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
+
+// DeleteClient deletes the client, along with its roles. idClient is the id of client (not client-id).
+func (c *Client) DeleteClient(accessToken string, realmName, idClient string) error {
+	return c.delete(accessToken, url.Path(clientIDPath), url.Param("realm", realmName), url.Param("id", idClient))
+}
+
+// GetServiceAccountUser returns the user dedicated to the service account of the client. It fails if the
+// service account of the client is not enabled.
+func (c *Client) GetServiceAccountUser(accessToken string, realmName, idClient string) (UserRepresentation, error) {
+	var resp = UserRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientServiceAccountUserPath), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
+
+// GetClientInstallationProvider returns the configuration of the client adapter generated by the installation
+// provider, one of the InstallationProvider constants. It is a JSON, XML or text document depending on the
+// provider.
+func (c *Client) GetClientInstallationProvider(accessToken string, realmName, idClient, providerID string) ([]byte, error) {
+	return c.getBytes(accessToken, url.Path(clientInstallationPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("providerId", providerID))
+}
+
+// PushRevocation sets the not-before of the client to now and pushes it to the admin URL of the client, so that
+// the tokens issued before are rejected.
+func (c *Client) PushRevocation(accessToken string, realmName, idClient string) (GlobalRequestResult, error) {
+	var resp = GlobalRequestResult{}
+	var _, err = c.post(accessToken, &resp, url.Path(clientPushRevocationPath), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
+
+// TestNodesAvailable checks that the cluster nodes registered by the client are reachable.
+func (c *Client) TestNodesAvailable(accessToken string, realmName, idClient string) (GlobalRequestResult, error) {
+	var resp = GlobalRequestResult{}
+	var err = c.get(accessToken, &resp, url.Path(clientTestNodesPath), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
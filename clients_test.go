@@ -0,0 +1,47 @@
+package keycloak
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSecret(t *testing.T) {
+	var paths []string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"type":"secret","value":"s3cr3t"}`))
+	}))
+
+	var secret, err = client.GetSecret("token", "test", "c1")
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", *secret.Value)
+	_, err = client.RegenerateSecret("token", "test", "c1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"GET /auth/admin/realms/test/clients/c1/client-secret",
+		"POST /auth/admin/realms/test/clients/c1/client-secret",
+	}, paths)
+}
+
+func TestGetSAMLDescriptor(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/test/clients/c1/installation/providers/saml-idp-descriptor", r.URL.Path)
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="http://keycloak/auth/realms/test">
+  <IDPSSODescriptor>
+    <KeyDescriptor use="signing"><dsig:KeyInfo xmlns:dsig="http://www.w3.org/2000/09/xmldsig#"><dsig:X509Data><dsig:X509Certificate>MIIC</dsig:X509Certificate></dsig:X509Data></dsig:KeyInfo></KeyDescriptor>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="http://keycloak/auth/realms/test/protocol/saml"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`))
+	}))
+
+	var descriptor, err = GetSAMLDescriptor(client, "token", "test", "c1", InstallationProviderSAMLIDPDescriptor)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://keycloak/auth/realms/test", descriptor.EntityID)
+	assert.Nil(t, descriptor.SPSSODescriptor)
+	assert.Equal(t, []SAMLKeyDescriptor{{Use: "signing", Certificate: "MIIC"}}, descriptor.IDPSSODescriptor.KeyDescriptors)
+	assert.Equal(t, "http://keycloak/auth/realms/test/protocol/saml", descriptor.IDPSSODescriptor.SingleSignOnServices[0].Location)
+}
@@ -31,10 +31,10 @@ func (c *Client) CreateComponent(accessToken string, realmName string, component
 
 // UpdateComponent updates a new component in the realm
 func (c *Client) UpdateComponent(accessToken string, realmName, componentID string, component ComponentRepresentation) error {
-	return c.put(accessToken, nil, url.Path(componentsPath), url.Param("realm", realmName), url.Param("id", componentID), body.JSON(component))
+	return c.put(accessToken, url.Path(componentsByIDPath), url.Param("realm", realmName), url.Param("id", componentID), body.JSON(component))
 }
 
 // DeleteComponent deletes a component in the realm
 func (c *Client) DeleteComponent(accessToken string, realmName, componentID string) error {
-	return c.delete(accessToken, nil, url.Path(componentsPath), url.Param("realm", realmName), url.Param("id", componentID))
+	return c.delete(accessToken, url.Path(componentsByIDPath), url.Param("realm", realmName), url.Param("id", componentID))
 }
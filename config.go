@@ -6,9 +6,15 @@ import (
 
 // Config is the keycloak client http config.
 type Config struct {
+	// AddrTokenProvider is the URL of the realm used to obtain tokens, e.g. http://localhost:8080/auth/realms/master
 	AddrTokenProvider string
 	AddrAPI           string
 	Timeout           time.Duration
-	CacheTTL          time.Duration
-	ErrorTolerance    time.Duration
+	// CacheTTL is the maximum duration a token is cached by the TokenProvider (0 means until it expires)
+	CacheTTL time.Duration
+	// ErrorTolerance is how long the TokenProvider keeps serving its cached token while Keycloak cannot be reached,
+	// within the validity of the token
+	ErrorTolerance time.Duration
+	// Retry is the policy applied by the client to the requests which fail. By default, they are not retried.
+	Retry RetryPolicy
 }
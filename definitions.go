@@ -170,6 +170,16 @@ type ClientRepresentation struct {
 	WebOrigins                   *[]string                       `json:"webOrigins,omitempty"`
 }
 
+// ClientScopeRepresentation struct
+type ClientScopeRepresentation struct {
+	Attributes      *map[string]interface{}         `json:"attributes,omitempty"`
+	Description     *string                         `json:"description,omitempty"`
+	ID              *string                         `json:"id,omitempty"`
+	Name            *string                         `json:"name,omitempty"`
+	Protocol        *string                         `json:"protocol,omitempty"`
+	ProtocolMappers *[]ProtocolMapperRepresentation `json:"protocolMappers,omitempty"`
+}
+
 // ClientTemplateRepresentation struct
 type ClientTemplateRepresentation struct {
 	Attributes                *map[string]interface{}         `json:"attributes,omitempty"`
@@ -256,6 +266,15 @@ type EventRepresentation struct {
 	UserID    *string                 `json:"userId,omitempty"`
 }
 
+// EvaluationResultRepresentation struct
+type EvaluationResultRepresentation struct {
+	AllowedScopes *[]ScopeRepresentation        `json:"allowedScopes,omitempty"`
+	Policies      *[]PolicyResultRepresentation `json:"policies,omitempty"`
+	Resource      *ResourceRepresentation       `json:"resource,omitempty"`
+	Scopes        *[]ScopeRepresentation        `json:"scopes,omitempty"`
+	Status        *string                       `json:"status,omitempty"`
+}
+
 // FederatedIdentityRepresentation struct
 type FederatedIdentityRepresentation struct {
 	IdentityProvider *string `json:"identityProvider,omitempty"`
@@ -342,8 +361,8 @@ type ManagementPermissionReference struct {
 
 // MappingsRepresentation struct
 type MappingsRepresentation struct {
-	ClientMappings *map[string]interface{} `json:"clientMappings,omitempty"`
-	RealmMappings  *[]RoleRepresentation   `json:"realmMappings,omitempty"`
+	ClientMappings *map[string]ClientMappingsRepresentation `json:"clientMappings,omitempty"`
+	RealmMappings  *[]RoleRepresentation                    `json:"realmMappings,omitempty"`
 }
 
 // MemoryInfoRepresentation struct
@@ -365,8 +384,7 @@ type PartialImportRepresentation struct {
 	Clients           *[]ClientRepresentation           `json:"clients,omitempty"`
 	Groups            *[]GroupRepresentation            `json:"groups,omitempty"`
 	IdentityProviders *[]IdentityProviderRepresentation `json:"identityProviders,omitempty"`
-	IfResourceExists  *string                           `json:"ifResourceExists,omitempty"`
-	Policy            *string                           `json:"policy,omitempty"`
+	IfResourceExists  IfResourceExists                  `json:"ifResourceExists,omitempty"`
 	Roles             *RolesRepresentation              `json:"roles,omitempty"`
 	Users             *[]UserRepresentation             `json:"users,omitempty"`
 }
@@ -380,6 +398,25 @@ type PasswordPolicyTypeRepresentation struct {
 	MultipleSupported *bool   `json:"multipleSupported,omitempty"`
 }
 
+// PolicyEvaluationRequest struct
+type PolicyEvaluationRequest struct {
+	ClientID         *string                       `json:"clientId,omitempty"`
+	Context          *map[string]map[string]string `json:"context,omitempty"`
+	Entitlements     *bool                         `json:"entitlements,omitempty"`
+	Resources        *[]ResourceRepresentation     `json:"resources,omitempty"`
+	ResourceServerID *string                       `json:"resourceServerId,omitempty"`
+	RoleIds          *[]string                     `json:"roleIds,omitempty"`
+	UserID           *string                       `json:"userId,omitempty"`
+}
+
+// PolicyEvaluationResponse struct
+type PolicyEvaluationResponse struct {
+	Entitlements *bool                             `json:"entitlements,omitempty"`
+	Results      *[]EvaluationResultRepresentation `json:"results,omitempty"`
+	Rpt          *map[string]interface{}           `json:"rpt,omitempty"`
+	Status       *string                           `json:"status,omitempty"`
+}
+
 // PolicyRepresentation struct
 type PolicyRepresentation struct {
 	Config           *map[string]interface{} `json:"config,omitempty"`
@@ -394,6 +431,14 @@ type PolicyRepresentation struct {
 	Type             *string                 `json:"type,omitempty"`
 }
 
+// PolicyResultRepresentation struct
+type PolicyResultRepresentation struct {
+	AssociatedPolicies *[]PolicyResultRepresentation `json:"associatedPolicies,omitempty"`
+	Policy             *PolicyRepresentation         `json:"policy,omitempty"`
+	Scopes             *[]string                     `json:"scopes,omitempty"`
+	Status             *string                       `json:"status,omitempty"`
+}
+
 // ProfileInfoRepresentation struct
 type ProfileInfoRepresentation struct {
 	DisabledFeatures *[]string `json:"disabledFeatures,omitempty"`
@@ -448,6 +493,7 @@ type RealmRepresentation struct {
 	BruteForceProtected                 *bool                                   `json:"bruteForceProtected,omitempty"`
 	ClientAuthenticationFlow            *string                                 `json:"clientAuthenticationFlow,omitempty"`
 	ClientScopeMappings                 *map[string]interface{}                 `json:"clientScopeMappings,omitempty"`
+	ClientScopes                        *[]ClientScopeRepresentation            `json:"clientScopes,omitempty"`
 	ClientTemplates                     *[]ClientTemplateRepresentation         `json:"clientTemplates,omitempty"`
 	Clients                             *[]ClientRepresentation                 `json:"clients,omitempty"`
 	Components                          *ComponentsExportRepresentation         `json:"components,omitempty"`
@@ -534,20 +580,25 @@ type ResourceOwnerRepresentation struct {
 
 // ResourceRepresentation struct
 type ResourceRepresentation struct {
-	ID          *string                      `json:"id,omitempty"`
-	IconURI     *string                      `json:"icon_uri,omitempty"`
-	Name        *string                      `json:"name,omitempty"`
-	Owner       *ResourceOwnerRepresentation `json:"owner,omitempty"`
-	Policies    *[]PolicyRepresentation      `json:"policies,omitempty"`
-	Scopes      *[]ScopeRepresentation       `json:"scopes,omitempty"`
-	Type        *string                      `json:"type,omitempty"`
-	TypedScopes *[]ScopeRepresentation       `json:"typedScopes,omitempty"`
-	URI         *string                      `json:"uri,omitempty"`
+	Attributes         *map[string][]string         `json:"attributes,omitempty"`
+	DisplayName        *string                      `json:"displayName,omitempty"`
+	ID                 *string                      `json:"_id,omitempty"`
+	IconURI            *string                      `json:"icon_uri,omitempty"`
+	Name               *string                      `json:"name,omitempty"`
+	Owner              *ResourceOwnerRepresentation `json:"owner,omitempty"`
+	OwnerManagedAccess *bool                        `json:"ownerManagedAccess,omitempty"`
+	Policies           *[]PolicyRepresentation      `json:"policies,omitempty"`
+	Scopes             *[]ScopeRepresentation       `json:"scopes,omitempty"`
+	Type               *string                      `json:"type,omitempty"`
+	TypedScopes        *[]ScopeRepresentation       `json:"typedScopes,omitempty"`
+	URI                *string                      `json:"uri,omitempty"`
+	URIs               *[]string                    `json:"uris,omitempty"`
 }
 
 // ResourceServerRepresentation struct
 type ResourceServerRepresentation struct {
 	AllowRemoteResourceManagement *bool                     `json:"allowRemoteResourceManagement,omitempty"`
+	DecisionStrategy              *string                   `json:"decisionStrategy,omitempty"`
 	ClientID                      *string                   `json:"clientId,omitempty"`
 	ID                            *string                   `json:"id,omitempty"`
 	Name                          *string                   `json:"name,omitempty"`
@@ -591,11 +642,12 @@ type ScopeMappingRepresentation struct {
 
 // ScopeRepresentation struct
 type ScopeRepresentation struct {
-	IconURI   *string                   `json:"iconUri,omitempty"`
-	ID        *string                   `json:"id,omitempty"`
-	Name      *string                   `json:"name,omitempty"`
-	Policies  *[]PolicyRepresentation   `json:"policies,omitempty"`
-	Resources *[]ResourceRepresentation `json:"resources,omitempty"`
+	DisplayName *string                   `json:"displayName,omitempty"`
+	IconURI     *string                   `json:"iconUri,omitempty"`
+	ID          *string                   `json:"id,omitempty"`
+	Name        *string                   `json:"name,omitempty"`
+	Policies    *[]PolicyRepresentation   `json:"policies,omitempty"`
+	Resources   *[]ResourceRepresentation `json:"resources,omitempty"`
 }
 
 // ServerInfoRepresentation struct
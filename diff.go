@@ -0,0 +1,436 @@
+package keycloak
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeType tells how a value differs between two representations.
+type ChangeType string
+
+// Types of the changes.
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is a difference between two representations. Path locates the value: the fields are separated by dots,
+// the elements of the keyed lists are given by their key in brackets, e.g. clients[my-app].redirectUris, and
+// those of the other lists by their index. From and To are the JSON values, From being nil for an added value
+// and To for a removed one.
+type Change struct {
+	Type ChangeType  `json:"type"`
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// String formats the change as a line, e.g. "~ clients[my-app].enabled: true -> false".
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %s", c.Path, jsonString(c.To))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %s", c.Path, jsonString(c.From))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, jsonString(c.From), jsonString(c.To))
+	}
+}
+
+// Changes are the differences between two representations.
+type Changes []Change
+
+// String formats the changes, one per line.
+func (c Changes) String() string {
+	var b strings.Builder
+	for _, change := range c {
+		b.WriteString(change.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// listKeys are the fields identifying the elements of the lists of objects, e.g. the clients by client id or the
+// components by name and provider type. The first one which all the elements have is used.
+var listKeys = [][]string{
+	{"clientId"},
+	{"alias"},
+	{"name", "providerType"},
+	{"name"},
+	{"username"},
+}
+
+// Diff returns the changes turning a into b, which are representations such as ClientRepresentation or
+// RealmRepresentation. A nil pointer is not set while a pointer to a zero value is, so that they differ. The
+// lists of objects are matched by key where they have one (see Change), and the lists of scalars are compared
+// regardless of their order, like the sets Keycloak stores them as.
+func Diff(a interface{}, b interface{}) (Changes, error) {
+	var from, to interface{}
+	if err := toJSONValue(a, &from); err != nil {
+		return nil, err
+	}
+	if err := toJSONValue(b, &to); err != nil {
+		return nil, err
+	}
+	var changes = Changes{}
+	diffValues("", from, to, &changes)
+	return changes, nil
+}
+
+func toJSONValue(rep interface{}, value *interface{}) error {
+	var bytes, err = json.Marshal(rep)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, value)
+}
+
+func diffValues(path string, from interface{}, to interface{}, changes *Changes) {
+	var fromMap, fromIsMap = from.(map[string]interface{})
+	var toMap, toIsMap = to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		for _, key := range unionKeys(fromMap, toMap) {
+			var fromValue, inFrom = fromMap[key]
+			var toValue, inTo = toMap[key]
+			switch {
+			case !inTo:
+				*changes = append(*changes, Change{Type: ChangeRemoved, Path: fieldPath(path, key), From: fromValue})
+			case !inFrom:
+				*changes = append(*changes, Change{Type: ChangeAdded, Path: fieldPath(path, key), To: toValue})
+			default:
+				diffValues(fieldPath(path, key), fromValue, toValue, changes)
+			}
+		}
+		return
+	}
+
+	var fromList, fromIsList = from.([]interface{})
+	var toList, toIsList = to.([]interface{})
+	if fromIsList && toIsList {
+		diffLists(path, fromList, toList, changes)
+		return
+	}
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, Change{Type: ChangeModified, Path: path, From: from, To: to})
+	}
+}
+
+func diffLists(path string, from []interface{}, to []interface{}, changes *Changes) {
+	if isScalarList(from) && isScalarList(to) {
+		if !reflect.DeepEqual(sortScalars(from), sortScalars(to)) {
+			*changes = append(*changes, Change{Type: ChangeModified, Path: path, From: from, To: to})
+		}
+		return
+	}
+
+	var fields = listKey(from, to)
+	if fields == nil {
+		for i := 0; i < len(from) || i < len(to); i++ {
+			var elementPath = fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(to):
+				*changes = append(*changes, Change{Type: ChangeRemoved, Path: elementPath, From: from[i]})
+			case i >= len(from):
+				*changes = append(*changes, Change{Type: ChangeAdded, Path: elementPath, To: to[i]})
+			default:
+				diffValues(elementPath, from[i], to[i], changes)
+			}
+		}
+		return
+	}
+
+	var toByKey = map[string]interface{}{}
+	for _, element := range to {
+		toByKey[elementKey(element, fields)] = element
+	}
+	var fromKeys = map[string]bool{}
+	for _, element := range from {
+		var key = elementKey(element, fields)
+		fromKeys[key] = true
+		if toElement, ok := toByKey[key]; ok {
+			diffValues(path+"["+key+"]", element, toElement, changes)
+		} else {
+			*changes = append(*changes, Change{Type: ChangeRemoved, Path: path + "[" + key + "]", From: element})
+		}
+	}
+	for _, element := range to {
+		if key := elementKey(element, fields); !fromKeys[key] {
+			*changes = append(*changes, Change{Type: ChangeAdded, Path: path + "[" + key + "]", To: element})
+		}
+	}
+}
+
+// listKey returns the fields identifying the elements of the lists, which must all be objects having a value
+// for these fields unique within their list, or nil.
+func listKey(lists ...[]interface{}) []string {
+	for _, fields := range listKeys {
+		var ok = true
+		for _, list := range lists {
+			ok = ok && hasUniqueKeys(list, fields)
+		}
+		if ok {
+			return fields
+		}
+	}
+	return nil
+}
+
+func hasUniqueKeys(elements []interface{}, fields []string) bool {
+	var keys = map[string]bool{}
+	for _, element := range elements {
+		var object, ok = element.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, field := range fields {
+			if _, ok := object[field].(string); !ok {
+				return false
+			}
+		}
+		var key = elementKey(element, fields)
+		if keys[key] {
+			return false
+		}
+		keys[key] = true
+	}
+	return true
+}
+
+func elementKey(element interface{}, fields []string) string {
+	var values []string
+	for _, field := range fields {
+		values = append(values, element.(map[string]interface{})[field].(string))
+	}
+	return strings.Join(values, "/")
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func fieldPath(path string, key string) string {
+	switch {
+	case !identifier.MatchString(key):
+		return path + "[" + strconv.Quote(key) + "]"
+	case path == "":
+		return key
+	default:
+		return path + "." + key
+	}
+}
+
+func unionKeys(a map[string]interface{}, b map[string]interface{}) []string {
+	var keys []string
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isScalarList(values []interface{}) bool {
+	for _, value := range values {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+func sortScalars(values []interface{}) []string {
+	var res = []string{}
+	for _, value := range values {
+		res = append(res, jsonString(value))
+	}
+	sort.Strings(res)
+	return res
+}
+
+func jsonString(value interface{}) string {
+	var bytes, _ = json.Marshal(value)
+	return string(bytes)
+}
+
+// UnifiedDiff returns the unified diff turning a into b, named fromName and toName in the header, or an empty
+// string if they do not differ. The representations are compared as indented JSON, with the keyed lists sorted by
+// key and the lists of scalars sorted, so that their order does not matter.
+func UnifiedDiff(a interface{}, b interface{}, fromName string, toName string) (string, error) {
+	var from, to interface{}
+	if err := toJSONValue(a, &from); err != nil {
+		return "", err
+	}
+	if err := toJSONValue(b, &to); err != nil {
+		return "", err
+	}
+	var fromLines, toLines = canonicalLines(from), canonicalLines(to)
+	var hunks = unifiedHunks(editScript(fromLines, toLines), 3)
+	if hunks == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", fromName, toName, hunks), nil
+}
+
+// canonicalLines returns the lines of the indented JSON of a value, whose lists are sorted.
+func canonicalLines(value interface{}) []string {
+	var bytes, _ = json.MarshalIndent(sortLists(value), "", "  ")
+	return strings.Split(string(bytes), "\n")
+}
+
+func sortLists(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var res = map[string]interface{}{}
+		for key, element := range v {
+			res[key] = sortLists(element)
+		}
+		return res
+	case []interface{}:
+		var res = []interface{}{}
+		for _, element := range v {
+			res = append(res, sortLists(element))
+		}
+		if isScalarList(res) {
+			sort.SliceStable(res, func(i, j int) bool { return jsonString(res[i]) < jsonString(res[j]) })
+		} else if fields := listKey(res); fields != nil {
+			sort.SliceStable(res, func(i, j int) bool { return elementKey(res[i], fields) < elementKey(res[j], fields) })
+		}
+		return res
+	default:
+		return value
+	}
+}
+
+// edit is a line of an edit script: kept (' '), deleted ('-') or inserted ('+').
+type edit struct {
+	op   byte
+	line string
+}
+
+// editScript returns the shortest edit script turning a into b, computed with the Myers algorithm.
+func editScript(a []string, b []string) []edit {
+	var n, m = len(a), len(b)
+	var max = n + m
+	var v = make([]int, 2*max+2)
+	var trace [][]int
+
+	// Find the furthest reaching paths of each number of edits d, until one reaches the end of both lists.
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int{}, v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			var y = x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the paths back from the end.
+	var edits []edit
+	var x, y = n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		var v = trace[d]
+		var k = x - y
+		var prevK = k - 1
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		}
+		var prevX = v[max+prevK]
+		var prevY = prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{'+', b[y-1]})
+			} else {
+				edits = append(edits, edit{'-', a[x-1]})
+			}
+			x, y = prevX, prevY
+		}
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// unifiedHunks formats the changes of an edit script as the hunks of a unified diff, with context lines of
+// kept lines around them.
+func unifiedHunks(edits []edit, context int) string {
+	var b strings.Builder
+	var fromLine, toLine = 1, 1
+	for start := 0; start < len(edits); {
+		var first = start
+		for first < len(edits) && edits[first].op == ' ' {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		// Extend the hunk until the kept lines separating two changes exceed twice the context.
+		var end = first
+		for i := first; i < len(edits); i++ {
+			if edits[i].op != ' ' {
+				end = i + 1
+			} else if i-end >= 2*context {
+				break
+			}
+		}
+		var hunkStart = first - context
+		if hunkStart < start {
+			hunkStart = start
+		}
+		var hunkEnd = end + context
+		if hunkEnd > len(edits) {
+			hunkEnd = len(edits)
+		}
+
+		fromLine, toLine = fromLine+hunkStart-start, toLine+hunkStart-start
+		var fromCount, toCount int
+		var lines strings.Builder
+		for _, e := range edits[hunkStart:hunkEnd] {
+			lines.WriteByte(e.op)
+			lines.WriteString(e.line)
+			lines.WriteString("\n")
+			if e.op != '+' {
+				fromCount++
+			}
+			if e.op != '-' {
+				toCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n%s", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount), lines.String())
+		fromLine, toLine = fromLine+fromCount, toLine+toCount
+		start = hunkEnd
+	}
+	return b.String()
+}
+
+func hunkRange(line int, count int) string {
+	if count == 0 {
+		line--
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
@@ -0,0 +1,131 @@
+package keycloak
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	var enabled, disabled = true, false
+	var app, api, ldap = "app", "api", "ldap"
+	var from = RealmRepresentation{
+		Clients: &[]ClientRepresentation{
+			{ClientID: &app, Enabled: &enabled, RedirectUris: &[]string{"https://a", "https://b"}},
+			{ClientID: &api},
+		},
+		Components: &ComponentsExportRepresentation{
+			UserStorageProviderType: {{Name: &ldap, Config: &MultivaluedHashMap{"connectionUrl": {"ldap://a"}}}},
+		},
+	}
+	var to = RealmRepresentation{
+		Clients: &[]ClientRepresentation{
+			{ClientID: &api, Enabled: &disabled},
+			{ClientID: &app, RedirectUris: &[]string{"https://b", "https://a"}},
+		},
+		Components: &ComponentsExportRepresentation{
+			UserStorageProviderType: {{Name: &ldap, Config: &MultivaluedHashMap{"connectionUrl": {"ldap://b"}}}},
+		},
+		Enabled: &disabled,
+	}
+
+	var changes, err = Diff(from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, Changes{
+		{Type: ChangeRemoved, Path: "clients[app].enabled", From: true},
+		{Type: ChangeAdded, Path: "clients[api].enabled", To: false},
+		{Type: ChangeModified, Path: `components["org.keycloak.storage.UserStorageProvider"][ldap].config.connectionUrl`, From: []interface{}{"ldap://a"}, To: []interface{}{"ldap://b"}},
+		{Type: ChangeAdded, Path: "enabled", To: false},
+	}, changes)
+	assert.Equal(t, `- clients[app].enabled: true
++ clients[api].enabled: false
+~ components["org.keycloak.storage.UserStorageProvider"][ldap].config.connectionUrl: ["ldap://a"] -> ["ldap://b"]
++ enabled: false
+`, changes.String())
+
+	changes, err = Diff(from, from)
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+
+	var name = "user"
+	changes, err = Diff(RoleRepresentation{Name: &name}, RoleRepresentation{Name: &name, Composites: &RoleRepresentationComposites{}})
+	assert.Nil(t, err)
+	assert.Equal(t, Changes{{Type: ChangeAdded, Path: "composites", To: map[string]interface{}{}}}, changes)
+
+	_, err = Diff(func() {}, from)
+	assert.NotNil(t, err)
+}
+
+func TestDiffUnkeyedLists(t *testing.T) {
+	var flow = "browser"
+	var first, second = "auth-cookie", "identity-provider-redirector"
+	var from = AuthenticationFlowRepresentation{Alias: &flow, AuthenticationExecutions: &[]AuthenticationExecutionExportRepresentation{{Authenticator: &first}}}
+	var to = AuthenticationFlowRepresentation{Alias: &flow, AuthenticationExecutions: &[]AuthenticationExecutionExportRepresentation{{Authenticator: &second}, {Authenticator: &first}}}
+
+	var changes, err = Diff(from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, Changes{
+		{Type: ChangeModified, Path: "authenticationExecutions[0].authenticator", From: first, To: second},
+		{Type: ChangeAdded, Path: "authenticationExecutions[1]", To: map[string]interface{}{"authenticator": first}},
+	}, changes)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	var app, api = "app", "api"
+	var enabled, disabled = true, false
+	var from = []ClientRepresentation{{ClientID: &app, Enabled: &enabled}, {ClientID: &api}}
+	var to = []ClientRepresentation{{ClientID: &api}, {ClientID: &app, Enabled: &disabled}}
+
+	var diff, err = UnifiedDiff(from, to, "staging", "production")
+	assert.Nil(t, err)
+	assert.Equal(t, `--- staging
++++ production
+@@ -4,6 +4,6 @@
+   },
+   {
+     "clientId": "app",
+-    "enabled": true
++    "enabled": false
+   }
+ ]
+`, diff)
+
+	diff, err = UnifiedDiff(from, from, "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, "", diff)
+}
+
+func TestEditScript(t *testing.T) {
+	var lines = func(edits []edit) string {
+		var res string
+		for _, e := range edits {
+			res += string(e.op) + e.line + "\n"
+		}
+		return res
+	}
+	assert.Equal(t, " a\n-b\n c\n+d\n", lines(editScript([]string{"a", "b", "c"}, []string{"a", "c", "d"})))
+	assert.Equal(t, "+a\n", lines(editScript(nil, []string{"a"})))
+	assert.Equal(t, "", lines(editScript(nil, nil)))
+
+	var from, to []string
+	for i := 0; i < 20; i++ {
+		from = append(from, string(rune('a'+i)))
+		to = append(to, string(rune('a'+i)))
+	}
+	to[1], to[18] = "B", "S"
+	assert.Equal(t, `@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -16,5 +16,5 @@
+ p
+ q
+ r
+-s
++S
+ t
+`, unifiedHunks(editScript(from, to), 3))
+}
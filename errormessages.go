@@ -1,7 +1,13 @@
 package keycloak
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/h2non/gentleman.v2"
 )
 
 // Constants for error management
@@ -17,25 +23,163 @@ const (
 	MsgErrUnknownResponseStatusCode = "unknownResponseStatusCode"
 	MsgErrExistingValue             = "existing"
 	MsgErrReadOnly                  = "readOnlyValue"
+	MsgErrNotFound                  = "notFound"
+
+	EvenParams                 = "key/valParametersShouldBeEven"
+	SearchWithFilters          = "searchCannotBeCombinedWithOtherFilters"
+	ExactWithoutFilter         = "exactRequiresAFilter"
+	SearchWithoutClientID      = "searchRequiresClientID"
+	NegativePaging             = "firstAndMaxCannotBeNegative"
+	NegativeLifespan           = "lifespanCannotBeNegative"
+	RedirectURIWithoutClientID = "redirectURIRequiresClientID"
+	DateToBeforeDateFrom       = "dateToCannotBeBeforeDateFrom"
+	MatchingURIWithoutURI      = "matchingURIRequiresURI"
+	TokenProviderURL           = "tokenProviderURL"
+	APIURL                     = "APIURL"
+	TokenMsg                   = "token"
+	Response                   = "response"
+	AccessToken                = "accessToken"
+	ClientAssertion            = "clientAssertion"
+	Representation             = "representation"
+	CheckpointFile             = "checkpointFile"
+	ClientNotFound             = "clientNotFound"
+	GroupNotFound              = "groupNotFound"
+	InvalidGroupPath           = "invalidGroupPath"
+	Metadata                   = "metadata"
+	PolicyType                 = "policyType"
+	InvalidIfResourceExists    = "invalidIfResourceExists"
+	FlowAlias                  = "flowAlias"
+	BuiltInFlow                = "builtInFlow"
+	FlowProvider               = "flowProvider"
+	AuthenticationExecutions   = "authenticationExecutions"
+	InvalidFlowBinding         = "invalidFlowBinding"
+	ExecutionNotFound          = "executionNotFound"
+)
 
-	EvenParams       = "key/valParametersShouldBeEven"
-	TokenProviderURL = "tokenProviderURL"
-	APIURL           = "APIURL"
-	TokenMsg         = "token"
-	Response         = "response"
-	AccessToken      = "accessToken"
+// Sentinel errors, matched with errors.Is by the HTTPError having the corresponding status.
+var (
+	ErrBadRequest   = errors.New("badRequest")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("notFound")
+	ErrConflict     = errors.New("conflict")
 )
 
-// HTTPError is returned when an error occured while contacting the keycloak instance.
+var sentinelStatuses = map[error]int{
+	ErrBadRequest:   http.StatusBadRequest,
+	ErrUnauthorized: http.StatusUnauthorized,
+	ErrForbidden:    http.StatusForbidden,
+	ErrNotFound:     http.StatusNotFound,
+	ErrConflict:     http.StatusConflict,
+}
+
+// Error is returned when a call fails on the client side: invalid parameters, transport failure, response
+// which cannot be decoded... Code is one of the MsgErr constants, Detail qualifies it and Err is the cause,
+// if any.
+type Error struct {
+	Code   string
+	Detail string
+	Err    error
+}
+
+func newError(code string, detail string, err error) error {
+	return Error{Code: code, Detail: detail, Err: err}
+}
+
+func (e Error) Error() string {
+	var msg = e.Code
+	if e.Detail != "" {
+		msg += "." + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the cause of the error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// Cause returns the cause of the error, for github.com/pkg/errors.Cause.
+func (e Error) Cause() error {
+	return e.Err
+}
+
+// HTTPError is returned when the keycloak instance answered with an error status. Message is the raw body
+// of the response, the fields of Keycloak's JSON error bodies are parsed into ErrorMessage, ErrorCode and
+// ErrorDescription.
 type HTTPError struct {
 	HTTPStatus int
 	Message    string
+
+	Method           string
+	Path             string
+	Realm            string
+	ErrorMessage     string
+	ErrorCode        string
+	ErrorDescription string
+}
+
+// newHTTPError builds the HTTPError matching the response resp.
+func newHTTPError(resp *gentleman.Response) HTTPError {
+	var e = HTTPError{
+		HTTPStatus: resp.StatusCode,
+		Message:    string(resp.Bytes()),
+	}
+	if resp.RawRequest != nil {
+		e.Method = resp.RawRequest.Method
+		e.Path = resp.RawRequest.URL.Path
+		e.Realm = realmFromPath(e.Path)
+	}
+
+	var body struct {
+		ErrorMessage     string `json:"errorMessage"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if json.Unmarshal([]byte(e.Message), &body) == nil {
+		e.ErrorMessage, e.ErrorCode, e.ErrorDescription = body.ErrorMessage, body.Error, body.ErrorDescription
+	}
+	return e
+}
+
+// realmFromPath returns the realm targeted by an admin or token endpoint path, or "" if there is none.
+func realmFromPath(path string) string {
+	var segments = strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "realms" {
+			return segments[i+1]
+		}
+	}
+	return ""
 }
 
 func (e HTTPError) Error() string {
 	return fmt.Sprintf("%d:%s", e.HTTPStatus, e.Message)
 }
 
+// Detail returns the most specific explanation given by Keycloak, or the raw body if it did not give any.
+func (e HTTPError) Detail() string {
+	switch {
+	case e.ErrorMessage != "":
+		return e.ErrorMessage
+	case e.ErrorDescription != "":
+		return e.ErrorDescription
+	case e.ErrorCode != "":
+		return e.ErrorCode
+	default:
+		return e.Message
+	}
+}
+
+// Is reports whether target is the sentinel error matching the status of e, e.g. ErrNotFound for a 404.
+func (e HTTPError) Is(target error) bool {
+	var status, ok = sentinelStatuses[target]
+	return ok && status == e.HTTPStatus
+}
+
 // ClientDetailedError struct
 type ClientDetailedError struct {
 	HTTPStatus int
@@ -0,0 +1,303 @@
+package keycloak
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// DefaultEventStreamInterval is the delay between two polls of an EventStream when none is configured.
+const DefaultEventStreamInterval = 10 * time.Second
+
+// EventStreamConfig configures an EventStream.
+type EventStreamConfig struct {
+	// Realm is the realm whose events are streamed.
+	Realm string
+	// Events and AdminEvents select the kinds of events streamed along with their filters. Their paging and date
+	// fields are managed by the stream. If both are nil, all the login events are streamed.
+	Events      *EventQuery
+	AdminEvents *AdminEventQuery
+	// Interval is the delay between two polls (DefaultEventStreamInterval if 0).
+	Interval time.Duration
+	// PageSize is the number of events fetched per request (DefaultPageSize if 0).
+	PageSize int
+	// Store persists the position of the stream (in memory if nil).
+	Store CheckpointStore
+	// Name tells apart the streams sharing a store for the same realm.
+	Name string
+	// StartAt skips the events older than it when the stream has no checkpoint yet (the creation of the stream if
+	// zero). A date in the past replays the events Keycloak kept since then, which the first poll lists at once.
+	StartAt time.Time
+}
+
+// StreamEvent is an event delivered by an EventStream. Exactly one of Event and AdminEvent is set.
+type StreamEvent struct {
+	Realm      string
+	Event      *EventRepresentation
+	AdminEvent *AdminEventRepresentation
+}
+
+// GetTime returns the time of the event.
+func (e StreamEvent) GetTime() time.Time {
+	if e.AdminEvent != nil {
+		return e.AdminEvent.GetTime()
+	}
+	if e.Event != nil {
+		return e.Event.GetTime()
+	}
+	return time.Time{}
+}
+
+// EventStream tails the events of a realm. It polls Keycloak at regular intervals and delivers the new events in
+// chronological order, the login events first. After each event is delivered, its time and hash are saved in
+// the checkpoint store, so that each event is delivered once even across restarts and when several events share
+// the same millisecond, including distinct events with the same content, which are counted. An event may only be
+// delivered twice if the process stops between its delivery and the save of the checkpoint.
+type EventStream struct {
+	client       KeycloakAdmin
+	provideToken func() (string, error)
+	config       EventStreamConfig
+}
+
+// pendingEvent is an event fetched by a poll and not yet delivered.
+type pendingEvent struct {
+	time  int64
+	hash  string
+	event StreamEvent
+}
+
+// NewEventStream returns a stream of the events of config.Realm. provideToken is called before each poll, e.g.
+// TokenProvider.ProvideToken, as the stream may outlive an access token. The stream stops when the context bound
+// to client with WithContext is done.
+func NewEventStream(client KeycloakAdmin, provideToken func() (string, error), config EventStreamConfig) *EventStream {
+	if config.Events == nil && config.AdminEvents == nil {
+		config.Events = &EventQuery{}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultEventStreamInterval
+	}
+	if config.PageSize <= 0 {
+		config.PageSize = DefaultPageSize
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryCheckpointStore()
+	}
+	if config.StartAt.IsZero() {
+		config.StartAt = time.Now()
+	}
+	return &EventStream{client: client, provideToken: provideToken, config: config}
+}
+
+// Run polls the events until the context of the client is done, and calls handler for each new event. It returns
+// the first error encountered, either returned by handler or while fetching the events or saving the checkpoint.
+// The event handler failed on is delivered again by the next run.
+func (s *EventStream) Run(handler func(StreamEvent) error) error {
+	var ctx = contextOf(s.client)
+	for {
+		if err := s.Poll(handler); err != nil {
+			return err
+		}
+
+		var timer = time.NewTimer(s.config.Interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// RunChannel is like Run but sends the events to ch. An event is delivered once ch accepted it.
+func (s *EventStream) RunChannel(ch chan<- StreamEvent) error {
+	var ctx = contextOf(s.client)
+	return s.Run(func(e StreamEvent) error {
+		select {
+		case ch <- e:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// Poll fetches the events which occurred since the last checkpoint once, and calls handler for each of them.
+func (s *EventStream) Poll(handler func(StreamEvent) error) error {
+	var accessToken, err = s.provideToken()
+	if err != nil {
+		return err
+	}
+
+	if s.config.Events != nil {
+		if err = s.poll(accessToken, "events", s.fetchEvents, handler); err != nil {
+			return err
+		}
+	}
+	if s.config.AdminEvents != nil {
+		if err = s.poll(accessToken, "admin-events", s.fetchAdminEvents, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *EventStream) poll(accessToken string, kind string, fetch func(string, Checkpoint) ([]pendingEvent, error), handler func(StreamEvent) error) error {
+	var key = s.config.Realm + "/" + kind
+	if s.config.Name != "" {
+		key = s.config.Name + "/" + key
+	}
+
+	var checkpoint, err = s.config.Store.Load(key)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Time == 0 && !s.config.StartAt.IsZero() {
+		checkpoint.Time = toMillis(s.config.StartAt)
+	}
+
+	events, err := fetch(accessToken, checkpoint)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		if err = handler(e.event); err != nil {
+			return err
+		}
+		if e.time > checkpoint.Time || checkpoint.Hashes == nil {
+			checkpoint = Checkpoint{Time: e.time, Hashes: map[string]int{}}
+		}
+		checkpoint.Hashes[e.hash]++
+		if err = s.config.Store.Save(key, checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *EventStream) fetchEvents(accessToken string, checkpoint Checkpoint) ([]pendingEvent, error) {
+	var query = *s.config.Events
+	query.DateFrom, query.DateTo = checkpointDay(checkpoint), time.Time{}
+
+	return s.collect(checkpoint, func(first, max int) ([]pendingEvent, error) {
+		query.First, query.Max = first, max
+		var events, err = s.client.GetEvents(accessToken, s.config.Realm, query)
+		var page = make([]pendingEvent, len(events))
+		for i := range events {
+			var e = events[i]
+			page[i] = newPendingEvent(e.Time, e, StreamEvent{Realm: s.config.Realm, Event: &e})
+		}
+		return page, err
+	})
+}
+
+func (s *EventStream) fetchAdminEvents(accessToken string, checkpoint Checkpoint) ([]pendingEvent, error) {
+	var query = *s.config.AdminEvents
+	query.DateFrom, query.DateTo = checkpointDay(checkpoint), time.Time{}
+
+	return s.collect(checkpoint, func(first, max int) ([]pendingEvent, error) {
+		query.First, query.Max = first, max
+		var events, err = s.client.GetAdminEvents(accessToken, s.config.Realm, query)
+		var page = make([]pendingEvent, len(events))
+		for i := range events {
+			var e = events[i]
+			page[i] = newPendingEvent(e.Time, e, StreamEvent{Realm: s.config.Realm, AdminEvent: &e})
+		}
+		return page, err
+	})
+}
+
+func newPendingEvent(millis *int64, rep interface{}, event StreamEvent) pendingEvent {
+	var t int64
+	if millis != nil {
+		t = *millis
+	}
+	return pendingEvent{time: t, hash: hashEvent(rep), event: event}
+}
+
+// collect lists with fetch, most recent first, the events since the checkpoint, and returns those not delivered
+// yet in chronological order. The events occurring meanwhile shift the pages, so each page is fetched from the
+// first event of the oldest millisecond listed so far: the more recent events it lists again are skipped, and
+// the events of that millisecond are replaced by those of the page, which keeps their count exact.
+func (s *EventStream) collect(checkpoint Checkpoint, fetch func(first, max int) ([]pendingEvent, error)) ([]pendingEvent, error) {
+	var listed []pendingEvent
+	// boundary is the index in listed, and first the offset in the listing, of the first event of the oldest
+	// millisecond listed.
+	var boundary, first int
+	for {
+		var max = s.config.PageSize + len(listed) - boundary
+		var page, err = fetch(first, max)
+		if err != nil {
+			return nil, err
+		}
+		var i int
+		if len(listed) > 0 {
+			for i < len(page) && page[i].time > listed[len(listed)-1].time {
+				i++
+			}
+			if i == len(page) {
+				if len(page) < max {
+					break
+				}
+				first += i
+				continue
+			}
+			first += i
+			listed = listed[:boundary]
+		}
+
+		var base, done = len(listed), len(page) < max
+		for _, e := range page[i:] {
+			if e.time < checkpoint.Time {
+				done = true
+				break
+			}
+			listed = append(listed, e)
+		}
+		if done || len(listed) == base {
+			break
+		}
+		boundary = len(listed) - 1
+		for boundary > base && listed[boundary-1].time == listed[len(listed)-1].time {
+			boundary--
+		}
+		first += boundary - base
+	}
+
+	var delivered = map[string]int{}
+	for hash, count := range checkpoint.Hashes {
+		delivered[hash] = count
+	}
+	var events []pendingEvent
+	for i := len(listed) - 1; i >= 0; i-- {
+		var e = listed[i]
+		if e.time == checkpoint.Time && delivered[e.hash] > 0 {
+			delivered[e.hash]--
+			continue
+		}
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].time < events[j].time })
+	return events, nil
+}
+
+func hashEvent(rep interface{}) string {
+	var content, _ = json.Marshal(rep)
+	var sum = sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// checkpointDay returns the lower bound of the dates to query after checkpoint. As Keycloak compares days in its
+// own time zone, it starts the day before.
+func checkpointDay(checkpoint Checkpoint) time.Time {
+	if checkpoint.Time == 0 {
+		return time.Time{}
+	}
+	return fromMillis(&checkpoint.Time).AddDate(0, 0, -1)
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,199 @@
+package keycloak
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// eventSource serves its login events most recent first, as Keycloak does.
+type eventSource struct {
+	KeycloakAdmin
+	ctx    context.Context
+	events []EventRepresentation
+	// fetched is called after each page is served, e.g. to add events while the stream pages through them.
+	fetched func()
+}
+
+func (s *eventSource) Context() context.Context {
+	return s.ctx
+}
+
+func (s *eventSource) add(millis int64, typ string) {
+	s.events = append([]EventRepresentation{{Time: &millis, Type: &typ}}, s.events...)
+}
+
+func (s *eventSource) GetEvents(accessToken string, realmName string, query EventQuery) ([]EventRepresentation, error) {
+	var res = []EventRepresentation{}
+	for i := query.First; i < len(s.events) && i < query.First+query.Max; i++ {
+		res = append(res, s.events[i])
+	}
+	if s.fetched != nil {
+		s.fetched()
+	}
+	return res, nil
+}
+
+func staticToken() (string, error) {
+	return "token", nil
+}
+
+func collect(stream *EventStream) ([]string, error) {
+	var types []string
+	var err = stream.Poll(func(e StreamEvent) error {
+		types = append(types, *e.Event.Type)
+		return nil
+	})
+	return types, err
+}
+
+func TestEventStream(t *testing.T) {
+	var source = &eventSource{ctx: context.Background()}
+	var store = NewMemoryCheckpointStore()
+	var config = EventStreamConfig{Realm: "test", Store: store, PageSize: 2, StartAt: time.Unix(0, 0)}
+
+	source.add(1000, "A")
+	source.add(2000, "B")
+	source.add(2000, "C")
+	var types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "B", "C"}, types)
+
+	// A restarted stream resumes after the events delivered, including those sharing the last millisecond.
+	source.add(2000, "D")
+	source.add(3000, "E")
+	types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"D", "E"}, types)
+
+	types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Empty(t, types)
+
+	// The event the handler fails on is delivered again.
+	source.add(4000, "F")
+	source.add(5000, "G")
+	var failure = errors.New("failure")
+	err = NewEventStream(source, staticToken, config).Poll(func(e StreamEvent) error {
+		if *e.Event.Type == "G" {
+			return failure
+		}
+		return nil
+	})
+	assert.Equal(t, failure, err)
+	types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"G"}, types)
+}
+
+func TestEventStreamIdenticalEvents(t *testing.T) {
+	var source = &eventSource{ctx: context.Background()}
+	var store = NewMemoryCheckpointStore()
+	var config = EventStreamConfig{Realm: "test", Store: store, PageSize: 2, StartAt: time.Unix(0, 0)}
+
+	// Two failed logins of the same user at the same millisecond are distinct events.
+	source.add(1000, "LOGIN_ERROR")
+	source.add(1000, "LOGIN_ERROR")
+	source.add(1000, "LOGIN_ERROR")
+	var types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"LOGIN_ERROR", "LOGIN_ERROR", "LOGIN_ERROR"}, types)
+	checkpoint, _ := store.Load("test/events")
+	assert.Equal(t, int64(1000), checkpoint.Time)
+	assert.Equal(t, []int{3}, hashCounts(checkpoint))
+
+	source.add(1000, "LOGIN_ERROR")
+	types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"LOGIN_ERROR"}, types)
+
+	// The events occurring while the stream pages through the events shift the pages, which list some events
+	// again.
+	source.add(2000, "A")
+	source.add(2000, "A")
+	source.add(3000, "B")
+	source.add(3000, "B")
+	source.add(4000, "C")
+	var added bool
+	source.fetched = func() {
+		if !added {
+			source.add(5000, "D")
+			source.add(5000, "D")
+			source.add(5000, "D")
+			added = true
+		}
+	}
+	types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "A", "B", "B", "C"}, types)
+	source.fetched = nil
+	types, err = collect(NewEventStream(source, staticToken, config))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"D", "D", "D"}, types)
+}
+
+func hashCounts(checkpoint Checkpoint) []int {
+	var counts []int
+	for _, count := range checkpoint.Hashes {
+		counts = append(counts, count)
+	}
+	return counts
+}
+
+func TestEventStreamStartAt(t *testing.T) {
+	var source = &eventSource{ctx: context.Background()}
+	source.add(toMillis(time.Now().Add(-time.Hour)), "OLD")
+
+	// Without a checkpoint nor StartAt, the stream starts with the events occurring after its creation.
+	var stream = NewEventStream(source, staticToken, EventStreamConfig{Realm: "test"})
+	source.add(toMillis(time.Now().Add(time.Second)), "NEW")
+	var types, err = collect(stream)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"NEW"}, types)
+}
+
+func TestEventStreamRunChannel(t *testing.T) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	var source = &eventSource{ctx: ctx}
+	source.add(1000, "A")
+	source.add(2000, "B")
+
+	var ch = make(chan StreamEvent)
+	var done = make(chan error)
+	go func() {
+		done <- NewEventStream(source, staticToken, EventStreamConfig{Realm: "test", StartAt: time.Unix(0, 0)}).RunChannel(ch)
+	}()
+	assert.Equal(t, "A", *(<-ch).Event.Type)
+	assert.Equal(t, "B", *(<-ch).Event.Type)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "checkpoints")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	var path = filepath.Join(dir, "checkpoints.json")
+
+	var store = NewFileCheckpointStore(path)
+	checkpoint, err := store.Load("test/events")
+	assert.Nil(t, err)
+	assert.Equal(t, Checkpoint{}, checkpoint)
+
+	assert.Nil(t, store.Save("test/events", Checkpoint{Time: 42, Hashes: map[string]int{"h1": 1, "h2": 2}}))
+	assert.Nil(t, store.Save("test/admin-events", Checkpoint{Time: 7}))
+
+	checkpoint, err = NewFileCheckpointStore(path).Load("test/events")
+	assert.Nil(t, err)
+	assert.Equal(t, Checkpoint{Time: 42, Hashes: map[string]int{"h1": 1, "h2": 2}}, checkpoint)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = NewFileCheckpointStore(path).Load("test/events")
+	assert.Equal(t, MsgErrCannotUnmarshal, err.(Error).Code)
+}
@@ -0,0 +1,180 @@
+package keycloak
+
+import (
+	"encoding/json"
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	eventsPath       = "/auth/admin/realms/:realm/events"
+	eventsConfigPath = eventsPath + "/config"
+	adminEventsPath  = "/auth/admin/realms/:realm/admin-events"
+)
+
+// Operation types of the admin events
+const (
+	OperationTypeCreate = "CREATE"
+	OperationTypeUpdate = "UPDATE"
+	OperationTypeDelete = "DELETE"
+	OperationTypeAction = "ACTION"
+)
+
+// Resource types of the admin events
+const (
+	ResourceTypeRealm                  = "REALM"
+	ResourceTypeRealmRole              = "REALM_ROLE"
+	ResourceTypeRealmRoleMapping       = "REALM_ROLE_MAPPING"
+	ResourceTypeRealmScopeMapping      = "REALM_SCOPE_MAPPING"
+	ResourceTypeUser                   = "USER"
+	ResourceTypeGroup                  = "GROUP"
+	ResourceTypeGroupMembership        = "GROUP_MEMBERSHIP"
+	ResourceTypeClient                 = "CLIENT"
+	ResourceTypeClientRole             = "CLIENT_ROLE"
+	ResourceTypeClientRoleMapping      = "CLIENT_ROLE_MAPPING"
+	ResourceTypeClientScopeMapping     = "CLIENT_SCOPE_MAPPING"
+	ResourceTypeComponent              = "COMPONENT"
+	ResourceTypeIdentityProvider       = "IDENTITY_PROVIDER"
+	ResourceTypeIdentityProviderMapper = "IDENTITY_PROVIDER_MAPPER"
+	ResourceTypeProtocolMapper         = "PROTOCOL_MAPPER"
+	ResourceTypeAuthFlow               = "AUTH_FLOW"
+	ResourceTypeAuthExecution          = "AUTH_EXECUTION"
+	ResourceTypeAuthenticatorConfig    = "AUTHENTICATOR_CONFIG"
+	ResourceTypeRequiredAction         = "REQUIRED_ACTION"
+)
+
+// GetEvents returns the login events of the realm, filtered according to the query, most recent first.
+func (c *Client) GetEvents(accessToken string, realmName string, query EventQuery) ([]EventRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []EventRepresentation{}
+	var plugins = append(query.plugins(), url.Path(eventsPath), url.Param("realm", realmName))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// ClearEvents deletes all the login events of the realm.
+func (c *Client) ClearEvents(accessToken string, realmName string) error {
+	return c.delete(accessToken, url.Path(eventsPath), url.Param("realm", realmName))
+}
+
+// GetAdminEvents returns the admin events of the realm, filtered according to the query, most recent first.
+func (c *Client) GetAdminEvents(accessToken string, realmName string, query AdminEventQuery) ([]AdminEventRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []AdminEventRepresentation{}
+	var plugins = append(query.plugins(), url.Path(adminEventsPath), url.Param("realm", realmName))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// ClearAdminEvents deletes all the admin events of the realm.
+func (c *Client) ClearAdminEvents(accessToken string, realmName string) error {
+	return c.delete(accessToken, url.Path(adminEventsPath), url.Param("realm", realmName))
+}
+
+// GetEventsConfig returns the events configuration of the realm.
+func (c *Client) GetEventsConfig(accessToken string, realmName string) (RealmEventsConfigRepresentation, error) {
+	var resp = RealmEventsConfigRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(eventsConfigPath), url.Param("realm", realmName))
+	return resp, err
+}
+
+// UpdateEventsConfig updates the events configuration of the realm.
+func (c *Client) UpdateEventsConfig(accessToken string, realmName string, config RealmEventsConfigRepresentation) error {
+	return c.put(accessToken, url.Path(eventsConfigPath), url.Param("realm", realmName), body.JSON(config))
+}
+
+// GetTime returns the time of the event.
+func (e EventRepresentation) GetTime() time.Time {
+	return fromMillis(e.Time)
+}
+
+// GetTime returns the time of the admin event.
+func (e AdminEventRepresentation) GetTime() time.Time {
+	return fromMillis(e.Time)
+}
+
+// DecodeRepresentation unmarshals the representation of the resource affected by the admin event into v. The
+// representation is only recorded when the adminEventsDetailsEnabled option of the realm is set.
+func (e AdminEventRepresentation) DecodeRepresentation(v interface{}) error {
+	if e.Representation == nil {
+		return newError(MsgErrMissingParam, Representation, nil)
+	}
+	if err := json.Unmarshal([]byte(*e.Representation), v); err != nil {
+		return newError(MsgErrCannotUnmarshal, Representation, err)
+	}
+	return nil
+}
+
+// TypedRepresentation decodes the representation of the resource affected by the admin event according to its
+// resource type, e.g. into a *UserRepresentation for a USER event, or a *[]RoleRepresentation for a role mapping
+// event. The representations of the other resource types are decoded into generic maps and slices. It returns
+// nil if the event has no representation.
+func (e AdminEventRepresentation) TypedRepresentation() (interface{}, error) {
+	if e.Representation == nil {
+		return nil, nil
+	}
+
+	var v interface{}
+	var resourceType string
+	if e.ResourceType != nil {
+		resourceType = *e.ResourceType
+	}
+	switch resourceType {
+	case ResourceTypeRealm:
+		v = &RealmRepresentation{}
+	case ResourceTypeRealmRole, ResourceTypeClientRole:
+		v = &RoleRepresentation{}
+	case ResourceTypeRealmRoleMapping, ResourceTypeClientRoleMapping, ResourceTypeRealmScopeMapping, ResourceTypeClientScopeMapping:
+		v = &[]RoleRepresentation{}
+	case ResourceTypeUser:
+		v = &UserRepresentation{}
+	case ResourceTypeGroup, ResourceTypeGroupMembership:
+		v = &GroupRepresentation{}
+	case ResourceTypeClient:
+		v = &ClientRepresentation{}
+	case ResourceTypeComponent:
+		v = &ComponentRepresentation{}
+	case ResourceTypeIdentityProvider:
+		v = &IdentityProviderRepresentation{}
+	case ResourceTypeIdentityProviderMapper:
+		v = &IdentityProviderMapperRepresentation{}
+	case ResourceTypeProtocolMapper:
+		v = &ProtocolMapperRepresentation{}
+	case ResourceTypeAuthFlow:
+		v = &AuthenticationFlowRepresentation{}
+	case ResourceTypeAuthExecution:
+		v = &AuthenticationExecutionRepresentation{}
+	case ResourceTypeAuthenticatorConfig:
+		v = &AuthenticatorConfigRepresentation{}
+	case ResourceTypeRequiredAction:
+		v = &RequiredActionProviderRepresentation{}
+	default:
+		var generic interface{}
+		if err := e.DecodeRepresentation(&generic); err != nil {
+			return nil, err
+		}
+		return generic, nil
+	}
+
+	if err := e.DecodeRepresentation(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// fromMillis converts a Keycloak timestamp, in milliseconds since the epoch, to a time. A nil timestamp gives the
+// zero time.
+func fromMillis(millis *int64) time.Time {
+	if millis == nil {
+		return time.Time{}
+	}
+	return time.Unix(0, *millis*int64(time.Millisecond))
+}
@@ -0,0 +1,73 @@
+package keycloak
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventQueries(t *testing.T) {
+	var params url.Values
+	var path string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, params = r.URL.Path, r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	}))
+
+	var from = time.Date(2020, time.March, 1, 23, 0, 0, 0, time.UTC)
+	var _, err = client.GetEvents("token", "test", EventQuery{DateFrom: from, Types: []string{"LOGIN", "LOGIN_ERROR"}, User: "id", Max: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, "/auth/admin/realms/test/events", path)
+	assert.Equal(t, url.Values{"dateFrom": {"2020-03-01"}, "type": {"LOGIN", "LOGIN_ERROR"}, "user": {"id"}, "max": {"10"}}, params)
+
+	_, err = client.GetAdminEvents("token", "test", AdminEventQuery{OperationTypes: []string{OperationTypeCreate}, ResourceTypes: []string{ResourceTypeUser}, ResourcePath: "users/*", AuthIPAddress: "10.0.0.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "/auth/admin/realms/test/admin-events", path)
+	assert.Equal(t, url.Values{"operationTypes": {"CREATE"}, "resourceTypes": {"USER"}, "resourcePath": {"users/*"}, "authIpAddress": {"10.0.0.1"}}, params)
+
+	_, err = client.GetEvents("token", "test", EventQuery{DateFrom: from, DateTo: from.AddDate(0, 0, -1)})
+	assert.Equal(t, Error{Code: MsgErrInvalidParam, Detail: DateToBeforeDateFrom}, err)
+}
+
+func TestAdminEventRepresentation(t *testing.T) {
+	var millis = int64(1583103600123)
+	var event = AdminEventRepresentation{
+		Time:           &millis,
+		ResourceType:   strPtr(ResourceTypeUser),
+		Representation: strPtr(`{"username":"john"}`),
+	}
+	assert.Equal(t, time.Unix(1583103600, 123000000), event.GetTime())
+	assert.True(t, EventRepresentation{}.GetTime().IsZero())
+
+	var v, err = event.TypedRepresentation()
+	assert.Nil(t, err)
+	assert.Equal(t, "john", *v.(*UserRepresentation).Username)
+
+	event.ResourceType = strPtr(ResourceTypeRealmRoleMapping)
+	event.Representation = strPtr(`[{"name":"admin"}]`)
+	v, err = event.TypedRepresentation()
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", *(*v.(*[]RoleRepresentation))[0].Name)
+
+	event.ResourceType = strPtr("UNKNOWN")
+	v, err = event.TypedRepresentation()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "admin"}}, v)
+
+	event.Representation = strPtr("{")
+	_, err = event.TypedRepresentation()
+	assert.Equal(t, MsgErrCannotUnmarshal, err.(Error).Code)
+
+	event.Representation = nil
+	v, err = event.TypedRepresentation()
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+}
+
+func strPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,47 @@
+package keycloak
+
+import (
+	"strings"
+)
+
+// GetGroupByPath returns the group at path, such as "/org/dept/team", along with its sub groups. It walks down
+// the sub groups of the top level groups, so the names of the groups must not contain any "/". It fails with
+// ErrNotFound if there is no such group.
+func GetGroupByPath(client KeycloakAdmin, accessToken string, realmName string, path string) (GroupRepresentation, error) {
+	var names = strings.Split(strings.Trim(path, "/"), "/")
+	if !strings.HasPrefix(path, "/") || names[0] == "" {
+		return GroupRepresentation{}, newError(MsgErrInvalidParam, InvalidGroupPath, nil)
+	}
+
+	// Searching the name of the group sought keeps its ancestors, with the sub groups leading to it.
+	var found *GroupRepresentation
+	var it = NewGroupIterator(client, accessToken, realmName, GroupQuery{Search: names[len(names)-1]}, 0)
+	for found == nil && it.Next() {
+		if group := it.Value(); group.Name != nil && *group.Name == names[0] {
+			found = &group
+		}
+	}
+	if err := it.Err(); err != nil {
+		return GroupRepresentation{}, err
+	}
+
+	for _, name := range names[1:] {
+		if found == nil || found.SubGroups == nil {
+			break
+		}
+		var subGroups = *found.SubGroups
+		found = nil
+		for i := range subGroups {
+			if subGroups[i].Name != nil && *subGroups[i].Name == name {
+				found = &subGroups[i]
+				break
+			}
+		}
+	}
+	if found == nil || found.Path == nil || *found.Path != "/"+strings.Join(names, "/") {
+		return GroupRepresentation{}, newError(MsgErrNotFound, GroupNotFound, ErrNotFound)
+	}
+
+	// The sub groups returned by a search are pruned, fetch the whole group.
+	return client.GetGroup(accessToken, realmName, *found.ID)
+}
@@ -7,15 +7,26 @@ import (
 
 const (
 	groupsPath                          = "/auth/admin/realms/:realm/groups"
+	groupCountPath                      = groupsPath + "/count"
 	groupByIDPath                       = groupsPath + "/:id"
+	groupChildrenPath                   = groupByIDPath + "/children"
+	groupMembersPath                    = groupByIDPath + "/members"
 	groupClientRoleMappingPath          = groupByIDPath + "/role-mappings/clients/:clientId"
 	availableGroupClientRoleMappingPath = groupClientRoleMappingPath + "/available"
+	groupRealmRoleMappingPath           = groupByIDPath + "/role-mappings/realm"
+	availableGroupRealmRoleMappingPath  = groupRealmRoleMappingPath + "/available"
+	effectiveGroupRealmRoleMappingPath  = groupRealmRoleMappingPath + "/composite"
 )
 
-// GetGroups gets all groups for the realm
-func (c *Client) GetGroups(accessToken string, realmName string) ([]GroupRepresentation, error) {
+// GetGroups gets the top level groups of the realm, along with their sub groups, filtered according to the query.
+func (c *Client) GetGroups(accessToken string, realmName string, query GroupQuery) ([]GroupRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
 	var resp = []GroupRepresentation{}
-	var err = c.get(accessToken, &resp, url.Path(groupsPath), url.Param("realm", realmName))
+	var plugins = append(query.plugins(), url.Path(groupsPath), url.Param("realm", realmName))
+	var err = c.get(accessToken, &resp, plugins...)
 	return resp, err
 }
 
@@ -31,6 +42,57 @@ func (c *Client) CreateGroup(accessToken string, reqRealmName string, group Grou
 	return c.post(accessToken, nil, url.Path(groupsPath), url.Param("realm", reqRealmName), body.JSON(group))
 }
 
+// UpdateGroup updates the name and the attributes of the group.
+func (c *Client) UpdateGroup(accessToken string, realmName string, groupID string, group GroupRepresentation) error {
+	return c.put(accessToken, url.Path(groupByIDPath), url.Param("realm", realmName), url.Param("id", groupID), body.JSON(group))
+}
+
+// CreateChildGroup creates the group under the parent group. The group name must be unique among its siblings.
+func (c *Client) CreateChildGroup(accessToken string, realmName string, parentID string, group GroupRepresentation) (string, error) {
+	group.ID = nil
+	return c.post(accessToken, nil, url.Path(groupChildrenPath), url.Param("realm", realmName), url.Param("id", parentID), body.JSON(group))
+}
+
+// MoveGroup moves the group, along with its sub groups, under the parent group, or to the top level if parentID
+// is empty.
+func (c *Client) MoveGroup(accessToken string, realmName string, groupID string, parentID string) error {
+	// Keycloak moves the group whose ID is posted, and updates it with the representation posted.
+	var group, err = c.GetGroup(accessToken, realmName, groupID)
+	if err != nil {
+		return err
+	}
+	var move = GroupRepresentation{ID: group.ID, Name: group.Name}
+
+	if parentID == "" {
+		_, err = c.post(accessToken, nil, url.Path(groupsPath), url.Param("realm", realmName), body.JSON(move))
+	} else {
+		_, err = c.post(accessToken, nil, url.Path(groupChildrenPath), url.Param("realm", realmName), url.Param("id", parentID), body.JSON(move))
+	}
+	return err
+}
+
+// GetGroupMembers returns the users which are direct members of the group, paged according to the query.
+func (c *Client) GetGroupMembers(accessToken string, realmName string, groupID string, query PageQuery) ([]UserRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []UserRepresentation{}
+	var plugins = append(query.plugins(), url.Path(groupMembersPath), url.Param("realm", realmName), url.Param("id", groupID))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetGroupCount returns the number of groups of the realm matching the query.
+func (c *Client) GetGroupCount(accessToken string, realmName string, query GroupCountQuery) (int, error) {
+	var resp = struct {
+		Count int `json:"count"`
+	}{}
+	var plugins = append(query.plugins(), url.Path(groupCountPath), url.Param("realm", realmName))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp.Count, err
+}
+
 // DeleteGroup deletes a specific group’s representation
 func (c *Client) DeleteGroup(accessToken string, realmName string, groupID string) error {
 	return c.delete(accessToken, url.Path(groupByIDPath), url.Param("realm", realmName), url.Param("id", groupID))
@@ -60,3 +122,36 @@ func (c *Client) GetAvailableGroupClientRoles(accessToken string, realmName stri
 	var err = c.get(accessToken, &roles, url.Path(availableGroupClientRoleMappingPath), url.Param("realm", realmName), url.Param("id", groupID), url.Param("clientId", clientID))
 	return roles, err
 }
+
+// AddGroupRealmRoles assigns realm roles to a specific group
+func (c *Client) AddGroupRealmRoles(accessToken string, realmName string, groupID string, roles []RoleRepresentation) error {
+	_, err := c.post(accessToken, nil, url.Path(groupRealmRoleMappingPath), url.Param("realm", realmName), url.Param("id", groupID), body.JSON(roles))
+	return err
+}
+
+// DeleteGroupRealmRoles removes realm roles from a specific group
+func (c *Client) DeleteGroupRealmRoles(accessToken string, realmName string, groupID string, roles []RoleRepresentation) error {
+	return c.delete(accessToken, url.Path(groupRealmRoleMappingPath), url.Param("realm", realmName), url.Param("id", groupID), body.JSON(roles))
+}
+
+// GetGroupRealmRoles gets realm roles assigned to a specific group
+func (c *Client) GetGroupRealmRoles(accessToken string, realmName string, groupID string) ([]RoleRepresentation, error) {
+	var roles = []RoleRepresentation{}
+	var err = c.get(accessToken, &roles, url.Path(groupRealmRoleMappingPath), url.Param("realm", realmName), url.Param("id", groupID))
+	return roles, err
+}
+
+// GetAvailableGroupRealmRoles gets realm roles which can still be assigned to a specific group
+func (c *Client) GetAvailableGroupRealmRoles(accessToken string, realmName string, groupID string) ([]RoleRepresentation, error) {
+	var roles = []RoleRepresentation{}
+	var err = c.get(accessToken, &roles, url.Path(availableGroupRealmRoleMappingPath), url.Param("realm", realmName), url.Param("id", groupID))
+	return roles, err
+}
+
+// GetEffectiveGroupRealmRoles gets realm roles granted to a specific group, directly, through composite roles or
+// through its parent groups
+func (c *Client) GetEffectiveGroupRealmRoles(accessToken string, realmName string, groupID string) ([]RoleRepresentation, error) {
+	var roles = []RoleRepresentation{}
+	var err = c.get(accessToken, &roles, url.Path(effectiveGroupRealmRoleMappingPath), url.Param("realm", realmName), url.Param("id", groupID))
+	return roles, err
+}
@@ -0,0 +1,28 @@
+package keycloak
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveGroup(t *testing.T) {
+	var requests []string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var content, _ = ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(content))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"g1","name":"team","path":"/org/team","attributes":{"a":["b"]}}`))
+	}))
+
+	assert.Nil(t, client.MoveGroup("token", "test", "g1", "g2"))
+	assert.Nil(t, client.MoveGroup("token", "test", "g1", ""))
+	assert.Equal(t, []string{
+		"GET /auth/admin/realms/test/groups/g1 ",
+		`POST /auth/admin/realms/test/groups/g2/children {"id":"g1","name":"team"}` + "\n",
+		"GET /auth/admin/realms/test/groups/g1 ",
+		`POST /auth/admin/realms/test/groups {"id":"g1","name":"team"}` + "\n",
+	}, requests)
+}
@@ -1,13 +1,21 @@
 package keycloak
 
 import (
+	"bytes"
+	"mime/multipart"
+
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/headers"
 	"gopkg.in/h2non/gentleman.v2/plugins/url"
 )
 
 const (
-	idpsPath       = "/auth/admin/realms/:realm/identity-provider/instances"
-	idpAliasPath   = idpsPath + "/:alias"
-	idpMappersPath = idpAliasPath + "/mappers"
+	idpsPath                     = "/auth/admin/realms/:realm/identity-provider/instances"
+	idpAliasPath                 = idpsPath + "/:alias"
+	idpMappersPath               = idpAliasPath + "/mappers"
+	idpMapperIDPath              = idpMappersPath + "/:id"
+	idpManagementPermissionsPath = idpAliasPath + "/management/permissions"
+	idpImportConfigPath          = "/auth/admin/realms/:realm/identity-provider/import-config"
 )
 
 // GetIdps gets the list of identity providers
@@ -24,9 +32,96 @@ func (c *Client) GetIdp(accessToken string, realmName string, idpAlias string) (
 	return resp, err
 }
 
+// CreateIdp creates an identity provider. Its alias must be unique within the realm.
+func (c *Client) CreateIdp(accessToken string, realmName string, idp IdentityProviderRepresentation) (string, error) {
+	return c.post(accessToken, nil, url.Path(idpsPath), url.Param("realm", realmName), body.JSON(idp))
+}
+
+// UpdateIdp updates the identity provider matching the given alias
+func (c *Client) UpdateIdp(accessToken string, realmName string, idpAlias string, idp IdentityProviderRepresentation) error {
+	return c.put(accessToken, url.Path(idpAliasPath), url.Param("realm", realmName), url.Param("alias", idpAlias), body.JSON(idp))
+}
+
+// DeleteIdp deletes the identity provider matching the given alias, along with its mappers
+func (c *Client) DeleteIdp(accessToken string, realmName string, idpAlias string) error {
+	return c.delete(accessToken, url.Path(idpAliasPath), url.Param("realm", realmName), url.Param("alias", idpAlias))
+}
+
 // GetIdpMappers gets the mappers of the specified identity provider
 func (c *Client) GetIdpMappers(accessToken string, realmName string, idpAlias string) ([]IdentityProviderMapperRepresentation, error) {
 	var resp = []IdentityProviderMapperRepresentation{}
 	var err = c.get(accessToken, &resp, url.Path(idpMappersPath), url.Param("realm", realmName), url.Param("alias", idpAlias))
 	return resp, err
 }
+
+// GetIdpMapper gets a mapper of the specified identity provider
+func (c *Client) GetIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string) (IdentityProviderMapperRepresentation, error) {
+	var resp = IdentityProviderMapperRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(idpMapperIDPath), url.Param("realm", realmName), url.Param("alias", idpAlias), url.Param("id", mapperID))
+	return resp, err
+}
+
+// CreateIdpMapper adds a mapper to the specified identity provider. The IdentityProviderAlias of the mapper must
+// be the alias of the identity provider.
+func (c *Client) CreateIdpMapper(accessToken string, realmName string, idpAlias string, mapper IdentityProviderMapperRepresentation) (string, error) {
+	return c.post(accessToken, nil, url.Path(idpMappersPath), url.Param("realm", realmName), url.Param("alias", idpAlias), body.JSON(mapper))
+}
+
+// UpdateIdpMapper updates a mapper of the specified identity provider
+func (c *Client) UpdateIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string, mapper IdentityProviderMapperRepresentation) error {
+	return c.put(accessToken, url.Path(idpMapperIDPath), url.Param("realm", realmName), url.Param("alias", idpAlias), url.Param("id", mapperID), body.JSON(mapper))
+}
+
+// DeleteIdpMapper deletes a mapper of the specified identity provider
+func (c *Client) DeleteIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string) error {
+	return c.delete(accessToken, url.Path(idpMapperIDPath), url.Param("realm", realmName), url.Param("alias", idpAlias), url.Param("id", mapperID))
+}
+
+// ImportIdpConfig fetches the configuration published at fromURL, an OpenID Connect discovery document or a SAML
+// metadata document, and returns it as the config of an identity provider of type providerID ("oidc", "saml"...).
+func (c *Client) ImportIdpConfig(accessToken string, realmName string, providerID string, fromURL string) (map[string]interface{}, error) {
+	var resp = map[string]interface{}{}
+	var _, err = c.post(accessToken, &resp, url.Path(idpImportConfigPath), url.Param("realm", realmName),
+		body.JSON(map[string]string{"providerId": providerID, "fromUrl": fromURL}))
+	return resp, err
+}
+
+// ImportIdpConfigFromMetadata is like ImportIdpConfig but parses the given document instead of fetching it.
+func (c *Client) ImportIdpConfigFromMetadata(accessToken string, realmName string, providerID string, metadata []byte) (map[string]interface{}, error) {
+	var form = &bytes.Buffer{}
+	var writer = multipart.NewWriter(form)
+	var err = writer.WriteField("providerId", providerID)
+	if err == nil {
+		var part, partErr = writer.CreateFormFile("file", "metadata")
+		if err = partErr; err == nil {
+			_, err = part.Write(metadata)
+		}
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return nil, newError(MsgErrCannotMarshal, Metadata, err)
+	}
+
+	var resp = map[string]interface{}{}
+	_, err = c.post(accessToken, &resp, url.Path(idpImportConfigPath), url.Param("realm", realmName),
+		body.String(form.String()), headers.Set("Content-Type", writer.FormDataContentType()))
+	return resp, err
+}
+
+// GetIdpManagementPermissions tells whether the fine grained permissions of the identity provider are enabled
+func (c *Client) GetIdpManagementPermissions(accessToken string, realmName string, idpAlias string) (ManagementPermissionReference, error) {
+	var resp = ManagementPermissionReference{}
+	var err = c.get(accessToken, &resp, url.Path(idpManagementPermissionsPath), url.Param("realm", realmName), url.Param("alias", idpAlias))
+	return resp, err
+}
+
+// SetIdpManagementPermissions enables or disables the fine grained permissions of the identity provider, e.g. to
+// allow clients to exchange its tokens
+func (c *Client) SetIdpManagementPermissions(accessToken string, realmName string, idpAlias string, enabled bool) (ManagementPermissionReference, error) {
+	var resp = ManagementPermissionReference{}
+	var err = c.putWithResponse(accessToken, &resp, url.Path(idpManagementPermissionsPath), url.Param("realm", realmName), url.Param("alias", idpAlias),
+		body.JSON(ManagementPermissionReference{Enabled: &enabled}))
+	return resp, err
+}
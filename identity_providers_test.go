@@ -0,0 +1,50 @@
+package keycloak
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportIdpConfig(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/test/identity-provider/import-config", r.URL.Path)
+		if r.Header.Get("Content-Type") == "application/json" {
+			var content, _ = ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"providerId":"oidc","fromUrl":"https://idp.example.com/.well-known/openid-configuration"}`, string(content))
+		} else {
+			assert.Nil(t, r.ParseMultipartForm(1<<20))
+			assert.Equal(t, "saml", r.FormValue("providerId"))
+			var file, _, err = r.FormFile("file")
+			assert.Nil(t, err)
+			var content, _ = ioutil.ReadAll(file)
+			assert.Equal(t, "<EntityDescriptor/>", string(content))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issuer":"https://idp.example.com"}`))
+	}))
+
+	var config, err = client.ImportIdpConfig("token", "test", "oidc", "https://idp.example.com/.well-known/openid-configuration")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://idp.example.com", config["issuer"])
+	config, err = client.ImportIdpConfigFromMetadata("token", "test", "saml", []byte("<EntityDescriptor/>"))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://idp.example.com", config["issuer"])
+}
+
+func TestSetIdpManagementPermissions(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var content, _ = ioutil.ReadAll(r.Body)
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/auth/admin/realms/test/identity-provider/instances/partner/management/permissions", r.URL.Path)
+		assert.JSONEq(t, `{"enabled":true}`, string(content))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"enabled":true,"resource":"r1","scopePermissions":{"token-exchange":"p1"}}`))
+	}))
+
+	var permissions, err = client.SetIdpManagementPermissions("token", "test", "partner", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "r1", *permissions.Resource)
+}
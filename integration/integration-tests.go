@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/nmasse-itix/keycloak-client"
+	"github.com/nmasse-itix/keycloak-client/keycloaktest"
+	"github.com/nmasse-itix/keycloak-client/reconcile"
 	"github.com/spf13/pflag"
 )
 
@@ -18,15 +20,10 @@ const (
 )
 
 func main() {
-	var conf = getKeycloakConfig()
-	fmt.Printf("Connecting to KC %s...\n", conf.AddrAPI)
-	var client, err = keycloak.NewClient(*conf)
-	if err != nil {
-		log.Fatalf("could not create keycloak client: %v", err)
-	}
+	var client = getKeycloakAdmin()
 
 	// Get access token
-	accessToken, err := client.GetToken("master", "admin", "admin")
+	var accessToken, err = client.GetToken("master", "admin", "admin")
 	if err != nil {
 		log.Fatalf("could not get access token: %v", err)
 	}
@@ -145,7 +142,7 @@ func main() {
 	{
 		{
 			// No parameters.
-			var users, err = client.GetUsers(accessToken, tstRealm)
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{})
 			if err != nil {
 				log.Fatalf("could not get users: %v", err)
 			}
@@ -166,7 +163,7 @@ func main() {
 		}
 		{
 			// email.
-			var users, err = client.GetUsers(accessToken, tstRealm, "email", "john.doe@cloudtrust.ch")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{Email: "john.doe@cloudtrust.ch"})
 			if err != nil {
 				log.Fatalf("could not get users: %v", err)
 			}
@@ -176,7 +173,7 @@ func main() {
 		}
 		{
 			// firstname.
-			var users, err = client.GetUsers(accessToken, tstRealm, "firstName", "John")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{FirstName: "John"})
 			if err != nil {
 				log.Fatalf("could not get users: %v", err)
 			}
@@ -187,7 +184,7 @@ func main() {
 		}
 		{
 			// lastname.
-			var users, err = client.GetUsers(accessToken, tstRealm, "lastName", "Wells")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{LastName: "Wells"})
 			if err != nil {
 				log.Fatalf("could not get users: %v", err)
 			}
@@ -197,7 +194,7 @@ func main() {
 		}
 		{
 			// username.
-			var users, err = client.GetUsers(accessToken, tstRealm, "username", "lucia.nelson")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{Username: "lucia.nelson"})
 			if err != nil {
 				log.Fatalf("could not get users: %v", err)
 			}
@@ -207,7 +204,7 @@ func main() {
 		}
 		{
 			// first.
-			var users, err = client.GetUsers(accessToken, tstRealm, "max", "7")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{Max: 7})
 			if err != nil {
 				log.Fatalf("could not get users: %v", err)
 			}
@@ -217,7 +214,7 @@ func main() {
 		}
 		{
 			// search.
-			var users, err = client.GetUsers(accessToken, tstRealm, "search", "le")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{Search: "le"})
 			if err != nil {
 				log.Fatalf("could not get users: %v", err)
 			}
@@ -234,7 +231,7 @@ func main() {
 		// Get user ID.
 		var userID string
 		{
-			var users, err = client.GetUsers(accessToken, tstRealm, "search", "Maria")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{Search: "Maria"})
 			if err != nil {
 				log.Fatalf("could not get Maria: %v", err)
 			}
@@ -261,7 +258,7 @@ func main() {
 		}
 		// Check that user was updated.
 		{
-			var users, err = client.GetUsers(accessToken, tstRealm, "search", "Maria")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{Search: "Maria"})
 			if err != nil {
 				log.Fatalf("could not get Maria: %v", err)
 			}
@@ -290,7 +287,7 @@ func main() {
 		// Get user ID.
 		var userID string
 		{
-			var users, err = client.GetUsers(accessToken, tstRealm, "search", "Toni")
+			var users, err = client.GetUsers(accessToken, tstRealm, keycloak.UserQuery{Search: "Toni"})
 			if err != nil {
 				log.Fatalf("could not get Toni: %v", err)
 			}
@@ -363,6 +360,250 @@ func main() {
 		fmt.Println("Components created.")
 	}
 
+	// Events.
+	{
+		var enabled = true
+		var err = client.UpdateEventsConfig(accessToken, tstRealm, keycloak.RealmEventsConfigRepresentation{
+			EventsEnabled:      &enabled,
+			AdminEventsEnabled: &enabled,
+			EnabledEventTypes:  &[]string{"LOGIN", "LOGIN_ERROR"},
+		})
+		if err != nil {
+			log.Fatalf("could not update events config: %v", err)
+		}
+		config, err := client.GetEventsConfig(accessToken, tstRealm)
+		if err != nil {
+			log.Fatalf("could not get events config: %v", err)
+		}
+		if config.EventsEnabled == nil || !*config.EventsEnabled {
+			log.Fatalf("events should be enabled")
+		}
+		_, err = client.GetEvents(accessToken, tstRealm, keycloak.EventQuery{Types: []string{"LOGIN"}, Max: 10})
+		if err != nil {
+			log.Fatalf("could not get events: %v", err)
+		}
+		_, err = client.GetAdminEvents(accessToken, tstRealm, keycloak.AdminEventQuery{ResourceTypes: []string{keycloak.ResourceTypeUser}})
+		if err != nil {
+			log.Fatalf("could not get admin events: %v", err)
+		}
+		if err = client.ClearEvents(accessToken, tstRealm); err != nil {
+			log.Fatalf("could not clear events: %v", err)
+		}
+		if err = client.ClearAdminEvents(accessToken, tstRealm); err != nil {
+			log.Fatalf("could not clear admin events: %v", err)
+		}
+		fmt.Println("Events checked.")
+	}
+
+	// Roles.
+	{
+		for _, name := range []string{"integration-reader", "integration-writer"} {
+			var _, err = client.CreateRole(accessToken, tstRealm, keycloak.RoleRepresentation{Name: &name})
+			if err != nil {
+				log.Fatalf("could not create role: %v", err)
+			}
+		}
+		var description = "Writes and reads"
+		var err = client.UpdateRole(accessToken, tstRealm, "integration-writer", keycloak.RoleRepresentation{Description: &description})
+		if err != nil {
+			log.Fatalf("could not update role: %v", err)
+		}
+		writer, err := client.GetRoleByName(accessToken, tstRealm, "integration-writer")
+		if err != nil {
+			log.Fatalf("could not get role: %v", err)
+		}
+		err = keycloak.SetRoleComposites(client, accessToken, tstRealm, *writer.ID, keycloak.RoleRepresentationComposites{Realm: &[]string{"integration-reader"}})
+		if err != nil {
+			log.Fatalf("could not set role composites: %v", err)
+		}
+		composites, err := client.GetRealmRoleComposites(accessToken, tstRealm, *writer.ID)
+		if err != nil {
+			log.Fatalf("could not get role composites: %v", err)
+		}
+		if len(composites) != 1 || *composites[0].Name != "integration-reader" {
+			log.Fatalf("writer should include reader")
+		}
+		for _, name := range []string{"integration-reader", "integration-writer"} {
+			if err = client.DeleteRole(accessToken, tstRealm, name); err != nil {
+				log.Fatalf("could not delete role: %v", err)
+			}
+		}
+		fmt.Println("Roles checked.")
+	}
+
+	// Client scopes.
+	{
+		var scopeName = "integration-scope"
+		var location, err = client.CreateClientScope(accessToken, tstRealm, keycloak.ClientScopeRepresentation{Name: &scopeName})
+		if err != nil {
+			log.Fatalf("could not create client scope: %v", err)
+		}
+		u, err := url.Parse(location)
+		if err != nil {
+			log.Fatalf("cannot parse client scope location: %v", err)
+		}
+		slugs := strings.Split(u.Path, "/")
+		scopeID := slugs[len(slugs)-1]
+
+		var clientID = "integration-scoped-client"
+		location, err = client.CreateClient(accessToken, tstRealm, keycloak.ClientRepresentation{ClientID: &clientID})
+		if err != nil {
+			log.Fatalf("could not create client: %v", err)
+		}
+		u, err = url.Parse(location)
+		if err != nil {
+			log.Fatalf("cannot parse client location: %v", err)
+		}
+		slugs = strings.Split(u.Path, "/")
+		idClient := slugs[len(slugs)-1]
+
+		if err = client.AddClientOptionalScope(accessToken, tstRealm, idClient, scopeID); err != nil {
+			log.Fatalf("could not add optional client scope: %v", err)
+		}
+		scopes, err := client.GetClientOptionalScopes(accessToken, tstRealm, idClient)
+		if err != nil {
+			log.Fatalf("could not get optional client scopes: %v", err)
+		}
+		var found = false
+		for _, scope := range scopes {
+			found = found || *scope.ID == scopeID
+		}
+		if !found {
+			log.Fatalf("client scope should be an optional scope of the client")
+		}
+		if err = client.DeleteClientScope(accessToken, tstRealm, scopeID); err != nil {
+			log.Fatalf("could not delete client scope: %v", err)
+		}
+		scoped, err := keycloak.GetClientByClientID(client, accessToken, tstRealm, clientID)
+		if err != nil {
+			log.Fatalf("could not get client by client-id: %v", err)
+		}
+		if err = client.DeleteClient(accessToken, tstRealm, *scoped.ID); err != nil {
+			log.Fatalf("could not delete client: %v", err)
+		}
+		fmt.Println("Client scopes checked.")
+	}
+
+	// Authorization services.
+	{
+		var clientID = "integration-resource-server"
+		var enabled = true
+		var location, err = client.CreateClient(accessToken, tstRealm, keycloak.ClientRepresentation{
+			ClientID:                     &clientID,
+			ServiceAccountsEnabled:       &enabled,
+			AuthorizationServicesEnabled: &enabled,
+		})
+		if err != nil {
+			log.Fatalf("could not create client: %v", err)
+		}
+		u, err := url.Parse(location)
+		if err != nil {
+			log.Fatalf("cannot parse client location: %v", err)
+		}
+		slugs := strings.Split(u.Path, "/")
+		idClient := slugs[len(slugs)-1]
+
+		var resourceName, scopeName = "integration-documents", "read"
+		if _, err = client.CreateResource(accessToken, tstRealm, idClient, keycloak.ResourceRepresentation{
+			Name:   &resourceName,
+			URIs:   &[]string{"/documents/*"},
+			Scopes: &[]keycloak.ScopeRepresentation{{Name: &scopeName}},
+		}); err != nil {
+			log.Fatalf("could not create resource: %v", err)
+		}
+		var policyName, permissionName = "integration-client", "integration-read-documents"
+		if _, err = client.CreatePolicy(accessToken, tstRealm, idClient, keycloak.ClientPolicyRepresentation{
+			PolicyRepresentation: keycloak.PolicyRepresentation{Name: &policyName},
+			Clients:              &[]string{idClient},
+		}); err != nil {
+			log.Fatalf("could not create policy: %v", err)
+		}
+		if _, err = client.CreatePermission(accessToken, tstRealm, idClient, keycloak.ScopePermissionRepresentation{
+			PolicyRepresentation: keycloak.PolicyRepresentation{
+				Name:      &permissionName,
+				Resources: &[]string{resourceName},
+				Scopes:    &[]string{scopeName},
+				Policies:  &[]string{policyName},
+			},
+		}); err != nil {
+			log.Fatalf("could not create permission: %v", err)
+		}
+		permissions, err := client.GetPermissions(accessToken, tstRealm, idClient, keycloak.PolicyQuery{Resource: resourceName})
+		if err != nil {
+			log.Fatalf("could not get permissions: %v", err)
+		}
+		if len(permissions) != 1 || *permissions[0].Name != permissionName {
+			log.Fatalf("wrong permissions of the resource: %d", len(permissions))
+		}
+		if err = client.DeleteClient(accessToken, tstRealm, idClient); err != nil {
+			log.Fatalf("could not delete client: %v", err)
+		}
+		fmt.Println("Authorization services checked.")
+	}
+
+	// Partial import.
+	{
+		var groupName = "integration-imported-group"
+		var rep = keycloak.PartialImportRepresentation{
+			IfResourceExists: keycloak.IfResourceExistsSkip,
+			Groups:           &[]keycloak.GroupRepresentation{{Name: &groupName}},
+		}
+		var results, err = client.PartialImport(accessToken, tstRealm, rep)
+		if err != nil {
+			log.Fatalf("could not import group: %v", err)
+		}
+		if results.Added != 1 {
+			log.Fatalf("group should be added: %d", results.Added)
+		}
+		if results, err = client.PartialImport(accessToken, tstRealm, rep); err != nil {
+			log.Fatalf("could not import group again: %v", err)
+		}
+		if len(results.WithAction(keycloak.PartialImportActionSkipped)) != 1 {
+			log.Fatalf("existing group should be skipped")
+		}
+		group, err := keycloak.GetGroupByPath(client, accessToken, tstRealm, "/"+groupName)
+		if err != nil {
+			log.Fatalf("could not get imported group: %v", err)
+		}
+		if err = client.DeleteGroup(accessToken, tstRealm, *group.ID); err != nil {
+			log.Fatalf("could not delete imported group: %v", err)
+		}
+		fmt.Println("Partial import checked.")
+	}
+
+	// Reconciliation.
+	{
+		var realmName, groupName = tstRealm, "integration-reconciled-group"
+		var desired = keycloak.RealmRepresentation{
+			Realm:  &realmName,
+			Groups: &[]keycloak.GroupRepresentation{{Name: &groupName}},
+		}
+		var plan, err = reconcile.Reconcile(client, accessToken, desired, reconcile.Options{DryRun: true})
+		if err != nil {
+			log.Fatalf("could not compute reconciliation plan: %v", err)
+		}
+		if len(plan.Operations) != 1 || plan.Operations[0].Action != reconcile.ActionCreate {
+			log.Fatalf("plan should create the group: %s", plan)
+		}
+		if err = plan.Apply(client, accessToken); err != nil {
+			log.Fatalf("could not apply reconciliation plan: %v", err)
+		}
+		if plan, err = reconcile.Compute(client, accessToken, desired, reconcile.Options{}); err != nil {
+			log.Fatalf("could not compute reconciliation plan again: %v", err)
+		}
+		if len(plan.Operations) != 0 {
+			log.Fatalf("realm should be up to date: %s", plan)
+		}
+		group, err := keycloak.GetGroupByPath(client, accessToken, tstRealm, "/"+groupName)
+		if err != nil {
+			log.Fatalf("could not get reconciled group: %v", err)
+		}
+		if err = client.DeleteGroup(accessToken, tstRealm, *group.ID); err != nil {
+			log.Fatalf("could not delete reconciled group: %v", err)
+		}
+		fmt.Println("Reconciliation checked.")
+	}
+
 	// Delete test realm.
 	{
 		var err = client.DeleteRealm(accessToken, tstRealm)
@@ -426,16 +667,29 @@ func main() {
 
 }
 
-func getKeycloakConfig() *keycloak.Config {
+// getKeycloakAdmin returns a client connected to Keycloak, or an in-memory fake when --fake is set.
+func getKeycloakAdmin() keycloak.KeycloakAdmin {
 	var apiAddr = pflag.String("urlKc", "http://localhost:8080/auth", "keycloak address")
 	var tokenAddr = pflag.String("url", "http://localhost:8080/auth/realms/master", "token address")
+	var fake = pflag.Bool("fake", false, "run against the in-memory keycloaktest fake")
 	pflag.Parse()
 
-	return &keycloak.Config{
+	if *fake {
+		fmt.Println("Using the in-memory fake...")
+		return keycloaktest.New()
+	}
+
+	var conf = keycloak.Config{
 		AddrTokenProvider: *tokenAddr,
 		AddrAPI:           *apiAddr,
 		Timeout:           10 * time.Second,
 	}
+	fmt.Printf("Connecting to KC %s...\n", conf.AddrAPI)
+	var client, err = keycloak.NewClient(conf)
+	if err != nil {
+		log.Fatalf("could not create keycloak client: %v", err)
+	}
+	return client
 }
 
 var tstUsers = []struct {
@@ -0,0 +1,303 @@
+package keycloak
+
+import (
+	"context"
+)
+
+// DefaultPageSize is the number of items fetched per request by the iterators when no page size is given.
+const DefaultPageSize = 100
+
+// pager walks a listing page by page, so that only one page is held in memory at a time. fetch retrieves the
+// page of at most max items starting at first and returns its size.
+type pager struct {
+	ctx      context.Context
+	pageSize int
+	fetch    func(first, max int) (int, error)
+
+	first int
+	index int
+	size  int
+	last  bool
+	err   error
+}
+
+func newPager(client KeycloakAdmin, pageSize int, fetch func(first, max int) (int, error)) pager {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return pager{ctx: contextOf(client), pageSize: pageSize, fetch: fetch, index: -1}
+}
+
+// next moves to the next item, fetching the next page when the current one is exhausted. It stops at the end
+// of the listing, on the first error or as soon as the context of the client is done.
+func (p *pager) next() bool {
+	if p.err != nil {
+		return false
+	}
+	p.index++
+	if p.index < p.size {
+		return true
+	}
+	if p.last {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	var size, err = p.fetch(p.first, p.pageSize)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.first += size
+	p.index, p.size = 0, size
+	p.last = size < p.pageSize
+	return size > 0
+}
+
+// contextOf returns the context bound to client, if it has one.
+func contextOf(client KeycloakAdmin) context.Context {
+	if c, ok := client.(interface{ Context() context.Context }); ok {
+		return c.Context()
+	}
+	return context.Background()
+}
+
+// UserIterator walks the users of a realm page by page.
+//
+//	var it = keycloak.NewUserIterator(client, accessToken, "myrealm", keycloak.UserQuery{}, 500)
+//	for it.Next() {
+//		var user = it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type UserIterator struct {
+	pager
+	page []UserRepresentation
+}
+
+// NewUserIterator returns an iterator over the users matching the query, fetched pageSize at a
+// time. The First and Max fields of the query are managed by the iterator.
+func NewUserIterator(client KeycloakAdmin, accessToken string, realmName string, query UserQuery, pageSize int) *UserIterator {
+	var it = &UserIterator{}
+	it.pager = newPager(client, pageSize, func(first, max int) (int, error) {
+		var err error
+		query.First, query.Max = first, max
+		it.page, err = client.GetUsers(accessToken, realmName, query)
+		return len(it.page), err
+	})
+	return it
+}
+
+// Next moves to the next user. It returns false at the end of the iteration or if an error occurred.
+func (it *UserIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current user.
+func (it *UserIterator) Value() UserRepresentation {
+	return it.page[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+// ForEachUser calls fn for each user matching the query, fetched pageSize at a time. It stops
+// at the first error, either returned by fn or encountered while fetching the users.
+func ForEachUser(client KeycloakAdmin, accessToken string, realmName string, query UserQuery, pageSize int, fn func(UserRepresentation) error) error {
+	var it = NewUserIterator(client, accessToken, realmName, query, pageSize)
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// GroupIterator walks the top level groups of a realm page by page.
+type GroupIterator struct {
+	pager
+	page []GroupRepresentation
+}
+
+// NewGroupIterator returns an iterator over the top level groups matching the query, fetched
+// pageSize at a time. The First and Max fields of the query are managed by the iterator.
+func NewGroupIterator(client KeycloakAdmin, accessToken string, realmName string, query GroupQuery, pageSize int) *GroupIterator {
+	var it = &GroupIterator{}
+	it.pager = newPager(client, pageSize, func(first, max int) (int, error) {
+		var err error
+		query.First, query.Max = first, max
+		it.page, err = client.GetGroups(accessToken, realmName, query)
+		return len(it.page), err
+	})
+	return it
+}
+
+// Next moves to the next group. It returns false at the end of the iteration or if an error occurred.
+func (it *GroupIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current group.
+func (it *GroupIterator) Value() GroupRepresentation {
+	return it.page[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *GroupIterator) Err() error {
+	return it.err
+}
+
+// ForEachGroup calls fn for each top level group matching the query, fetched pageSize at a
+// time. It stops at the first error, either returned by fn or encountered while fetching the groups.
+func ForEachGroup(client KeycloakAdmin, accessToken string, realmName string, query GroupQuery, pageSize int, fn func(GroupRepresentation) error) error {
+	var it = NewGroupIterator(client, accessToken, realmName, query, pageSize)
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// ClientIterator walks the clients of a realm page by page.
+type ClientIterator struct {
+	pager
+	page []ClientRepresentation
+}
+
+// NewClientIterator returns an iterator over the clients matching the query, fetched
+// pageSize at a time. The First and Max fields of the query are managed by the iterator.
+func NewClientIterator(client KeycloakAdmin, accessToken string, realmName string, query ClientQuery, pageSize int) *ClientIterator {
+	var it = &ClientIterator{}
+	it.pager = newPager(client, pageSize, func(first, max int) (int, error) {
+		var err error
+		query.First, query.Max = first, max
+		it.page, err = client.GetClients(accessToken, realmName, query)
+		return len(it.page), err
+	})
+	return it
+}
+
+// Next moves to the next client. It returns false at the end of the iteration or if an error occurred.
+func (it *ClientIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current client.
+func (it *ClientIterator) Value() ClientRepresentation {
+	return it.page[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *ClientIterator) Err() error {
+	return it.err
+}
+
+// ForEachClient calls fn for each client matching the query, fetched pageSize at a time. It
+// stops at the first error, either returned by fn or encountered while fetching the clients.
+func ForEachClient(client KeycloakAdmin, accessToken string, realmName string, query ClientQuery, pageSize int, fn func(ClientRepresentation) error) error {
+	var it = NewClientIterator(client, accessToken, realmName, query, pageSize)
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// EventIterator walks the login events of a realm page by page, most recent first.
+type EventIterator struct {
+	pager
+	page []EventRepresentation
+}
+
+// NewEventIterator returns an iterator over the login events matching the query, fetched
+// pageSize at a time. The First and Max fields of the query are managed by the iterator.
+func NewEventIterator(client KeycloakAdmin, accessToken string, realmName string, query EventQuery, pageSize int) *EventIterator {
+	var it = &EventIterator{}
+	it.pager = newPager(client, pageSize, func(first, max int) (int, error) {
+		var err error
+		query.First, query.Max = first, max
+		it.page, err = client.GetEvents(accessToken, realmName, query)
+		return len(it.page), err
+	})
+	return it
+}
+
+// Next moves to the next event. It returns false at the end of the iteration or if an error occurred.
+func (it *EventIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current event.
+func (it *EventIterator) Value() EventRepresentation {
+	return it.page[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *EventIterator) Err() error {
+	return it.err
+}
+
+// ForEachEvent calls fn for each login event matching the query, fetched pageSize at a time. It
+// stops at the first error, either returned by fn or encountered while fetching the events.
+func ForEachEvent(client KeycloakAdmin, accessToken string, realmName string, query EventQuery, pageSize int, fn func(EventRepresentation) error) error {
+	var it = NewEventIterator(client, accessToken, realmName, query, pageSize)
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// AdminEventIterator walks the admin events of a realm page by page, most recent first.
+type AdminEventIterator struct {
+	pager
+	page []AdminEventRepresentation
+}
+
+// NewAdminEventIterator returns an iterator over the admin events matching the query, fetched
+// pageSize at a time. The First and Max fields of the query are managed by the iterator.
+func NewAdminEventIterator(client KeycloakAdmin, accessToken string, realmName string, query AdminEventQuery, pageSize int) *AdminEventIterator {
+	var it = &AdminEventIterator{}
+	it.pager = newPager(client, pageSize, func(first, max int) (int, error) {
+		var err error
+		query.First, query.Max = first, max
+		it.page, err = client.GetAdminEvents(accessToken, realmName, query)
+		return len(it.page), err
+	})
+	return it
+}
+
+// Next moves to the next admin event. It returns false at the end of the iteration or if an error occurred.
+func (it *AdminEventIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current admin event.
+func (it *AdminEventIterator) Value() AdminEventRepresentation {
+	return it.page[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *AdminEventIterator) Err() error {
+	return it.err
+}
+
+// ForEachAdminEvent calls fn for each admin event matching the query, fetched pageSize at a time.
+// It stops at the first error, either returned by fn or encountered while fetching the events.
+func ForEachAdminEvent(client KeycloakAdmin, accessToken string, realmName string, query AdminEventQuery, pageSize int, fn func(AdminEventRepresentation) error) error {
+	var it = NewAdminEventIterator(client, accessToken, realmName, query, pageSize)
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
@@ -0,0 +1,112 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedUsers serves n users, honouring the first and max parameters, and counts the requests.
+func pagedUsers(n int, calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		var first, _ = strconv.Atoi(r.URL.Query().Get("first"))
+		var max, _ = strconv.Atoi(r.URL.Query().Get("max"))
+		var users = []UserRepresentation{}
+		for i := first; i < n && i < first+max; i++ {
+			var username = fmt.Sprintf("user%03d", i)
+			users = append(users, UserRepresentation{Username: &username})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(users)
+	}
+}
+
+func TestUserIterator(t *testing.T) {
+	var calls int32
+	var client = newTestClient(t, pagedUsers(250, &calls))
+
+	var it = NewUserIterator(client, "token", "test", UserQuery{Max: 100000, Search: "user"}, 100)
+	var usernames []string
+	for it.Next() {
+		usernames = append(usernames, *it.Value().Username)
+	}
+	assert.Nil(t, it.Err())
+	assert.Len(t, usernames, 250)
+	assert.Equal(t, "user249", usernames[249])
+	assert.Equal(t, int32(3), calls)
+
+	// A last page which is full requires an extra request to detect the end.
+	atomic.StoreInt32(&calls, 0)
+	var count = 0
+	var err = ForEachUser(client, "token", "test", UserQuery{}, 50, func(UserRepresentation) error {
+		count++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 250, count)
+	assert.Equal(t, int32(6), calls)
+}
+
+func TestUserIteratorStops(t *testing.T) {
+	var calls int32
+	var client = newTestClient(t, pagedUsers(250, &calls))
+
+	t.Run("Callback error", func(t *testing.T) {
+		var stop = errors.New("stop")
+		var count = 0
+		var err = ForEachUser(client, "token", "test", UserQuery{}, 100, func(UserRepresentation) error {
+			count++
+			if count == 120 {
+				return stop
+			}
+			return nil
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, 120, count)
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		var ctx, cancel = context.WithCancel(context.Background())
+		var it = NewUserIterator(client.WithContext(ctx), "token", "test", UserQuery{}, 100)
+		for i := 0; i < 100; i++ {
+			assert.True(t, it.Next())
+		}
+		cancel()
+		assert.False(t, it.Next())
+		assert.Equal(t, context.Canceled, it.Err())
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		var it = NewUserIterator(client, "token", "test", UserQuery{Search: "doe", Username: "john"}, 100)
+		assert.False(t, it.Next())
+		assert.NotNil(t, it.Err())
+	})
+}
+
+func TestGroupAndClientIterators(t *testing.T) {
+	var calls int32
+	var client = newTestClient(t, pagedUsers(30, &calls))
+
+	var groups = 0
+	assert.Nil(t, ForEachGroup(client, "token", "test", GroupQuery{}, 20, func(GroupRepresentation) error {
+		groups++
+		return nil
+	}))
+	assert.Equal(t, 30, groups)
+
+	var clients = NewClientIterator(client, "token", "test", ClientQuery{}, 0)
+	var count = 0
+	for clients.Next() {
+		count++
+	}
+	assert.Nil(t, clients.Err())
+	assert.Equal(t, 30, count)
+}
@@ -0,0 +1,300 @@
+package keycloak
+
+// KeycloakAdmin is the set of operations offered by Client. Code driving Keycloak should depend on this
+// interface rather than on *Client, so that it can be unit tested against the in-memory implementation
+// of the keycloaktest package.
+type KeycloakAdmin interface {
+	// Tokens
+	GetToken(realm string, username string, password string) (string, error)
+	GetTokenWithClientCredentials(realm string, clientID string, clientSecret string) (TokenResponse, error)
+	GetTokenWithClientAssertion(realm string, clientID string, clientAssertion string) (TokenResponse, error)
+	RefreshToken(realm string, refreshToken string, tokenReq TokenRequest) (TokenResponse, error)
+	ExchangeToken(realm string, tokenReq TokenRequest) (TokenResponse, error)
+	RequestToken(realm string, tokenReq TokenRequest) (TokenResponse, error)
+
+	// Realms
+	GetRealms(accessToken string) ([]RealmRepresentation, error)
+	CreateRealm(accessToken string, realm RealmRepresentation) (string, error)
+	GetRealm(accessToken string, realmName string) (RealmRepresentation, error)
+	UpdateRealm(accessToken string, realmName string, realm RealmRepresentation) error
+	DeleteRealm(accessToken string, realmName string) error
+	ExportRealm(accessToken string, realmName string) (RealmRepresentation, error)
+	PartialImport(accessToken string, realmName string, rep PartialImportRepresentation) (PartialImportResults, error)
+	PartialExport(accessToken string, realmName string, options PartialExportOptions) (RealmRepresentation, error)
+	GetRealmCredentialRegistrators(accessToken string, realmName string) ([]string, error)
+
+	// Users
+	GetUsers(accessToken string, targetRealmName string, query UserQuery) ([]UserRepresentation, error)
+	CreateUser(accessToken string, targetRealmName string, user UserRepresentation) (string, error)
+	CountUsers(accessToken string, realmName string) (int, error)
+	GetUser(accessToken string, realmName, userID string) (UserRepresentation, error)
+	GetGroupsOfUser(accessToken string, realmName, userID string) ([]GroupRepresentation, error)
+	AddGroupToUser(accessToken string, realmName, userID, groupID string) error
+	DeleteGroupFromUser(accessToken string, realmName, userID, groupID string) error
+	UpdateUser(accessToken string, realmName, userID string, user UserRepresentation) error
+	DeleteUser(accessToken string, realmName, userID string) error
+	ExecuteActionsEmail(accessToken string, realmName string, userID string, actions []string, options ActionsEmailOptions) error
+	SendSmsCode(accessToken string, realmName string, userID string) (SmsCodeRepresentation, error)
+	SendReminderEmail(accessToken string, realmName string, userID string, options ActionsEmailOptions) error
+	LinkShadowUser(accessToken string, reqRealmName string, userID string, provider string, fedIDKC FederatedIdentityRepresentation) error
+	SendSMS(accessToken string, realmName string, smsRep SMSRepresentation) error
+
+	// Credentials
+	ResetPassword(accessToken string, realmName, userID string, cred CredentialRepresentation) error
+	GetCredentials(accessToken string, realmName string, userID string) ([]CredentialRepresentation, error)
+	GetCredentialTypes(accessToken string, realmName string) ([]string, error)
+	UpdateLabelCredential(accessToken string, realmName string, userID string, credentialID string, label string) error
+	DeleteCredential(accessToken string, realmName string, userID string, credentialID string) error
+	MoveToFirst(accessToken string, realmName string, userID string, credentialID string) error
+	MoveAfter(accessToken string, realmName string, userID string, credentialID string, previousCredentialID string) error
+
+	// Groups
+	GetGroups(accessToken string, realmName string, query GroupQuery) ([]GroupRepresentation, error)
+	GetGroup(accessToken string, realmName string, groupID string) (GroupRepresentation, error)
+	CreateGroup(accessToken string, reqRealmName string, group GroupRepresentation) (string, error)
+	UpdateGroup(accessToken string, realmName string, groupID string, group GroupRepresentation) error
+	CreateChildGroup(accessToken string, realmName string, parentID string, group GroupRepresentation) (string, error)
+	MoveGroup(accessToken string, realmName string, groupID string, parentID string) error
+	GetGroupMembers(accessToken string, realmName string, groupID string, query PageQuery) ([]UserRepresentation, error)
+	GetGroupCount(accessToken string, realmName string, query GroupCountQuery) (int, error)
+	DeleteGroup(accessToken string, realmName string, groupID string) error
+	AssignClientRole(accessToken string, realmName string, groupID string, clientID string, roles []RoleRepresentation) error
+	RemoveClientRole(accessToken string, realmName string, groupID string, clientID string, roles []RoleRepresentation) error
+	GetGroupClientRoles(accessToken string, realmName string, groupID string, clientID string) ([]RoleRepresentation, error)
+	GetAvailableGroupClientRoles(accessToken string, realmName string, groupID string, clientID string) ([]RoleRepresentation, error)
+	AddGroupRealmRoles(accessToken string, realmName string, groupID string, roles []RoleRepresentation) error
+	DeleteGroupRealmRoles(accessToken string, realmName string, groupID string, roles []RoleRepresentation) error
+	GetGroupRealmRoles(accessToken string, realmName string, groupID string) ([]RoleRepresentation, error)
+	GetAvailableGroupRealmRoles(accessToken string, realmName string, groupID string) ([]RoleRepresentation, error)
+	GetEffectiveGroupRealmRoles(accessToken string, realmName string, groupID string) ([]RoleRepresentation, error)
+
+	// Clients
+	GetClients(accessToken string, realmName string, query ClientQuery) ([]ClientRepresentation, error)
+	GetClient(accessToken string, realmName, idClient string) (ClientRepresentation, error)
+	UpdateClient(accessToken string, realmName, idClient string, clientRep ClientRepresentation) error
+	CreateClient(accessToken string, realmName string, clientRep ClientRepresentation) (string, error)
+	GetClientMappers(accessToken string, realmName, idClient string) ([]ClientMapperRepresentation, error)
+	GetSecret(accessToken string, realmName, idClient string) (CredentialRepresentation, error)
+	RegenerateSecret(accessToken string, realmName, idClient string) (CredentialRepresentation, error)
+	DeleteClient(accessToken string, realmName, idClient string) error
+	GetServiceAccountUser(accessToken string, realmName, idClient string) (UserRepresentation, error)
+	GetClientInstallationProvider(accessToken string, realmName, idClient, providerID string) ([]byte, error)
+	PushRevocation(accessToken string, realmName, idClient string) (GlobalRequestResult, error)
+	TestNodesAvailable(accessToken string, realmName, idClient string) (GlobalRequestResult, error)
+	GetClientProtocolMappers(accessToken string, realmName, idClient string) ([]ProtocolMapperRepresentation, error)
+	GetClientProtocolMapper(accessToken string, realmName, idClient, mapperID string) (ProtocolMapperRepresentation, error)
+	CreateClientProtocolMapper(accessToken string, realmName, idClient string, mapper ProtocolMapperRepresentation) (string, error)
+	AddClientProtocolMappers(accessToken string, realmName, idClient string, mappers []ProtocolMapperRepresentation) error
+	UpdateClientProtocolMapper(accessToken string, realmName, idClient, mapperID string, mapper ProtocolMapperRepresentation) error
+	DeleteClientProtocolMapper(accessToken string, realmName, idClient, mapperID string) error
+
+	// Client scopes
+	GetClientScopes(accessToken string, realmName string) ([]ClientScopeRepresentation, error)
+	GetClientScope(accessToken string, realmName, scopeID string) (ClientScopeRepresentation, error)
+	CreateClientScope(accessToken string, realmName string, scope ClientScopeRepresentation) (string, error)
+	UpdateClientScope(accessToken string, realmName, scopeID string, scope ClientScopeRepresentation) error
+	DeleteClientScope(accessToken string, realmName, scopeID string) error
+	GetClientScopeMappers(accessToken string, realmName, scopeID string) ([]ProtocolMapperRepresentation, error)
+	GetClientScopeMapper(accessToken string, realmName, scopeID, mapperID string) (ProtocolMapperRepresentation, error)
+	CreateClientScopeMapper(accessToken string, realmName, scopeID string, mapper ProtocolMapperRepresentation) (string, error)
+	AddClientScopeMappers(accessToken string, realmName, scopeID string, mappers []ProtocolMapperRepresentation) error
+	UpdateClientScopeMapper(accessToken string, realmName, scopeID, mapperID string, mapper ProtocolMapperRepresentation) error
+	DeleteClientScopeMapper(accessToken string, realmName, scopeID, mapperID string) error
+	GetClientScopeScopeMappings(accessToken string, realmName, scopeID string) (MappingsRepresentation, error)
+	GetClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]RoleRepresentation, error)
+	AddClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string, roles []RoleRepresentation) error
+	DeleteClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string, roles []RoleRepresentation) error
+	GetAvailableClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]RoleRepresentation, error)
+	GetEffectiveClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]RoleRepresentation, error)
+	GetClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]RoleRepresentation, error)
+	AddClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string, roles []RoleRepresentation) error
+	DeleteClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string, roles []RoleRepresentation) error
+	GetAvailableClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]RoleRepresentation, error)
+	GetEffectiveClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]RoleRepresentation, error)
+	GetRealmDefaultClientScopes(accessToken string, realmName string) ([]ClientScopeRepresentation, error)
+	AddRealmDefaultClientScope(accessToken string, realmName, scopeID string) error
+	RemoveRealmDefaultClientScope(accessToken string, realmName, scopeID string) error
+	GetRealmOptionalClientScopes(accessToken string, realmName string) ([]ClientScopeRepresentation, error)
+	AddRealmOptionalClientScope(accessToken string, realmName, scopeID string) error
+	RemoveRealmOptionalClientScope(accessToken string, realmName, scopeID string) error
+	GetClientDefaultScopes(accessToken string, realmName, idClient string) ([]ClientScopeRepresentation, error)
+	AddClientDefaultScope(accessToken string, realmName, idClient, scopeID string) error
+	RemoveClientDefaultScope(accessToken string, realmName, idClient, scopeID string) error
+	GetClientOptionalScopes(accessToken string, realmName, idClient string) ([]ClientScopeRepresentation, error)
+	AddClientOptionalScope(accessToken string, realmName, idClient, scopeID string) error
+	RemoveClientOptionalScope(accessToken string, realmName, idClient, scopeID string) error
+
+	// Authorization services
+	GetResourceServer(accessToken string, realmName, idClient string) (ResourceServerRepresentation, error)
+	UpdateResourceServer(accessToken string, realmName, idClient string, resourceServer ResourceServerRepresentation) error
+	GetResources(accessToken string, realmName, idClient string, query ResourceQuery) ([]ResourceRepresentation, error)
+	GetResource(accessToken string, realmName, idClient, resourceID string) (ResourceRepresentation, error)
+	CreateResource(accessToken string, realmName, idClient string, resource ResourceRepresentation) (ResourceRepresentation, error)
+	UpdateResource(accessToken string, realmName, idClient, resourceID string, resource ResourceRepresentation) error
+	DeleteResource(accessToken string, realmName, idClient, resourceID string) error
+	GetAuthorizationScopes(accessToken string, realmName, idClient string, query AuthorizationScopeQuery) ([]ScopeRepresentation, error)
+	GetAuthorizationScope(accessToken string, realmName, idClient, scopeID string) (ScopeRepresentation, error)
+	CreateAuthorizationScope(accessToken string, realmName, idClient string, scope ScopeRepresentation) (ScopeRepresentation, error)
+	UpdateAuthorizationScope(accessToken string, realmName, idClient, scopeID string, scope ScopeRepresentation) error
+	DeleteAuthorizationScope(accessToken string, realmName, idClient, scopeID string) error
+	GetPolicies(accessToken string, realmName, idClient string, query PolicyQuery) ([]PolicyRepresentation, error)
+	GetPolicy(accessToken string, realmName, idClient, policyID string) (PolicyRepresentation, error)
+	GetTypedPolicy(accessToken string, realmName, idClient, policyID string, policy TypedPolicy) error
+	CreatePolicy(accessToken string, realmName, idClient string, policy TypedPolicy) (PolicyRepresentation, error)
+	UpdatePolicy(accessToken string, realmName, idClient, policyID string, policy TypedPolicy) error
+	DeletePolicy(accessToken string, realmName, idClient, policyID string) error
+	GetDependentPolicies(accessToken string, realmName, idClient, policyID string) ([]PolicyRepresentation, error)
+	GetPermissions(accessToken string, realmName, idClient string, query PolicyQuery) ([]PolicyRepresentation, error)
+	GetPermission(accessToken string, realmName, idClient, permissionID string) (PolicyRepresentation, error)
+	GetTypedPermission(accessToken string, realmName, idClient, permissionID string, permission TypedPolicy) error
+	CreatePermission(accessToken string, realmName, idClient string, permission TypedPolicy) (PolicyRepresentation, error)
+	UpdatePermission(accessToken string, realmName, idClient, permissionID string, permission TypedPolicy) error
+	DeletePermission(accessToken string, realmName, idClient, permissionID string) error
+	EvaluatePolicies(accessToken string, realmName, idClient string, request PolicyEvaluationRequest) (PolicyEvaluationResponse, error)
+
+	// Roles
+	GetClientRoles(accessToken string, realmName, idClient string) ([]RoleRepresentation, error)
+	CreateClientRole(accessToken string, realmName, clientID string, role RoleRepresentation) (string, error)
+	GetRoles(accessToken string, realmName string) ([]RoleRepresentation, error)
+	GetRole(accessToken string, realmName string, roleID string) (RoleRepresentation, error)
+	CreateRole(accessToken string, realmName string, role RoleRepresentation) (string, error)
+	GetRoleByName(accessToken string, realmName, roleName string) (RoleRepresentation, error)
+	UpdateRole(accessToken string, realmName, roleName string, role RoleRepresentation) error
+	UpdateRoleByID(accessToken string, realmName, roleID string, role RoleRepresentation) error
+	DeleteRole(accessToken string, realmName, roleName string) error
+	DeleteRoleByID(accessToken string, realmName, roleID string) error
+	GetRoleUsers(accessToken string, realmName, roleName string, query PageQuery) ([]UserRepresentation, error)
+	GetRoleGroups(accessToken string, realmName, roleName string, query PageQuery) ([]GroupRepresentation, error)
+	GetClientRole(accessToken string, realmName, idClient, roleName string) (RoleRepresentation, error)
+	UpdateClientRole(accessToken string, realmName, idClient, roleName string, role RoleRepresentation) error
+	DeleteClientRole(accessToken string, realmName, idClient, roleName string) error
+	GetClientRoleUsers(accessToken string, realmName, idClient, roleName string, query PageQuery) ([]UserRepresentation, error)
+	GetClientRoleGroups(accessToken string, realmName, idClient, roleName string, query PageQuery) ([]GroupRepresentation, error)
+	GetRoleComposites(accessToken string, realmName, roleID string) ([]RoleRepresentation, error)
+	GetRealmRoleComposites(accessToken string, realmName, roleID string) ([]RoleRepresentation, error)
+	GetClientRoleComposites(accessToken string, realmName, roleID, idClient string) ([]RoleRepresentation, error)
+	AddRoleComposites(accessToken string, realmName, roleID string, roles []RoleRepresentation) error
+	DeleteRoleComposites(accessToken string, realmName, roleID string, roles []RoleRepresentation) error
+
+	// User role mappings
+	GetRoleMappings(accessToken string, realmName, userID string) (MappingsRepresentation, error)
+	AddClientRolesToUserRoleMapping(accessToken string, realmName, userID, clientID string, roles []RoleRepresentation) error
+	GetClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]RoleRepresentation, error)
+	DeleteClientRolesFromUserRoleMapping(accessToken string, realmName, userID, clientID string, roles []RoleRepresentation) error
+	GetEffectiveClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]RoleRepresentation, error)
+	GetAvailableClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]RoleRepresentation, error)
+	GetRealmLevelRoleMappings(accessToken string, realmName, userID string) ([]RoleRepresentation, error)
+	AddRealmRolesToUserRoleMapping(accessToken string, realmName, userID string, roles []RoleRepresentation) error
+	DeleteRealmRolesFromUserRoleMapping(accessToken string, realmName, userID string, roles []RoleRepresentation) error
+	GetEffectiveRealmRoleMappings(accessToken string, realmName, userID string) ([]RoleRepresentation, error)
+	GetAvailableRealmRoleMappings(accessToken string, realmName, userID string) ([]RoleRepresentation, error)
+
+	// Components
+	GetComponents(accessToken string, realmName string) ([]ComponentRepresentation, error)
+	GetComponent(accessToken string, realmName string, componentID string) ([]ComponentRepresentation, error)
+	CreateComponent(accessToken string, realmName string, component ComponentRepresentation) (string, error)
+	UpdateComponent(accessToken string, realmName, componentID string, component ComponentRepresentation) error
+	DeleteComponent(accessToken string, realmName, componentID string) error
+
+	// User storage
+	TriggerFullSync(accessToken string, realmName, providerID string) (SynchronizationResult, error)
+	TriggerChangedUsersSync(accessToken string, realmName, providerID string) (SynchronizationResult, error)
+	RemoveImportedUsers(accessToken string, realmName, providerID string) error
+	UnlinkUsers(accessToken string, realmName, providerID string) error
+	SyncUserStorageMapper(accessToken string, realmName, providerID, mapperID, direction string) (SynchronizationResult, error)
+	TestLDAPConnection(accessToken string, realmName string, test LDAPConnectionTest) error
+
+	// Sessions
+	GetUserSessions(accessToken string, realmName, userID string) ([]UserSessionRepresentation, error)
+	GetUserOfflineSessions(accessToken string, realmName, userID, idClient string) ([]UserSessionRepresentation, error)
+	LogoutUser(accessToken string, realmName, userID string) error
+	DeleteSession(accessToken string, realmName, sessionID string) error
+	LogoutAll(accessToken string, realmName string) (GlobalRequestResult, error)
+	GetClientSessions(accessToken string, realmName, idClient string, query PageQuery) ([]UserSessionRepresentation, error)
+	GetClientOfflineSessions(accessToken string, realmName, idClient string, query PageQuery) ([]UserSessionRepresentation, error)
+	GetClientSessionCount(accessToken string, realmName, idClient string) (int, error)
+	GetClientOfflineSessionCount(accessToken string, realmName, idClient string) (int, error)
+
+	// Events
+	GetEvents(accessToken string, realmName string, query EventQuery) ([]EventRepresentation, error)
+	ClearEvents(accessToken string, realmName string) error
+	GetAdminEvents(accessToken string, realmName string, query AdminEventQuery) ([]AdminEventRepresentation, error)
+	ClearAdminEvents(accessToken string, realmName string) error
+	GetEventsConfig(accessToken string, realmName string) (RealmEventsConfigRepresentation, error)
+	UpdateEventsConfig(accessToken string, realmName string, config RealmEventsConfigRepresentation) error
+
+	// Identity providers
+	GetIdps(accessToken string, realmName string) ([]IdentityProviderRepresentation, error)
+	GetIdp(accessToken string, realmName string, idpAlias string) (IdentityProviderRepresentation, error)
+	CreateIdp(accessToken string, realmName string, idp IdentityProviderRepresentation) (string, error)
+	UpdateIdp(accessToken string, realmName string, idpAlias string, idp IdentityProviderRepresentation) error
+	DeleteIdp(accessToken string, realmName string, idpAlias string) error
+	GetIdpMappers(accessToken string, realmName string, idpAlias string) ([]IdentityProviderMapperRepresentation, error)
+	GetIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string) (IdentityProviderMapperRepresentation, error)
+	CreateIdpMapper(accessToken string, realmName string, idpAlias string, mapper IdentityProviderMapperRepresentation) (string, error)
+	UpdateIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string, mapper IdentityProviderMapperRepresentation) error
+	DeleteIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string) error
+	ImportIdpConfig(accessToken string, realmName string, providerID string, fromURL string) (map[string]interface{}, error)
+	ImportIdpConfigFromMetadata(accessToken string, realmName string, providerID string, metadata []byte) (map[string]interface{}, error)
+	GetIdpManagementPermissions(accessToken string, realmName string, idpAlias string) (ManagementPermissionReference, error)
+	SetIdpManagementPermissions(accessToken string, realmName string, idpAlias string, enabled bool) (ManagementPermissionReference, error)
+
+	// Authentication management
+	GetAuthenticatorProviders(accessToken string, realmName string) ([]map[string]interface{}, error)
+	GetClientAuthenticatorProviders(accessToken string, realmName string) ([]map[string]interface{}, error)
+	GetAuthenticatorProviderConfig(accessToken string, realmName, providerID string) (AuthenticatorConfigInfoRepresentation, error)
+	GetAuthenticatorConfig(accessToken string, realmName, configID string) (AuthenticatorConfigRepresentation, error)
+	UpdateAuthenticatorConfig(accessToken string, realmName, configID string, config AuthenticatorConfigRepresentation) error
+	DeleteAuthenticatorConfig(accessToken string, realmName, configID string) error
+	CreateAuthenticationExecution(accessToken string, realmName string, authExec AuthenticationExecutionRepresentation) (string, error)
+	DeleteAuthenticationExecution(accessToken string, realmName, executionID string) error
+	UpdateAuthenticationExecution(accessToken string, realmName, executionID string, authConfig AuthenticatorConfigRepresentation) error
+	LowerExecutionPriority(accessToken string, realmName, executionID string) error
+	RaiseExecutionPriority(accessToken string, realmName, executionID string) error
+	CreateAuthenticationFlow(accessToken string, realmName string, authFlow AuthenticationFlowRepresentation) error
+	GetAuthenticationFlows(accessToken string, realmName string) ([]AuthenticationFlowRepresentation, error)
+	CopyExistingAuthenticationFlow(accessToken string, realmName, flowAlias, newName string) error
+	GetAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias string) ([]AuthenticationExecutionInfoRepresentation, error)
+	UpdateAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias string, authExecInfo AuthenticationExecutionInfoRepresentation) error
+	CreateAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias, provider string) (string, error)
+	CreateFlowWithExecutionForExistingFlow(accessToken string, realmName, flowAlias, alias, flowType, provider, description string) (string, error)
+	GetAuthenticationFlow(accessToken string, realmName, flowID string) (AuthenticationFlowRepresentation, error)
+	UpdateAuthenticationFlow(accessToken string, realmName, flowID string, authFlow AuthenticationFlowRepresentation) error
+	DeleteAuthenticationFlow(accessToken string, realmName, flowID string) error
+	GetFormActionProviders(accessToken string, realmName string) ([]map[string]interface{}, error)
+	GetFormProviders(accessToken string, realmName string) ([]map[string]interface{}, error)
+	GetConfigDescriptionForClients(accessToken string, realmName string) (map[string]interface{}, error)
+	RegisterRequiredAction(accessToken string, realmName, providerID, name string) error
+	GetRequiredActions(accessToken string, realmName string) ([]RequiredActionProviderRepresentation, error)
+	GetRequiredAction(accessToken string, realmName, actionAlias string) (RequiredActionProviderRepresentation, error)
+	UpdateRequiredAction(accessToken string, realmName, actionAlias string, action RequiredActionProviderRepresentation) error
+	DeleteRequiredAction(accessToken string, realmName, actionAlias string) error
+	GetUnregisteredRequiredActions(accessToken string, realmName string) ([]map[string]interface{}, error)
+
+	// Attack detection
+	ClearAllLoginFailures(accessToken string, realmName string) error
+	GetAttackDetectionStatus(accessToken string, realmName, userID string) (map[string]interface{}, error)
+	ClearUserLoginFailures(accessToken string, realmName, userID string) error
+
+	// Client certificates
+	GetKeyInfo(accessToken string, realmName, idClient, attr string) (CertificateRepresentation, error)
+	GetKeyStore(accessToken string, realmName, idClient, attr string, keyStoreConfig KeyStoreConfig) ([]byte, error)
+	GenerateCertificate(accessToken string, realmName, idClient, attr string) (CertificateRepresentation, error)
+	GenerateKeyPairAndCertificate(accessToken string, realmName, idClient, attr string, keyStoreConfig KeyStoreConfig) ([]byte, error)
+	UploadCertificatePrivateKey(accessToken string, realmName, idClient, attr string, file []byte) (CertificateRepresentation, error)
+	UploadCertificate(accessToken string, realmName, idClient, attr string, file []byte) (CertificateRepresentation, error)
+
+	// Client initial access
+	CreateClientInitialAccess(accessToken string, realmName string, access ClientInitialAccessCreatePresentation) (ClientInitialAccessPresentation, error)
+	GetClientInitialAccess(accessToken string, realmName string) ([]ClientInitialAccessPresentation, error)
+	DeleteClientInitialAccess(accessToken string, realmName, accessID string) error
+
+	// Client registration policy
+	GetClientRegistrationPolicy(accessToken string, realmName, configID string) ([]ComponentTypeRepresentation, error)
+
+	// Recovery and activation codes
+	CreateRecoveryCode(accessToken string, realmName string, userID string) (RecoveryCodeRepresentation, error)
+	CreateActivationCode(accessToken string, realmName string, userID string) (ActivationCodeRepresentation, error)
+}
+
+var _ KeycloakAdmin = (*Client)(nil)
@@ -1,11 +1,14 @@
 package keycloak
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"net/url"
+	"time"
 
-	"github.com/pkg/errors"
 	"gopkg.in/h2non/gentleman.v2"
+	gcontext "gopkg.in/h2non/gentleman.v2/context"
 	"gopkg.in/h2non/gentleman.v2/plugin"
 	"gopkg.in/h2non/gentleman.v2/plugins/query"
 	"gopkg.in/h2non/gentleman.v2/plugins/timeout"
@@ -13,8 +16,11 @@ import (
 
 // Client is the keycloak client.
 type Client struct {
-	apiURL     *url.URL
-	httpClient *gentleman.Client
+	apiURL       *url.URL
+	httpClient   *gentleman.Client
+	ctx          context.Context
+	retryPolicy  RetryPolicy
+	tokenRenewer TokenRenewer
 }
 
 // NewClient returns a keycloak client.
@@ -24,7 +30,7 @@ func NewClient(config Config) (*Client, error) {
 		var err error
 		uAPI, err = url.Parse(config.AddrAPI)
 		if err != nil {
-			return nil, errors.Wrap(err, MsgErrCannotParse+"."+APIURL)
+			return nil, newError(MsgErrCannotParse, APIURL, err)
 		}
 	}
 
@@ -35,188 +41,160 @@ func NewClient(config Config) (*Client, error) {
 	}
 
 	var client = &Client{
-		apiURL:     uAPI,
-		httpClient: httpClient,
+		apiURL:      uAPI,
+		httpClient:  httpClient,
+		retryPolicy: config.Retry.withDefaults(),
 	}
 
 	return client, nil
 }
 
-// GetToken returns a valid token from keycloak
-func (c *Client) GetToken(realm string, username string, password string) (string, error) {
-	var req *gentleman.Request
-	{
-		var authPath = fmt.Sprintf("/auth/realms/%s/protocol/openid-connect/token", realm)
-		req = c.httpClient.Post()
-		req = req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
-		req = req.Path(authPath)
-		req = req.Type("urlencoded")
-		req = req.BodyString(fmt.Sprintf("username=%s&password=%s&grant_type=password&client_id=admin-cli", username, password))
-	}
-
-	var resp *gentleman.Response
-	{
-		var err error
-		resp, err = req.Do()
-		if err != nil {
-			return "", errors.Wrap(err, MsgErrCannotObtain+"."+TokenMsg)
-		}
-	}
-	defer resp.Close()
+// WithContext returns a shallow copy of the client whose requests are bound to ctx: they are
+// cancelled as soon as ctx is done and they honour its deadline, on top of Config.Timeout.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	var client = *c
+	client.ctx = ctx
+	return &client
+}
 
-	var unmarshalledBody map[string]interface{}
-	{
-		var err error
-		err = resp.JSON(&unmarshalledBody)
-		if err != nil {
-			return "", errors.Wrap(err, MsgErrCannotUnmarshal+"."+Response)
-		}
-	}
+// WithTokenProvider returns a shallow copy of the client which, when Keycloak rejects the access token of
+// a request with a 401, asks tokenProvider for a new token and replays the request with it, once.
+func (c *Client) WithTokenProvider(tokenProvider TokenRenewer) *Client {
+	var client = *c
+	client.tokenRenewer = tokenProvider
+	return &client
+}
 
-	var accessToken interface{}
-	{
-		var ok bool
-		accessToken, ok = unmarshalledBody["access_token"]
-		if !ok {
-			return "", fmt.Errorf(MsgErrMissingParam + "." + AccessToken)
-		}
+// Context returns the context bound to the client's requests.
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
 	}
-
-	return accessToken.(string), nil
+	return context.Background()
 }
 
 // get is a HTTP get method.
 func (c *Client) get(accessToken string, data interface{}, plugins ...plugin.Plugin) error {
-	var err error
-	var req = c.httpClient.Get()
-	req = applyPlugins(req, plugins...)
-	req = setAuthorisationHeader(req, accessToken)
-
+	var resp, err = c.do(http.MethodGet, accessToken, plugins...)
 	if err != nil {
 		return err
 	}
 
-	var resp *gentleman.Response
-	{
-		var err error
-		resp, err = req.Do()
-		if err != nil {
-			return errors.Wrap(err, MsgErrCannotObtain+"."+Response)
-		}
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-			return HTTPError{
-				HTTPStatus: resp.StatusCode,
-				Message:    string(resp.Bytes()),
-			}
-		}
+	switch resp.Header.Get("Content-Type") {
+	case "application/json":
+		return resp.JSON(data)
+	case "application/octet-stream":
+		_ = resp.Bytes()
+		return nil
+	default:
+		return newError(MsgErrUnkownHTTPContentType, resp.Header.Get("Content-Type"), nil)
+	}
+}
 
-		switch resp.Header.Get("Content-Type") {
-		case "application/json":
-			return resp.JSON(data)
-		case "application/octet-stream":
-			_ = resp.Bytes()
-			return nil
-		default:
-			return fmt.Errorf("%s.%v", MsgErrUnkownHTTPContentType, resp.Header.Get("Content-Type"))
-		}
+// getBytes returns the raw body of the response, whatever its content type.
+func (c *Client) getBytes(accessToken string, plugins ...plugin.Plugin) ([]byte, error) {
+	var resp, err = c.do(http.MethodGet, accessToken, plugins...)
+	if err != nil {
+		return nil, err
 	}
+	return resp.Bytes(), nil
 }
 
 func (c *Client) post(accessToken string, data interface{}, plugins ...plugin.Plugin) (string, error) {
-	var err error
-	var req = c.httpClient.Post()
-	req = applyPlugins(req, plugins...)
-	req = setAuthorisationHeader(req, accessToken)
-
+	var resp, err = c.do(http.MethodPost, accessToken, plugins...)
 	if err != nil {
 		return "", err
 	}
 
-	var resp *gentleman.Response
-	{
-		var err error
-		resp, err = req.Do()
-		if err != nil {
-			return "", errors.Wrap(err, MsgErrCannotObtain+"."+Response)
-		}
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-			return "", HTTPError{
-				HTTPStatus: resp.StatusCode,
-				Message:    string(resp.Bytes()),
-			}
-		}
+	var location = resp.Header.Get("Location")
 
-		var location = resp.Header.Get("Location")
-
-		switch resp.Header.Get("Content-Type") {
-		case "application/json":
-			return location, resp.JSON(data)
-		case "application/octet-stream":
-			data = resp.Bytes()
-			return location, nil
-		default:
-			return location, nil
-		}
+	switch resp.Header.Get("Content-Type") {
+	case "application/json":
+		return location, resp.JSON(data)
+	case "application/octet-stream":
+		data = resp.Bytes()
+		return location, nil
+	default:
+		return location, nil
 	}
 }
 
 func (c *Client) delete(accessToken string, plugins ...plugin.Plugin) error {
-	var err error
-	var req = c.httpClient.Delete()
-	req = applyPlugins(req, plugins...)
-	req = setAuthorisationHeader(req, accessToken)
+	var _, err = c.do(http.MethodDelete, accessToken, plugins...)
+	return err
+}
 
+func (c *Client) put(accessToken string, plugins ...plugin.Plugin) error {
+	var _, err = c.do(http.MethodPut, accessToken, plugins...)
+	return err
+}
+
+// putWithResponse is like put but decodes the JSON response, if any, into data.
+func (c *Client) putWithResponse(accessToken string, data interface{}, plugins ...plugin.Plugin) error {
+	var resp, err = c.do(http.MethodPut, accessToken, plugins...)
 	if err != nil {
 		return err
 	}
+	if resp.Header.Get("Content-Type") == "application/json" {
+		return resp.JSON(data)
+	}
+	return nil
+}
 
-	var resp *gentleman.Response
-	{
-		var err error
-		resp, err = req.Do()
+// do sends the request and returns the response if its status is 2xx or 3xx. The request is built anew for
+// each attempt: it is retried according to the retry policy of the client and, if the client has a token
+// provider, replayed once with a renewed token when Keycloak rejects the access token with a 401.
+func (c *Client) do(method string, accessToken string, plugins ...plugin.Plugin) (*gentleman.Response, error) {
+	var renewed = false
+	for attempt := 1; ; attempt++ {
+		var req = c.httpClient.Request().Method(method)
+		req = applyPlugins(req, plugins...)
+		req = setAuthorisationHeader(req, accessToken)
+		req = c.applyContext(req)
+
+		var resp, err = req.Do()
 		if err != nil {
-			return errors.Wrap(err, MsgErrCannotObtain+"."+Response)
+			err = newError(MsgErrCannotObtain, Response, err)
+			if c.retryPolicy.retryable(method, 0, attempt) && c.wait(c.retryPolicy.backoff(attempt)) {
+				continue
+			}
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && c.tokenRenewer != nil && !renewed {
+			renewed = true
+			if token, err := c.tokenRenewer.RenewToken(accessToken); err == nil && token != accessToken {
+				_ = resp.Close()
+				accessToken = token
+				attempt-- // The replay is not a retry.
+				continue
+			}
 		}
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-			return HTTPError{
-				HTTPStatus: resp.StatusCode,
-				Message:    string(resp.Bytes()),
+			var httpErr = newHTTPError(resp)
+			if c.retryPolicy.retryable(method, resp.StatusCode, attempt) {
+				if c.wait(c.retryPolicy.responseBackoff(resp.Header.Get("Retry-After"), time.Now(), attempt)) {
+					continue
+				}
 			}
+			return nil, httpErr
 		}
 
-		return nil
+		return resp, nil
 	}
 }
 
-func (c *Client) put(accessToken string, plugins ...plugin.Plugin) error {
-	var err error
-	var req = c.httpClient.Put()
-	req = applyPlugins(req, plugins...)
-	req = setAuthorisationHeader(req, accessToken)
-
-	if err != nil {
-		return err
-	}
-
-	var resp *gentleman.Response
-	{
-		var err error
-		resp, err = req.Do()
-		if err != nil {
-			return errors.Wrap(err, MsgErrCannotObtain+"."+Response)
-		}
+// wait waits for d, unless the context of the client is done first. It reports whether the full wait elapsed.
+func (c *Client) wait(d time.Duration) bool {
+	var timer = time.NewTimer(d)
+	defer timer.Stop()
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-			return HTTPError{
-				HTTPStatus: resp.StatusCode,
-				Message:    string(resp.Bytes()),
-			}
-		}
-
-		return nil
+	select {
+	case <-timer.C:
+		return true
+	case <-c.Context().Done():
+		return false
 	}
 }
 
@@ -226,6 +204,24 @@ func setAuthorisationHeader(req *gentleman.Request, accessToken string) *gentlem
 	return r
 }
 
+// applyContext binds the request to the context of the client, if any.
+func (c *Client) applyContext(req *gentleman.Request) *gentleman.Request {
+	if c.ctx == nil {
+		return req
+	}
+	return req.Use(withContext(c.ctx))
+}
+
+// withContext replaces the context of the outgoing http.Request with ctx. The gentleman data store
+// carried by the original context is preserved, so that the other plugins keep working.
+func withContext(ctx context.Context) plugin.Plugin {
+	return plugin.NewPhasePlugin("before dial", func(gctx *gcontext.Context, h gcontext.Handler) {
+		var store = gctx.Request.Context().Value(gcontext.Key)
+		gctx.Request = gctx.Request.WithContext(context.WithValue(ctx, gcontext.Key, store))
+		h.Next(gctx)
+	})
+}
+
 // applyPlugins apply all the plugins to the request req.
 func applyPlugins(req *gentleman.Request, plugins ...plugin.Plugin) *gentleman.Request {
 	var r = req
@@ -0,0 +1,114 @@
+package keycloak
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	var server = httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var client, err = NewClient(Config{AddrAPI: server.URL, Timeout: 5 * time.Second})
+	assert.Nil(t, err)
+	return client
+}
+
+func TestWithContext(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	}))
+
+	t.Run("Deadline exceeded", func(t *testing.T) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		var start = time.Now()
+		var _, err = client.WithContext(ctx).GetRealms("token")
+		assert.NotNil(t, err)
+		assert.True(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("Cancelled before the call", func(t *testing.T) {
+		var ctx, cancel = context.WithCancel(context.Background())
+		cancel()
+
+		var _, err = client.WithContext(ctx).GetRealms("token")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Original client is not bound to the context", func(t *testing.T) {
+		var ctx, cancel = context.WithCancel(context.Background())
+		cancel()
+		_ = client.WithContext(ctx)
+
+		assert.Equal(t, context.Background(), client.Context())
+	})
+}
+
+func TestHTTPError(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"errorMessage":"User exists with same username"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"HTTP 401 Unauthorized"}`))
+		}
+	}))
+
+	t.Run("Conflict", func(t *testing.T) {
+		var _, err = client.CreateUser("token", "test", UserRepresentation{})
+		assert.True(t, errors.Is(err, ErrConflict))
+		assert.False(t, errors.Is(err, ErrNotFound))
+
+		var httpErr HTTPError
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusConflict, httpErr.HTTPStatus)
+		assert.Equal(t, http.MethodPost, httpErr.Method)
+		assert.Equal(t, "/auth/admin/realms/test/users", httpErr.Path)
+		assert.Equal(t, "test", httpErr.Realm)
+		assert.Equal(t, "User exists with same username", httpErr.Detail())
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		var _, err = client.GetRealms("token")
+		assert.True(t, errors.Is(err, ErrUnauthorized))
+
+		var httpErr HTTPError
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, "", httpErr.Realm)
+		assert.Equal(t, "HTTP 401 Unauthorized", httpErr.ErrorCode)
+	})
+
+	t.Run("Transport error", func(t *testing.T) {
+		var ctx, cancel = context.WithCancel(context.Background())
+		cancel()
+
+		var _, err = client.WithContext(ctx).GetRealms("token")
+		assert.True(t, errors.Is(err, context.Canceled))
+
+		var clientErr Error
+		assert.True(t, errors.As(err, &clientErr))
+		assert.Equal(t, MsgErrCannotObtain, clientErr.Code)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		var _, err = client.GetUsers("token", "test", UserQuery{Max: -1})
+		assert.Equal(t, Error{Code: MsgErrInvalidParam, Detail: NegativePaging}, err)
+		assert.Equal(t, "invalidParameter.firstAndMaxCannotBeNegative", err.Error())
+	})
+}
@@ -0,0 +1,283 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// CreateAuthenticationFlow creates the flow. Its alias must be unique within the realm and, like Keycloak does,
+// its executions are ignored: they are added with CreateAuthenticationExecutionForFlow and
+// CreateFlowWithExecutionForExistingFlow.
+func (f *Fake) CreateAuthenticationFlow(accessToken string, realmName string, authFlow keycloak.AuthenticationFlowRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	_, err = r.createFlow(authFlow)
+	return err
+}
+
+func (r *realm) createFlow(authFlow keycloak.AuthenticationFlowRepresentation) (*keycloak.AuthenticationFlowRepresentation, error) {
+	if authFlow.Alias == nil || *authFlow.Alias == "" {
+		return nil, badRequest("Flow alias is missing")
+	}
+	if r.flowByAlias(*authFlow.Alias) != nil {
+		return nil, conflict("Flow " + *authFlow.Alias + " already exists")
+	}
+
+	var flow keycloak.AuthenticationFlowRepresentation
+	deepCopy(authFlow, &flow)
+	flow.ID = strPtr(newID())
+	flow.AuthenticationExecutions = &[]keycloak.AuthenticationExecutionExportRepresentation{}
+	if flow.ProviderID == nil {
+		flow.ProviderID = strPtr("basic-flow")
+	}
+	if flow.TopLevel == nil {
+		flow.TopLevel = boolPtr(true)
+	}
+	if flow.BuiltIn == nil {
+		flow.BuiltIn = boolPtr(false)
+	}
+	r.flows = append(r.flows, &flow)
+	return &flow, nil
+}
+
+// GetAuthenticationFlows returns the top level flows of the realm.
+func (f *Fake) GetAuthenticationFlows(accessToken string, realmName string) ([]keycloak.AuthenticationFlowRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.AuthenticationFlowRepresentation{}
+	for _, flow := range r.flows {
+		if *flow.TopLevel {
+			res = append(res, r.flowRepresentation(flow))
+		}
+	}
+	return res, nil
+}
+
+// GetAuthenticationFlow returns the flow.
+func (f *Fake) GetAuthenticationFlow(accessToken string, realmName, flowID string) (keycloak.AuthenticationFlowRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.AuthenticationFlowRepresentation{}, err
+	}
+	var i = r.flowIndex(flowID)
+	if i < 0 {
+		return keycloak.AuthenticationFlowRepresentation{}, notFound("Could not find flow with id")
+	}
+	return r.flowRepresentation(r.flows[i]), nil
+}
+
+// flowRepresentation returns the flow along with its executions.
+func (r *realm) flowRepresentation(flow *keycloak.AuthenticationFlowRepresentation) keycloak.AuthenticationFlowRepresentation {
+	var rep keycloak.AuthenticationFlowRepresentation
+	deepCopy(flow, &rep)
+	var executions = []keycloak.AuthenticationExecutionExportRepresentation{}
+	for i, e := range r.flowExecutions(*flow.ID) {
+		var exported = keycloak.AuthenticationExecutionExportRepresentation{
+			Requirement:       strPtr(e.requirement),
+			Priority:          int32Ptr(int32(10 * (i + 1))),
+			AuthenticatorFlow: boolPtr(e.subFlowID != ""),
+			UserSetupAllowed:  boolPtr(false),
+		}
+		if e.authenticator != "" {
+			exported.Authenticator = strPtr(e.authenticator)
+		}
+		if e.subFlowID != "" {
+			exported.FlowAlias = r.flows[r.flowIndex(e.subFlowID)].Alias
+		}
+		if e.configID != "" {
+			exported.AuthenticatorConfig = r.authenticatorConfigs[r.authenticatorConfigIndex(e.configID)].Alias
+		}
+		executions = append(executions, exported)
+	}
+	rep.AuthenticationExecutions = &executions
+	return rep
+}
+
+// UpdateAuthenticationFlow updates the alias and the description of the flow, unless it is built in. Its new
+// alias must be unique within the realm.
+func (f *Fake) UpdateAuthenticationFlow(accessToken string, realmName, flowID string, authFlow keycloak.AuthenticationFlowRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.flowIndex(flowID)
+	if i < 0 {
+		return notFound("Could not find flow with id")
+	}
+	if *r.flows[i].BuiltIn {
+		return badRequest("Can't update built in flow")
+	}
+	if authFlow.Alias != nil && *authFlow.Alias != *r.flows[i].Alias && r.flowByAlias(*authFlow.Alias) != nil {
+		return conflict("Flow " + *authFlow.Alias + " already exists")
+	}
+	merge(r.flows[i], keycloak.AuthenticationFlowRepresentation{Alias: authFlow.Alias, Description: authFlow.Description})
+	return nil
+}
+
+// DeleteAuthenticationFlow deletes the flow, along with its executions and sub flows, unless it is built in.
+func (f *Fake) DeleteAuthenticationFlow(accessToken string, realmName, flowID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.flowIndex(flowID)
+	if i < 0 {
+		return notFound("Could not find flow with id")
+	}
+	if *r.flows[i].BuiltIn {
+		return badRequest("Can't delete built in flow")
+	}
+	r.deleteFlow(flowID)
+	return nil
+}
+
+func (r *realm) flowIndex(flowID string) int {
+	for i, flow := range r.flows {
+		if *flow.ID == flowID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *realm) flowByAlias(alias string) *keycloak.AuthenticationFlowRepresentation {
+	for _, flow := range r.flows {
+		if *flow.Alias == alias {
+			return flow
+		}
+	}
+	return nil
+}
+
+// RegisterRequiredAction registers the required action, whose alias is its provider id. It is enabled and is
+// not a default action.
+func (f *Fake) RegisterRequiredAction(accessToken string, realmName, providerID, name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	return r.registerRequiredAction(keycloak.RequiredActionProviderRepresentation{
+		Alias:         strPtr(providerID),
+		Name:          strPtr(name),
+		ProviderID:    strPtr(providerID),
+		Enabled:       boolPtr(true),
+		DefaultAction: boolPtr(false),
+	})
+}
+
+func (r *realm) registerRequiredAction(action keycloak.RequiredActionProviderRepresentation) error {
+	if action.Alias == nil || *action.Alias == "" {
+		return badRequest("Required action alias is missing")
+	}
+	if r.requiredActionIndex(*action.Alias) >= 0 {
+		return conflict("Required action " + *action.Alias + " already exists")
+	}
+	var stored keycloak.RequiredActionProviderRepresentation
+	deepCopy(action, &stored)
+	if stored.Config == nil {
+		stored.Config = &map[string]interface{}{}
+	}
+	r.requiredActions = append(r.requiredActions, stored)
+	return nil
+}
+
+// GetRequiredActions returns the required actions registered in the realm.
+func (f *Fake) GetRequiredActions(accessToken string, realmName string) ([]keycloak.RequiredActionProviderRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.RequiredActionProviderRepresentation{}
+	deepCopy(r.requiredActions, &res)
+	return res, nil
+}
+
+// GetRequiredAction returns the required action.
+func (f *Fake) GetRequiredAction(accessToken string, realmName, actionAlias string) (keycloak.RequiredActionProviderRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var rep keycloak.RequiredActionProviderRepresentation
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return rep, err
+	}
+	var i = r.requiredActionIndex(actionAlias)
+	if i < 0 {
+		return rep, notFound("Failed to find required action")
+	}
+	deepCopy(r.requiredActions[i], &rep)
+	return rep, nil
+}
+
+// UpdateRequiredAction updates whether the required action is enabled or a default action, and its
+// configuration. Its alias, name and provider cannot change.
+func (f *Fake) UpdateRequiredAction(accessToken string, realmName, actionAlias string, action keycloak.RequiredActionProviderRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.requiredActionIndex(actionAlias)
+	if i < 0 {
+		return notFound("Failed to find required action")
+	}
+	merge(&r.requiredActions[i], keycloak.RequiredActionProviderRepresentation{
+		Enabled:       action.Enabled,
+		DefaultAction: action.DefaultAction,
+		Config:        action.Config,
+	})
+	return nil
+}
+
+// DeleteRequiredAction unregisters the required action.
+func (f *Fake) DeleteRequiredAction(accessToken string, realmName, actionAlias string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.requiredActionIndex(actionAlias)
+	if i < 0 {
+		return notFound("Failed to find required action")
+	}
+	r.requiredActions = append(r.requiredActions[:i], r.requiredActions[i+1:]...)
+	return nil
+}
+
+func (r *realm) requiredActionIndex(alias string) int {
+	for i, action := range r.requiredActions {
+		if *action.Alias == alias {
+			return i
+		}
+	}
+	return -1
+}
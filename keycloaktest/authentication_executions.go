@@ -0,0 +1,372 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// execution is an authenticator, or a sub flow, of a flow. The executions of a flow are ordered like in
+// realm.executions.
+type execution struct {
+	id            string
+	flowID        string
+	authenticator string
+	subFlowID     string
+	requirement   string
+	configID      string
+}
+
+// GetAuthenticationExecutionForFlow returns the executions of the flow and of its sub flows, depth first. The
+// display name of an authenticator is its provider id, that of a sub flow its alias.
+func (f *Fake) GetAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias string) ([]keycloak.AuthenticationExecutionInfoRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var flow = r.flowByAlias(flowAlias)
+	if flow == nil {
+		return nil, notFound("Flow not found")
+	}
+	var res = []keycloak.AuthenticationExecutionInfoRepresentation{}
+	r.executionInfos(*flow.ID, 0, &res)
+	return res, nil
+}
+
+func (r *realm) executionInfos(flowID string, level int32, res *[]keycloak.AuthenticationExecutionInfoRepresentation) {
+	for i, e := range r.flowExecutions(flowID) {
+		var info = keycloak.AuthenticationExecutionInfoRepresentation{
+			ID:          strPtr(e.id),
+			Requirement: strPtr(e.requirement),
+			Level:       int32Ptr(level),
+			Index:       int32Ptr(int32(i)),
+		}
+		if e.subFlowID != "" {
+			var sub = r.flows[r.flowIndex(e.subFlowID)]
+			info.AuthenticationFlow = boolPtr(true)
+			info.FlowID = strPtr(e.subFlowID)
+			info.DisplayName = strPtr(*sub.Alias)
+		} else {
+			info.ProviderID = strPtr(e.authenticator)
+			info.DisplayName = strPtr(e.authenticator)
+		}
+		if e.configID != "" {
+			info.AuthenticationConfig = strPtr(e.configID)
+			info.Alias = r.authenticatorConfigs[r.authenticatorConfigIndex(e.configID)].Alias
+		}
+		*res = append(*res, info)
+		if e.subFlowID != "" {
+			r.executionInfos(e.subFlowID, level+1, res)
+		}
+	}
+}
+
+// UpdateAuthenticationExecutionForFlow updates the requirement of the execution.
+func (f *Fake) UpdateAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias string, authExecInfo keycloak.AuthenticationExecutionInfoRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if r.flowByAlias(flowAlias) == nil {
+		return notFound("Parent flow doesn't exist")
+	}
+	var i = -1
+	if authExecInfo.ID != nil {
+		i = r.executionIndex(*authExecInfo.ID)
+	}
+	if i < 0 {
+		return notFound("Illegal execution")
+	}
+	if authExecInfo.Requirement != nil {
+		r.executions[i].requirement = *authExecInfo.Requirement
+	}
+	return nil
+}
+
+// CreateAuthenticationExecutionForFlow adds a disabled execution of the authenticator at the end of the flow.
+func (f *Fake) CreateAuthenticationExecutionForFlow(accessToken string, realmName, flowAlias, provider string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	var flow = r.flowByAlias(flowAlias)
+	if flow == nil {
+		return "", notFound("Parent flow doesn't exist")
+	}
+	var e = r.addExecution(&execution{flowID: *flow.ID, authenticator: provider})
+	return location(realmName, "authentication/executions", e.id), nil
+}
+
+// CreateFlowWithExecutionForExistingFlow adds a disabled sub flow at the end of the flow. Its alias must be
+// unique within the realm.
+func (f *Fake) CreateFlowWithExecutionForExistingFlow(accessToken string, realmName, flowAlias, alias, flowType, provider, description string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	var parent = r.flowByAlias(flowAlias)
+	if parent == nil {
+		return "", notFound("Parent flow doesn't exist")
+	}
+	sub, err := r.createFlow(keycloak.AuthenticationFlowRepresentation{
+		Alias:       strPtr(alias),
+		Description: strPtr(description),
+		ProviderID:  strPtr(flowType),
+		TopLevel:    boolPtr(false),
+	})
+	if err != nil {
+		return "", err
+	}
+	r.addExecution(&execution{flowID: *parent.ID, authenticator: provider, subFlowID: *sub.ID})
+	return location(realmName, "authentication/flows", *sub.ID), nil
+}
+
+// CreateAuthenticationExecution adds the execution at the end of its parent flow. It is disabled unless its
+// requirement is given.
+func (f *Fake) CreateAuthenticationExecution(accessToken string, realmName string, authExec keycloak.AuthenticationExecutionRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	if authExec.ParentFlow == nil || r.flowIndex(*authExec.ParentFlow) < 0 {
+		return "", badRequest("Parent flow doesn't exist")
+	}
+	var e = &execution{flowID: *authExec.ParentFlow}
+	if authExec.Authenticator != nil {
+		e.authenticator = *authExec.Authenticator
+	}
+	if authExec.AuthenticatorFlow != nil && *authExec.AuthenticatorFlow {
+		if authExec.FlowID == nil || r.flowIndex(*authExec.FlowID) < 0 {
+			return "", badRequest("Flow doesn't exist")
+		}
+		e.subFlowID = *authExec.FlowID
+	}
+	r.addExecution(e)
+	if authExec.Requirement != nil {
+		e.requirement = *authExec.Requirement
+	}
+	return location(realmName, "authentication/executions", e.id), nil
+}
+
+// DeleteAuthenticationExecution deletes the execution, along with its configuration and, for a sub flow, the
+// sub flow.
+func (f *Fake) DeleteAuthenticationExecution(accessToken string, realmName, executionID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.executionIndex(executionID)
+	if i < 0 {
+		return notFound("Illegal execution")
+	}
+	r.deleteExecution(i)
+	return nil
+}
+
+// RaiseExecutionPriority moves the execution before the previous one of its flow.
+func (f *Fake) RaiseExecutionPriority(accessToken string, realmName, executionID string) error {
+	return f.moveExecution(realmName, executionID, -1)
+}
+
+// LowerExecutionPriority moves the execution after the next one of its flow.
+func (f *Fake) LowerExecutionPriority(accessToken string, realmName, executionID string) error {
+	return f.moveExecution(realmName, executionID, 1)
+}
+
+func (f *Fake) moveExecution(realmName, executionID string, step int) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.executionIndex(executionID)
+	if i < 0 {
+		return notFound("Illegal execution")
+	}
+	for j := i + step; j >= 0 && j < len(r.executions); j += step {
+		if r.executions[j].flowID == r.executions[i].flowID {
+			r.executions[i], r.executions[j] = r.executions[j], r.executions[i]
+			break
+		}
+	}
+	return nil
+}
+
+// UpdateAuthenticationExecution creates the configuration of the execution, whose alias is mandatory.
+func (f *Fake) UpdateAuthenticationExecution(accessToken string, realmName, executionID string, authConfig keycloak.AuthenticatorConfigRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.executionIndex(executionID)
+	if i < 0 {
+		return notFound("Illegal execution")
+	}
+	config, err := r.createAuthenticatorConfig(authConfig)
+	if err != nil {
+		return err
+	}
+	r.executions[i].configID = *config.ID
+	return nil
+}
+
+// GetAuthenticatorConfig returns the configuration of an execution.
+func (f *Fake) GetAuthenticatorConfig(accessToken string, realmName, configID string) (keycloak.AuthenticatorConfigRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var rep keycloak.AuthenticatorConfigRepresentation
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return rep, err
+	}
+	var i = r.authenticatorConfigIndex(configID)
+	if i < 0 {
+		return rep, notFound("Could not find authenticator config")
+	}
+	deepCopy(r.authenticatorConfigs[i], &rep)
+	return rep, nil
+}
+
+// UpdateAuthenticatorConfig updates the alias and the values of the configuration.
+func (f *Fake) UpdateAuthenticatorConfig(accessToken string, realmName, configID string, config keycloak.AuthenticatorConfigRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.authenticatorConfigIndex(configID)
+	if i < 0 {
+		return notFound("Could not find authenticator config")
+	}
+	merge(r.authenticatorConfigs[i], keycloak.AuthenticatorConfigRepresentation{Alias: config.Alias, Config: config.Config})
+	return nil
+}
+
+// DeleteAuthenticatorConfig deletes the configuration, which its execution no longer references.
+func (f *Fake) DeleteAuthenticatorConfig(accessToken string, realmName, configID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if r.authenticatorConfigIndex(configID) < 0 {
+		return notFound("Could not find authenticator config")
+	}
+	r.deleteAuthenticatorConfig(configID)
+	return nil
+}
+
+func (r *realm) addExecution(e *execution) *execution {
+	e.id = newID()
+	if e.requirement == "" {
+		e.requirement = keycloak.RequirementDisabled
+	}
+	r.executions = append(r.executions, e)
+	return e
+}
+
+func (r *realm) flowExecutions(flowID string) []*execution {
+	var res []*execution
+	for _, e := range r.executions {
+		if e.flowID == flowID {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (r *realm) executionIndex(executionID string) int {
+	for i, e := range r.executions {
+		if e.id == executionID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *realm) deleteExecution(i int) {
+	var e = r.executions[i]
+	r.executions = append(r.executions[:i], r.executions[i+1:]...)
+	if e.configID != "" {
+		r.deleteAuthenticatorConfig(e.configID)
+	}
+	if e.subFlowID != "" {
+		r.deleteFlow(e.subFlowID)
+	}
+}
+
+// deleteFlow deletes the flow along with its executions, recursively.
+func (r *realm) deleteFlow(flowID string) {
+	for i := 0; i < len(r.executions); {
+		if r.executions[i].flowID == flowID {
+			// The executions of a sub flow may be before i.
+			r.deleteExecution(i)
+			i = 0
+		} else {
+			i++
+		}
+	}
+	if i := r.flowIndex(flowID); i >= 0 {
+		r.flows = append(r.flows[:i], r.flows[i+1:]...)
+	}
+}
+
+func (r *realm) createAuthenticatorConfig(config keycloak.AuthenticatorConfigRepresentation) (*keycloak.AuthenticatorConfigRepresentation, error) {
+	if config.Alias == nil || *config.Alias == "" {
+		return nil, badRequest("Failed to create authentication execution configuration with empty alias name")
+	}
+	var stored keycloak.AuthenticatorConfigRepresentation
+	deepCopy(config, &stored)
+	stored.ID = strPtr(newID())
+	if stored.Config == nil {
+		stored.Config = &map[string]interface{}{}
+	}
+	r.authenticatorConfigs = append(r.authenticatorConfigs, &stored)
+	return &stored, nil
+}
+
+func (r *realm) authenticatorConfigIndex(configID string) int {
+	for i, config := range r.authenticatorConfigs {
+		if *config.ID == configID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *realm) deleteAuthenticatorConfig(configID string) {
+	for _, e := range r.executions {
+		if e.configID == configID {
+			e.configID = ""
+		}
+	}
+	if i := r.authenticatorConfigIndex(configID); i >= 0 {
+		r.authenticatorConfigs = append(r.authenticatorConfigs[:i], r.authenticatorConfigs[i+1:]...)
+	}
+}
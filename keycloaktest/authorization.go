@@ -0,0 +1,821 @@
+package keycloaktest
+
+import (
+	"fmt"
+	"strings"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// updateResourceServer creates the resource server of the client once its authorization services are enabled,
+// and drops it once they are disabled. Unlike Keycloak, it creates no default resource nor policy.
+func (c *client) updateResourceServer() {
+	c.rep.AuthorizationSettings = nil
+	switch {
+	case c.rep.AuthorizationServicesEnabled == nil || !*c.rep.AuthorizationServicesEnabled:
+		c.authz = nil
+	case c.authz == nil:
+		c.authz = &resourceServer{rep: keycloak.ResourceServerRepresentation{
+			ID:                            strPtr(*c.rep.ID),
+			ClientID:                      strPtr(*c.rep.ID),
+			Name:                          strPtr(*c.rep.ClientID),
+			AllowRemoteResourceManagement: boolPtr(false),
+			PolicyEnforcementMode:         strPtr(keycloak.PolicyEnforcementModeEnforcing),
+			DecisionStrategy:              strPtr(keycloak.DecisionStrategyUnanimous),
+		}}
+	}
+}
+
+func (f *Fake) resourceServer(realmName, idClient string) (*client, *resourceServer, error) {
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.authz == nil {
+		return nil, nil, notFound("Authorization services are not enabled for the client")
+	}
+	return c, c.authz, nil
+}
+
+// GetResourceServer returns the authorization settings of the client.
+func (f *Fake) GetResourceServer(accessToken string, realmName, idClient string) (keycloak.ResourceServerRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return keycloak.ResourceServerRepresentation{}, err
+	}
+	var rep keycloak.ResourceServerRepresentation
+	deepCopy(rs.rep, &rep)
+	return rep, nil
+}
+
+// UpdateResourceServer updates the non nil settings of the client, but its resources, scopes and policies.
+func (f *Fake) UpdateResourceServer(accessToken string, realmName, idClient string, resourceServer keycloak.ResourceServerRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	resourceServer.ID, resourceServer.ClientID, resourceServer.Name = nil, nil, nil
+	resourceServer.Resources, resourceServer.Scopes, resourceServer.Policies = nil, nil, nil
+	merge(&rs.rep, resourceServer)
+	return nil
+}
+
+// GetResources returns the resources of the client. It supports all the fields of the query, URI patterns
+// ending with a * wildcard only.
+func (f *Fake) GetResources(accessToken string, realmName, idClient string, query keycloak.ResourceQuery) ([]keycloak.ResourceRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, rs, err := f.resourceServer(realmName, idClient)
+	if err != nil {
+		return nil, err
+	}
+	var matches []keycloak.ResourceRepresentation
+	for _, resource := range rs.resources {
+		if resourceMatches(resource, query) {
+			matches = append(matches, resource)
+		}
+	}
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.ResourceRepresentation{}
+	deepCopy(matches[first:last], &res)
+	return res, nil
+}
+
+func resourceMatches(resource keycloak.ResourceRepresentation, query keycloak.ResourceQuery) bool {
+	switch {
+	case query.Name != "" && !containsFold(resource.Name, query.Name):
+		return false
+	case query.Type != "" && (resource.Type == nil || *resource.Type != query.Type):
+		return false
+	case query.Owner != "" && *resource.Owner.ID != query.Owner && *resource.Owner.Name != query.Owner:
+		return false
+	case query.URI != "" && !uriMatches(resource, query.URI, query.MatchingURI):
+		return false
+	case query.Scope != "":
+		for _, scope := range *resource.Scopes {
+			if containsFold(scope.Name, query.Scope) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func uriMatches(resource keycloak.ResourceRepresentation, uri string, matching bool) bool {
+	for _, candidate := range *resource.URIs {
+		switch {
+		case candidate == uri:
+			return true
+		case matching && strings.HasSuffix(candidate, "*") && strings.HasPrefix(uri, strings.TrimSuffix(candidate, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// GetResource returns a resource of the client.
+func (f *Fake) GetResource(accessToken string, realmName, idClient, resourceID string) (keycloak.ResourceRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return keycloak.ResourceRepresentation{}, err
+	}
+	resource, err := rs.resource(resourceID)
+	if err != nil {
+		return keycloak.ResourceRepresentation{}, err
+	}
+	var rep keycloak.ResourceRepresentation
+	deepCopy(*resource, &rep)
+	return rep, nil
+}
+
+// CreateResource creates a resource owned by the client. Its name must be unique within the client, and its
+// scopes, referred to by id or name, are created if they do not exist.
+func (f *Fake) CreateResource(accessToken string, realmName, idClient string, resource keycloak.ResourceRepresentation) (keycloak.ResourceRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return keycloak.ResourceRepresentation{}, err
+	}
+	if resource.Name == nil || *resource.Name == "" {
+		return keycloak.ResourceRepresentation{}, badRequest("Name is required")
+	}
+	if err = rs.checkResourceUniqueness(*resource.Name, ""); err != nil {
+		return keycloak.ResourceRepresentation{}, err
+	}
+
+	var stored keycloak.ResourceRepresentation
+	deepCopy(resource, &stored)
+	stored.ID = strPtr(newID())
+	if stored.Owner == nil {
+		stored.Owner = &keycloak.ResourceOwnerRepresentation{ID: strPtr(*c.rep.ID), Name: strPtr(*c.rep.ClientID)}
+	}
+	if stored.OwnerManagedAccess == nil {
+		stored.OwnerManagedAccess = boolPtr(false)
+	}
+	rs.normalizeResource(&stored)
+	rs.resources = append(rs.resources, stored)
+
+	var rep keycloak.ResourceRepresentation
+	deepCopy(stored, &rep)
+	return rep, nil
+}
+
+// UpdateResource updates the non nil fields of a resource of the client.
+func (f *Fake) UpdateResource(accessToken string, realmName, idClient, resourceID string, resource keycloak.ResourceRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	existing, err := rs.resource(resourceID)
+	if err != nil {
+		return err
+	}
+	if resource.Name != nil {
+		if err = rs.checkResourceUniqueness(*resource.Name, resourceID); err != nil {
+			return err
+		}
+	}
+	resource.ID = nil
+	merge(existing, resource)
+	rs.normalizeResource(existing)
+	return nil
+}
+
+// DeleteResource deletes a resource of the client and removes it from the permissions.
+func (f *Fake) DeleteResource(accessToken string, realmName, idClient, resourceID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	if _, err = rs.resource(resourceID); err != nil {
+		return err
+	}
+	var resources []keycloak.ResourceRepresentation
+	for _, resource := range rs.resources {
+		if *resource.ID != resourceID {
+			resources = append(resources, resource)
+		}
+	}
+	rs.resources = resources
+	rs.removeReferences("resources", resourceID)
+	return nil
+}
+
+// normalizeResource moves the legacy URI to URIs and resolves the scopes, creating the missing ones.
+func (rs *resourceServer) normalizeResource(resource *keycloak.ResourceRepresentation) {
+	if resource.URI != nil {
+		resource.URIs = &[]string{*resource.URI}
+		resource.URI = nil
+	}
+	if resource.URIs == nil {
+		resource.URIs = &[]string{}
+	}
+	var scopes = []keycloak.ScopeRepresentation{}
+	if resource.Scopes != nil {
+		for _, scope := range *resource.Scopes {
+			var stored = rs.findScope(scope.ID, scope.Name)
+			if stored == nil {
+				rs.scopes = append(rs.scopes, keycloak.ScopeRepresentation{ID: strPtr(newID()), Name: strPtr(*scope.Name)})
+				stored = &rs.scopes[len(rs.scopes)-1]
+			}
+			scopes = append(scopes, keycloak.ScopeRepresentation{ID: strPtr(*stored.ID), Name: strPtr(*stored.Name)})
+		}
+	}
+	resource.Scopes = &scopes
+}
+
+func (rs *resourceServer) resource(resourceID string) (*keycloak.ResourceRepresentation, error) {
+	for i := range rs.resources {
+		if *rs.resources[i].ID == resourceID {
+			return &rs.resources[i], nil
+		}
+	}
+	return nil, notFound("Resource not found")
+}
+
+func (rs *resourceServer) checkResourceUniqueness(name string, resourceID string) error {
+	for _, other := range rs.resources {
+		if *other.ID != resourceID && *other.Name == name {
+			return conflict(fmt.Sprintf("Resource with name [%s] already exists.", name))
+		}
+	}
+	return nil
+}
+
+// GetAuthorizationScopes returns the authorization scopes of the client.
+func (f *Fake) GetAuthorizationScopes(accessToken string, realmName, idClient string, query keycloak.AuthorizationScopeQuery) ([]keycloak.ScopeRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, rs, err := f.resourceServer(realmName, idClient)
+	if err != nil {
+		return nil, err
+	}
+	var matches []keycloak.ScopeRepresentation
+	for _, scope := range rs.scopes {
+		if query.Name == "" || containsFold(scope.Name, query.Name) {
+			matches = append(matches, scope)
+		}
+	}
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.ScopeRepresentation{}
+	deepCopy(matches[first:last], &res)
+	return res, nil
+}
+
+// GetAuthorizationScope returns an authorization scope of the client.
+func (f *Fake) GetAuthorizationScope(accessToken string, realmName, idClient, scopeID string) (keycloak.ScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return keycloak.ScopeRepresentation{}, err
+	}
+	var scope = rs.findScope(&scopeID, nil)
+	if scope == nil {
+		return keycloak.ScopeRepresentation{}, notFound("Scope not found")
+	}
+	var rep keycloak.ScopeRepresentation
+	deepCopy(*scope, &rep)
+	return rep, nil
+}
+
+// CreateAuthorizationScope creates an authorization scope. Its name must be unique within the client.
+func (f *Fake) CreateAuthorizationScope(accessToken string, realmName, idClient string, scope keycloak.ScopeRepresentation) (keycloak.ScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return keycloak.ScopeRepresentation{}, err
+	}
+	if scope.Name == nil || *scope.Name == "" {
+		return keycloak.ScopeRepresentation{}, badRequest("Name is required")
+	}
+	if err = rs.checkScopeUniqueness(*scope.Name, ""); err != nil {
+		return keycloak.ScopeRepresentation{}, err
+	}
+
+	var stored keycloak.ScopeRepresentation
+	deepCopy(scope, &stored)
+	stored.ID = strPtr(newID())
+	stored.Resources, stored.Policies = nil, nil
+	rs.scopes = append(rs.scopes, stored)
+
+	var rep keycloak.ScopeRepresentation
+	deepCopy(stored, &rep)
+	return rep, nil
+}
+
+// UpdateAuthorizationScope updates the non nil fields of an authorization scope of the client.
+func (f *Fake) UpdateAuthorizationScope(accessToken string, realmName, idClient, scopeID string, scope keycloak.ScopeRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	var existing = rs.findScope(&scopeID, nil)
+	if existing == nil {
+		return notFound("Scope not found")
+	}
+	if scope.Name != nil {
+		if err = rs.checkScopeUniqueness(*scope.Name, scopeID); err != nil {
+			return err
+		}
+		for i := range rs.resources {
+			for j, resourceScope := range *rs.resources[i].Scopes {
+				if *resourceScope.ID == scopeID {
+					(*rs.resources[i].Scopes)[j].Name = strPtr(*scope.Name)
+				}
+			}
+		}
+	}
+	scope.ID, scope.Resources, scope.Policies = nil, nil, nil
+	merge(existing, scope)
+	return nil
+}
+
+// DeleteAuthorizationScope deletes an authorization scope of the client and removes it from the resources and
+// the permissions.
+func (f *Fake) DeleteAuthorizationScope(accessToken string, realmName, idClient, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	if rs.findScope(&scopeID, nil) == nil {
+		return notFound("Scope not found")
+	}
+	var scopes []keycloak.ScopeRepresentation
+	for _, scope := range rs.scopes {
+		if *scope.ID != scopeID {
+			scopes = append(scopes, scope)
+		}
+	}
+	rs.scopes = scopes
+	for i := range rs.resources {
+		var kept = []keycloak.ScopeRepresentation{}
+		for _, scope := range *rs.resources[i].Scopes {
+			if *scope.ID != scopeID {
+				kept = append(kept, scope)
+			}
+		}
+		rs.resources[i].Scopes = &kept
+	}
+	rs.removeReferences("scopes", scopeID)
+	return nil
+}
+
+// findScope returns the scope having the id, or else the name, if any.
+func (rs *resourceServer) findScope(scopeID *string, name *string) *keycloak.ScopeRepresentation {
+	for i, scope := range rs.scopes {
+		if (scopeID != nil && *scope.ID == *scopeID) || (scopeID == nil && name != nil && *scope.Name == *name) {
+			return &rs.scopes[i]
+		}
+	}
+	return nil
+}
+
+func (rs *resourceServer) checkScopeUniqueness(name string, scopeID string) error {
+	for _, other := range rs.scopes {
+		if *other.ID != scopeID && *other.Name == name {
+			return conflict(fmt.Sprintf("Scope with name [%s] already exists.", name))
+		}
+	}
+	return nil
+}
+
+// GetPolicies returns the policies of the client, but its permissions. It supports all the fields of the query
+// but Owner.
+func (f *Fake) GetPolicies(accessToken string, realmName, idClient string, query keycloak.PolicyQuery) ([]keycloak.PolicyRepresentation, error) {
+	return f.getPolicies(realmName, idClient, query, false)
+}
+
+// GetPermissions returns the permissions of the client. It supports all the fields of the query but Owner.
+func (f *Fake) GetPermissions(accessToken string, realmName, idClient string, query keycloak.PolicyQuery) ([]keycloak.PolicyRepresentation, error) {
+	return f.getPolicies(realmName, idClient, query, true)
+}
+
+func (f *Fake) getPolicies(realmName, idClient string, query keycloak.PolicyQuery, permissions bool) ([]keycloak.PolicyRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, rs, err := f.resourceServer(realmName, idClient)
+	if err != nil {
+		return nil, err
+	}
+	var matches []policy
+	for _, p := range rs.policies {
+		if p.isPermission() == permissions && rs.policyMatches(p, query) {
+			matches = append(matches, p)
+		}
+	}
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.PolicyRepresentation{}
+	for _, p := range matches[first:last] {
+		res = append(res, p.representation())
+	}
+	return res, nil
+}
+
+func (rs *resourceServer) policyMatches(p policy, query keycloak.PolicyQuery) bool {
+	switch {
+	case query.Name != "" && !strings.Contains(strings.ToLower(p.name()), strings.ToLower(query.Name)):
+		return false
+	case query.Type != "" && p.policyType() != query.Type:
+		return false
+	case query.Resource != "":
+		var resource = rs.resourceByIDOrName(query.Resource)
+		return resource != nil && contains(p.references("resources"), *resource.ID)
+	case query.Scope != "":
+		var scope = rs.findScope(&query.Scope, nil)
+		if scope == nil {
+			scope = rs.findScope(nil, &query.Scope)
+		}
+		return scope != nil && contains(p.references("scopes"), *scope.ID)
+	default:
+		return true
+	}
+}
+
+// GetPolicy returns a policy of the client. Unlike Keycloak, it leaves its Config empty.
+func (f *Fake) GetPolicy(accessToken string, realmName, idClient, policyID string) (keycloak.PolicyRepresentation, error) {
+	return f.getPolicy(realmName, idClient, policyID, false)
+}
+
+// GetPermission returns a permission of the client.
+func (f *Fake) GetPermission(accessToken string, realmName, idClient, permissionID string) (keycloak.PolicyRepresentation, error) {
+	return f.getPolicy(realmName, idClient, permissionID, true)
+}
+
+func (f *Fake) getPolicy(realmName, idClient, policyID string, permission bool) (keycloak.PolicyRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return keycloak.PolicyRepresentation{}, err
+	}
+	p, err := rs.policy(policyID, permission, "")
+	if err != nil {
+		return keycloak.PolicyRepresentation{}, err
+	}
+	return p.representation(), nil
+}
+
+// GetTypedPolicy decodes a policy of the client into policy, which must be of its type.
+func (f *Fake) GetTypedPolicy(accessToken string, realmName, idClient, policyID string, policy keycloak.TypedPolicy) error {
+	return f.getTypedPolicy(realmName, idClient, policyID, policy, false)
+}
+
+// GetTypedPermission decodes a permission of the client into permission, which must be of its type.
+func (f *Fake) GetTypedPermission(accessToken string, realmName, idClient, permissionID string, permission keycloak.TypedPolicy) error {
+	return f.getTypedPolicy(realmName, idClient, permissionID, permission, true)
+}
+
+func (f *Fake) getTypedPolicy(realmName, idClient, policyID string, typed keycloak.TypedPolicy, permission bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	p, err := rs.policy(policyID, permission, typed.PolicyType())
+	if err != nil {
+		return err
+	}
+	deepCopy(p, typed)
+	return nil
+}
+
+// CreatePolicy creates a policy. Its name must be unique within the client, and the policies it aggregates are
+// referred to by id or name.
+func (f *Fake) CreatePolicy(accessToken string, realmName, idClient string, policy keycloak.TypedPolicy) (keycloak.PolicyRepresentation, error) {
+	if keycloak.IsPermissionType(policy.PolicyType()) {
+		return keycloak.PolicyRepresentation{}, policyTypeError()
+	}
+	return f.createPolicy(realmName, idClient, policy)
+}
+
+// CreatePermission creates a permission. Its name must be unique within the client, and its resources, scopes
+// and policies are referred to by id or name.
+func (f *Fake) CreatePermission(accessToken string, realmName, idClient string, permission keycloak.TypedPolicy) (keycloak.PolicyRepresentation, error) {
+	if !keycloak.IsPermissionType(permission.PolicyType()) {
+		return keycloak.PolicyRepresentation{}, policyTypeError()
+	}
+	return f.createPolicy(realmName, idClient, permission)
+}
+
+func (f *Fake) createPolicy(realmName, idClient string, typed keycloak.TypedPolicy) (keycloak.PolicyRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return keycloak.PolicyRepresentation{}, err
+	}
+	var p = policy{}
+	deepCopy(typed, &p)
+	if p.name() == "" {
+		return keycloak.PolicyRepresentation{}, badRequest("Name is required")
+	}
+	if err = rs.checkPolicyUniqueness(p.name(), ""); err != nil {
+		return keycloak.PolicyRepresentation{}, err
+	}
+	if err = rs.resolvePolicyReferences(p); err != nil {
+		return keycloak.PolicyRepresentation{}, err
+	}
+	p["id"] = newID()
+	p["type"] = typed.PolicyType()
+	if p["logic"] == nil {
+		p["logic"] = keycloak.LogicPositive
+	}
+	if p["decisionStrategy"] == nil {
+		p["decisionStrategy"] = keycloak.DecisionStrategyUnanimous
+	}
+	rs.policies = append(rs.policies, p)
+	return p.representation(), nil
+}
+
+// UpdatePolicy updates the non nil fields of a policy of the client.
+func (f *Fake) UpdatePolicy(accessToken string, realmName, idClient, policyID string, policy keycloak.TypedPolicy) error {
+	if keycloak.IsPermissionType(policy.PolicyType()) {
+		return policyTypeError()
+	}
+	return f.updatePolicy(realmName, idClient, policyID, policy, false)
+}
+
+// UpdatePermission updates the non nil fields of a permission of the client.
+func (f *Fake) UpdatePermission(accessToken string, realmName, idClient, permissionID string, permission keycloak.TypedPolicy) error {
+	if !keycloak.IsPermissionType(permission.PolicyType()) {
+		return policyTypeError()
+	}
+	return f.updatePolicy(realmName, idClient, permissionID, permission, true)
+}
+
+func (f *Fake) updatePolicy(realmName, idClient, policyID string, typed keycloak.TypedPolicy, permission bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	existing, err := rs.policy(policyID, permission, typed.PolicyType())
+	if err != nil {
+		return err
+	}
+	var update = policy{}
+	deepCopy(typed, &update)
+	delete(update, "id")
+	delete(update, "type")
+	if name, ok := update["name"].(string); ok {
+		if err = rs.checkPolicyUniqueness(name, policyID); err != nil {
+			return err
+		}
+	}
+	if err = rs.resolvePolicyReferences(update); err != nil {
+		return err
+	}
+	for key, value := range update {
+		existing[key] = value
+	}
+	return nil
+}
+
+// DeletePolicy deletes a policy or a permission of the client and removes it from the other policies.
+func (f *Fake) DeletePolicy(accessToken string, realmName, idClient, policyID string) error {
+	return f.deletePolicy(realmName, idClient, policyID, false)
+}
+
+// DeletePermission deletes a permission of the client.
+func (f *Fake) DeletePermission(accessToken string, realmName, idClient, permissionID string) error {
+	return f.deletePolicy(realmName, idClient, permissionID, true)
+}
+
+func (f *Fake) deletePolicy(realmName, idClient, policyID string, permission bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	var found = false
+	var policies []policy
+	for _, p := range rs.policies {
+		switch {
+		case p.id() != policyID:
+			policies = append(policies, p)
+		case permission && !p.isPermission():
+			return notFound("Policy not found")
+		default:
+			found = true
+		}
+	}
+	if !found {
+		return notFound("Policy not found")
+	}
+	rs.policies = policies
+	rs.removeReferences("policies", policyID)
+	return nil
+}
+
+// GetDependentPolicies returns the policies and permissions which aggregate a policy of the client.
+func (f *Fake) GetDependentPolicies(accessToken string, realmName, idClient, policyID string) ([]keycloak.PolicyRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, rs, err = f.resourceServer(realmName, idClient)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = rs.policy(policyID, false, ""); err != nil {
+		return nil, err
+	}
+	var res = []keycloak.PolicyRepresentation{}
+	for _, p := range rs.policies {
+		if contains(p.references("policies"), policyID) {
+			res = append(res, p.representation())
+		}
+	}
+	return res, nil
+}
+
+// policy is the JSON of a typed policy, which keeps the fields specific to its type.
+type policy map[string]interface{}
+
+func (p policy) id() string {
+	var id, _ = p["id"].(string)
+	return id
+}
+
+func (p policy) name() string {
+	var name, _ = p["name"].(string)
+	return name
+}
+
+func (p policy) policyType() string {
+	var policyType, _ = p["type"].(string)
+	return policyType
+}
+
+func (p policy) isPermission() bool {
+	return keycloak.IsPermissionType(p.policyType())
+}
+
+func (p policy) representation() keycloak.PolicyRepresentation {
+	var rep keycloak.PolicyRepresentation
+	deepCopy(p, &rep)
+	return rep
+}
+
+// references returns the ids of the resources, scopes or policies of the policy.
+func (p policy) references(key string) []string {
+	var ids []string
+	var values, _ = p[key].([]interface{})
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (p policy) setReferences(key string, ids []string) {
+	var values = []interface{}{}
+	for _, id := range ids {
+		values = append(values, id)
+	}
+	p[key] = values
+}
+
+// policy returns the policy having the id. Policies may be read as permissions, and vice versa, by id only.
+func (rs *resourceServer) policy(policyID string, permission bool, policyType string) (policy, error) {
+	for _, p := range rs.policies {
+		if p.id() != policyID {
+			continue
+		}
+		if (permission && !p.isPermission()) || (policyType != "" && p.policyType() != policyType) {
+			break
+		}
+		return p, nil
+	}
+	return nil, notFound("Policy not found")
+}
+
+func (rs *resourceServer) checkPolicyUniqueness(name string, policyID string) error {
+	for _, other := range rs.policies {
+		if other.id() != policyID && other.name() == name {
+			return conflict(fmt.Sprintf("Policy with name [%s] already exists", name))
+		}
+	}
+	return nil
+}
+
+// resolvePolicyReferences replaces the names of the resources, scopes and policies of the policy by their ids.
+func (rs *resourceServer) resolvePolicyReferences(p policy) error {
+	var lookups = map[string]func(string) *string{
+		"resources": func(ref string) *string {
+			if resource := rs.resourceByIDOrName(ref); resource != nil {
+				return resource.ID
+			}
+			return nil
+		},
+		"scopes": func(ref string) *string {
+			if scope := rs.findScope(&ref, nil); scope != nil {
+				return scope.ID
+			}
+			if scope := rs.findScope(nil, &ref); scope != nil {
+				return scope.ID
+			}
+			return nil
+		},
+		"policies": func(ref string) *string {
+			for _, other := range rs.policies {
+				if other.id() == ref || other.name() == ref {
+					return strPtr(other.id())
+				}
+			}
+			return nil
+		},
+	}
+	for key, lookup := range lookups {
+		if _, ok := p[key]; !ok {
+			continue
+		}
+		var ids []string
+		for _, ref := range p.references(key) {
+			var id = lookup(ref)
+			if id == nil {
+				return badRequest(fmt.Sprintf("Could not find %s [%s]", strings.TrimSuffix(key, "s"), ref))
+			}
+			ids = append(ids, *id)
+		}
+		p.setReferences(key, ids)
+	}
+	return nil
+}
+
+func (rs *resourceServer) resourceByIDOrName(ref string) *keycloak.ResourceRepresentation {
+	for i, resource := range rs.resources {
+		if *resource.ID == ref || *resource.Name == ref {
+			return &rs.resources[i]
+		}
+	}
+	return nil
+}
+
+// removeReferences removes a resource, scope or policy from the policies referring to it.
+func (rs *resourceServer) removeReferences(key string, id string) {
+	for _, p := range rs.policies {
+		if contains(p.references(key), id) {
+			p.setReferences(key, remove(p.references(key), id))
+		}
+	}
+}
+
+func policyTypeError() error {
+	return keycloak.Error{Code: keycloak.MsgErrInvalidParam, Detail: keycloak.PolicyType}
+}
@@ -0,0 +1,270 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// AddClientRolesToUserRoleMapping grants client roles to the user.
+func (f *Fake) AddClientRolesToUserRoleMapping(accessToken string, realmName, userID, clientID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, c, err = f.userAndClient(realmName, userID, clientID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(c.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !contains(u.clientRoles[clientID], id) {
+			u.clientRoles[clientID] = append(u.clientRoles[clientID], id)
+		}
+	}
+	return nil
+}
+
+// GetClientRoleMappings returns the client roles granted to the user.
+func (f *Fake) GetClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, c, err = f.userAndClient(realmName, userID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(c.roles, u.clientRoles[clientID]), nil
+}
+
+// DeleteClientRolesFromUserRoleMapping revokes client roles from the user.
+func (f *Fake) DeleteClientRolesFromUserRoleMapping(accessToken string, realmName, userID, clientID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, c, err = f.userAndClient(realmName, userID, clientID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(c.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		u.clientRoles[clientID] = remove(u.clientRoles[clientID], id)
+	}
+	return nil
+}
+
+// GetEffectiveClientRoleMappings returns the client roles granted to the user, directly, through composite roles
+// or through groups.
+func (f *Fake) GetEffectiveClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, c, err = f.userAndClient(realmName, userID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(c.roles, f.realms[realmName].effectiveRoles(u)), nil
+}
+
+// GetAvailableClientRoleMappings returns the client roles the user is not granted, even indirectly.
+func (f *Fake) GetAvailableClientRoleMappings(accessToken string, realmName, userID, clientID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, c, err = f.userAndClient(realmName, userID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesNotIn(c.roles, f.realms[realmName].effectiveRoles(u)), nil
+}
+
+// GetRealmLevelRoleMappings returns the realm roles granted to the user.
+func (f *Fake) GetRealmLevelRoleMappings(accessToken string, realmName, userID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(r.roles, u.realmRoles), nil
+}
+
+// GetRoleMappings returns the realm and client roles granted directly to the user.
+func (f *Fake) GetRoleMappings(accessToken string, realmName, userID string) (keycloak.MappingsRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.MappingsRepresentation{}, err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return keycloak.MappingsRepresentation{}, err
+	}
+
+	var res = keycloak.MappingsRepresentation{}
+	if len(u.realmRoles) > 0 {
+		var realmMappings = rolesByID(r.roles, u.realmRoles)
+		res.RealmMappings = &realmMappings
+	}
+	var clientMappings = map[string]keycloak.ClientMappingsRepresentation{}
+	for _, c := range r.clients {
+		if len(u.clientRoles[*c.rep.ID]) == 0 {
+			continue
+		}
+		var mappings = rolesByID(c.roles, u.clientRoles[*c.rep.ID])
+		clientMappings[*c.rep.ClientID] = keycloak.ClientMappingsRepresentation{
+			ID:       strPtr(*c.rep.ID),
+			Client:   strPtr(*c.rep.ClientID),
+			Mappings: &mappings,
+		}
+	}
+	if len(clientMappings) > 0 {
+		res.ClientMappings = &clientMappings
+	}
+	return res, nil
+}
+
+// AddRealmRolesToUserRoleMapping grants realm roles to the user.
+func (f *Fake) AddRealmRolesToUserRoleMapping(accessToken string, realmName, userID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, u, err = f.realmAndUser(realmName, userID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(r.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !contains(u.realmRoles, id) {
+			u.realmRoles = append(u.realmRoles, id)
+		}
+	}
+	return nil
+}
+
+// DeleteRealmRolesFromUserRoleMapping revokes realm roles from the user.
+func (f *Fake) DeleteRealmRolesFromUserRoleMapping(accessToken string, realmName, userID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, u, err = f.realmAndUser(realmName, userID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(r.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		u.realmRoles = remove(u.realmRoles, id)
+	}
+	return nil
+}
+
+// GetEffectiveRealmRoleMappings returns the realm roles granted to the user, directly, through composite roles or
+// through groups.
+func (f *Fake) GetEffectiveRealmRoleMappings(accessToken string, realmName, userID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, u, err = f.realmAndUser(realmName, userID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(r.roles, r.effectiveRoles(u)), nil
+}
+
+// GetAvailableRealmRoleMappings returns the realm roles the user is not granted, even indirectly.
+func (f *Fake) GetAvailableRealmRoleMappings(accessToken string, realmName, userID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, u, err = f.realmAndUser(realmName, userID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesNotIn(r.roles, r.effectiveRoles(u)), nil
+}
+
+// effectiveRoles returns the IDs of the roles granted to the user directly or through its groups and their
+// parents, expanded with the composites of these roles.
+func (r *realm) effectiveRoles(u *user) []string {
+	var granted = append([]string{}, u.realmRoles...)
+	for _, ids := range u.clientRoles {
+		granted = append(granted, ids...)
+	}
+	for _, groupID := range u.groupIDs {
+		for g, err := r.group(groupID); err == nil; g, err = r.group(g.parentID) {
+			granted = append(granted, g.realmRoles...)
+			for _, ids := range g.clientRoles {
+				granted = append(granted, ids...)
+			}
+		}
+	}
+	return r.expandComposites(granted)
+}
+
+// expandComposites returns the roles and the roles they include, directly or not.
+func (r *realm) expandComposites(roleIDs []string) []string {
+	var res []string
+	for len(roleIDs) > 0 {
+		var id = roleIDs[0]
+		roleIDs = roleIDs[1:]
+		if !contains(res, id) {
+			res = append(res, id)
+			roleIDs = append(roleIDs, r.composites[id]...)
+		}
+	}
+	return res
+}
+
+func rolesNotIn(candidates []keycloak.RoleRepresentation, ids []string) []keycloak.RoleRepresentation {
+	var res = []keycloak.RoleRepresentation{}
+	for _, candidate := range candidates {
+		if !contains(ids, *candidate.ID) {
+			res = append(res, cloneRole(candidate))
+		}
+	}
+	return res
+}
+
+func (f *Fake) realmAndUser(realmName string, userID string) (*realm, *user, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, u, nil
+}
+
+func (f *Fake) userAndClient(realmName string, userID string, idClient string) (*user, *client, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, c, nil
+}
@@ -0,0 +1,643 @@
+package keycloaktest
+
+import (
+	"fmt"
+	"strings"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetClientScopes returns the client scopes of the realm.
+func (f *Fake) GetClientScopes(accessToken string, realmName string) ([]keycloak.ClientScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.ClientScopeRepresentation{}
+	for _, s := range r.clientScopes {
+		res = append(res, s.representation())
+	}
+	return res, nil
+}
+
+func (s *clientScope) representation() keycloak.ClientScopeRepresentation {
+	var rep keycloak.ClientScopeRepresentation
+	deepCopy(s.rep, &rep)
+	return rep
+}
+
+// GetClientScope returns the client scope, along with its protocol mappers.
+func (f *Fake) GetClientScope(accessToken string, realmName, scopeID string) (keycloak.ClientScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return keycloak.ClientScopeRepresentation{}, err
+	}
+	return s.representation(), nil
+}
+
+// CreateClientScope creates the client scope. Its name must be unique within the realm.
+func (f *Fake) CreateClientScope(accessToken string, realmName string, scope keycloak.ClientScopeRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	s, err := r.createClientScope(scope)
+	if err != nil {
+		return "", err
+	}
+	return location(realmName, "client-scopes", *s.rep.ID), nil
+}
+
+func (r *realm) createClientScope(scope keycloak.ClientScopeRepresentation) (*clientScope, error) {
+	if scope.Name == nil || *scope.Name == "" {
+		return nil, badRequest("Client scope name is missing")
+	}
+	if err := r.checkClientScopeUniqueness(*scope.Name, ""); err != nil {
+		return nil, err
+	}
+
+	var s = &clientScope{clientRoles: map[string][]string{}}
+	deepCopy(scope, &s.rep)
+	s.rep.ID = strPtr(newID())
+	if s.rep.Protocol == nil {
+		s.rep.Protocol = strPtr("openid-connect")
+	}
+	s.rep.ProtocolMappers = nil
+	if scope.ProtocolMappers != nil {
+		if _, err := addProtocolMappers(&s.rep.ProtocolMappers, *s.rep.Protocol, *scope.ProtocolMappers...); err != nil {
+			return nil, err
+		}
+	}
+	r.clientScopes = append(r.clientScopes, s)
+	return s, nil
+}
+
+// UpdateClientScope updates the non nil fields of the client scope, but its protocol mappers.
+func (f *Fake) UpdateClientScope(accessToken string, realmName, scopeID string, scope keycloak.ClientScopeRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	if scope.Name != nil {
+		if err = r.checkClientScopeUniqueness(*scope.Name, scopeID); err != nil {
+			return err
+		}
+	}
+	scope.ID, scope.ProtocolMappers = nil, nil
+	merge(&s.rep, scope)
+	return nil
+}
+
+// DeleteClientScope deletes the client scope and removes it from the default and optional scopes.
+func (f *Fake) DeleteClientScope(accessToken string, realmName, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, _, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	var scopes []*clientScope
+	for _, s := range r.clientScopes {
+		if *s.rep.ID != scopeID {
+			scopes = append(scopes, s)
+		}
+	}
+	r.clientScopes = scopes
+	r.defaultScopes = remove(r.defaultScopes, scopeID)
+	r.optionalScopes = remove(r.optionalScopes, scopeID)
+	for _, c := range r.clients {
+		c.defaultScopes = remove(c.defaultScopes, scopeID)
+		c.optionalScopes = remove(c.optionalScopes, scopeID)
+	}
+	return nil
+}
+
+// GetClientScopeMappers returns the protocol mappers of the client scope.
+func (f *Fake) GetClientScopeMappers(accessToken string, realmName, scopeID string) ([]keycloak.ProtocolMapperRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	return protocolMappers(s.rep.ProtocolMappers), nil
+}
+
+// GetClientScopeMapper returns a protocol mapper of the client scope.
+func (f *Fake) GetClientScopeMapper(accessToken string, realmName, scopeID, mapperID string) (keycloak.ProtocolMapperRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return keycloak.ProtocolMapperRepresentation{}, err
+	}
+	mapper, err := protocolMapper(s.rep.ProtocolMappers, mapperID)
+	if err != nil {
+		return keycloak.ProtocolMapperRepresentation{}, err
+	}
+	var rep keycloak.ProtocolMapperRepresentation
+	deepCopy(*mapper, &rep)
+	return rep, nil
+}
+
+// CreateClientScopeMapper adds a protocol mapper to the client scope. Its name must be unique within the scope.
+func (f *Fake) CreateClientScopeMapper(accessToken string, realmName, scopeID string, mapper keycloak.ProtocolMapperRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return "", err
+	}
+	ids, err := addProtocolMappers(&s.rep.ProtocolMappers, *s.rep.Protocol, mapper)
+	if err != nil {
+		return "", err
+	}
+	return location(realmName, "client-scopes/"+scopeID+"/protocol-mappers/models", ids[0]), nil
+}
+
+// UpdateClientScopeMapper updates the non nil fields of a protocol mapper of the client scope.
+func (f *Fake) UpdateClientScopeMapper(accessToken string, realmName, scopeID, mapperID string, mapper keycloak.ProtocolMapperRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	return updateProtocolMapper(s.rep.ProtocolMappers, mapperID, mapper)
+}
+
+// DeleteClientScopeMapper deletes a protocol mapper of the client scope.
+func (f *Fake) DeleteClientScopeMapper(accessToken string, realmName, scopeID, mapperID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	return deleteProtocolMapper(&s.rep.ProtocolMappers, mapperID)
+}
+
+// GetClientScopeScopeMappings returns the realm and client roles mapped to the client scope.
+func (f *Fake) GetClientScopeScopeMappings(accessToken string, realmName, scopeID string) (keycloak.MappingsRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return keycloak.MappingsRepresentation{}, err
+	}
+
+	var res = keycloak.MappingsRepresentation{}
+	if len(s.realmRoles) > 0 {
+		var realmMappings = rolesByID(r.roles, s.realmRoles)
+		res.RealmMappings = &realmMappings
+	}
+	var clientMappings = map[string]keycloak.ClientMappingsRepresentation{}
+	for _, c := range r.clients {
+		if len(s.clientRoles[*c.rep.ID]) == 0 {
+			continue
+		}
+		var mappings = rolesByID(c.roles, s.clientRoles[*c.rep.ID])
+		clientMappings[*c.rep.ClientID] = keycloak.ClientMappingsRepresentation{
+			ID:       strPtr(*c.rep.ID),
+			Client:   strPtr(*c.rep.ClientID),
+			Mappings: &mappings,
+		}
+	}
+	if len(clientMappings) > 0 {
+		res.ClientMappings = &clientMappings
+	}
+	return res, nil
+}
+
+// GetClientScopeRealmScopeMappings returns the realm roles mapped to the client scope.
+func (f *Fake) GetClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(r.roles, s.realmRoles), nil
+}
+
+// AddClientScopeRealmScopeMappings maps realm roles to the client scope.
+func (f *Fake) AddClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(r.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !contains(s.realmRoles, id) {
+			s.realmRoles = append(s.realmRoles, id)
+		}
+	}
+	return nil
+}
+
+// DeleteClientScopeRealmScopeMappings unmaps realm roles from the client scope.
+func (f *Fake) DeleteClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(r.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.realmRoles = remove(s.realmRoles, id)
+	}
+	return nil
+}
+
+// GetAvailableClientScopeRealmScopeMappings returns the realm roles not mapped to the client scope, even indirectly.
+func (f *Fake) GetAvailableClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesNotIn(r.roles, r.expandComposites(s.realmRoles)), nil
+}
+
+// GetEffectiveClientScopeRealmScopeMappings returns the realm roles mapped to the client scope, directly or through
+// composite roles.
+func (f *Fake) GetEffectiveClientScopeRealmScopeMappings(accessToken string, realmName, scopeID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(r.roles, r.expandComposites(s.realmRoles)), nil
+}
+
+// GetClientScopeClientScopeMappings returns the roles of the client mapped to the client scope.
+func (f *Fake) GetClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var s, c, err = f.clientScopeAndClient(realmName, scopeID, idClient)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(c.roles, s.clientRoles[idClient]), nil
+}
+
+// AddClientScopeClientScopeMappings maps roles of the client to the client scope.
+func (f *Fake) AddClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var s, c, err = f.clientScopeAndClient(realmName, scopeID, idClient)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(c.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !contains(s.clientRoles[idClient], id) {
+			s.clientRoles[idClient] = append(s.clientRoles[idClient], id)
+		}
+	}
+	return nil
+}
+
+// DeleteClientScopeClientScopeMappings unmaps roles of the client from the client scope.
+func (f *Fake) DeleteClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var s, c, err = f.clientScopeAndClient(realmName, scopeID, idClient)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(c.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.clientRoles[idClient] = remove(s.clientRoles[idClient], id)
+	}
+	return nil
+}
+
+// GetAvailableClientScopeClientScopeMappings returns the roles of the client not mapped to the client scope, even
+// indirectly.
+func (f *Fake) GetAvailableClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var s, c, err = f.clientScopeAndClient(realmName, scopeID, idClient)
+	if err != nil {
+		return nil, err
+	}
+	return rolesNotIn(c.roles, f.realms[realmName].expandComposites(s.mappedRoles())), nil
+}
+
+// GetEffectiveClientScopeClientScopeMappings returns the roles of the client mapped to the client scope, directly or
+// through composite roles.
+func (f *Fake) GetEffectiveClientScopeClientScopeMappings(accessToken string, realmName, scopeID, idClient string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var s, c, err = f.clientScopeAndClient(realmName, scopeID, idClient)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(c.roles, f.realms[realmName].expandComposites(s.mappedRoles())), nil
+}
+
+// mappedRoles returns the IDs of the realm and client roles mapped to the scope.
+func (s *clientScope) mappedRoles() []string {
+	var ids = append([]string{}, s.realmRoles...)
+	for _, clientRoles := range s.clientRoles {
+		ids = append(ids, clientRoles...)
+	}
+	return ids
+}
+
+// GetRealmDefaultClientScopes returns the client scopes assigned as default scopes to the new clients.
+func (f *Fake) GetRealmDefaultClientScopes(accessToken string, realmName string) ([]keycloak.ClientScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	return r.scopeSummaries(r.defaultScopes), nil
+}
+
+// AddRealmDefaultClientScope assigns the client scope as a default scope to the new clients.
+func (f *Fake) AddRealmDefaultClientScope(accessToken string, realmName, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, _, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	r.optionalScopes = remove(r.optionalScopes, scopeID)
+	if !contains(r.defaultScopes, scopeID) {
+		r.defaultScopes = append(r.defaultScopes, scopeID)
+	}
+	return nil
+}
+
+// RemoveRealmDefaultClientScope stops assigning the client scope as a default scope to the new clients.
+func (f *Fake) RemoveRealmDefaultClientScope(accessToken string, realmName, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, _, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	r.defaultScopes = remove(r.defaultScopes, scopeID)
+	return nil
+}
+
+// GetRealmOptionalClientScopes returns the client scopes assigned as optional scopes to the new clients.
+func (f *Fake) GetRealmOptionalClientScopes(accessToken string, realmName string) ([]keycloak.ClientScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	return r.scopeSummaries(r.optionalScopes), nil
+}
+
+// AddRealmOptionalClientScope assigns the client scope as an optional scope to the new clients.
+func (f *Fake) AddRealmOptionalClientScope(accessToken string, realmName, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, _, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	r.defaultScopes = remove(r.defaultScopes, scopeID)
+	if !contains(r.optionalScopes, scopeID) {
+		r.optionalScopes = append(r.optionalScopes, scopeID)
+	}
+	return nil
+}
+
+// RemoveRealmOptionalClientScope stops assigning the client scope as an optional scope to the new clients.
+func (f *Fake) RemoveRealmOptionalClientScope(accessToken string, realmName, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, _, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	r.optionalScopes = remove(r.optionalScopes, scopeID)
+	return nil
+}
+
+// GetClientDefaultScopes returns the default client scopes of the client.
+func (f *Fake) GetClientDefaultScopes(accessToken string, realmName, idClient string) ([]keycloak.ClientScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return nil, err
+	}
+	return r.scopeSummaries(c.defaultScopes), nil
+}
+
+// AddClientDefaultScope assigns the client scope as a default scope of the client. The protocols of the client
+// and of the scope must match.
+func (f *Fake) AddClientDefaultScope(accessToken string, realmName, idClient, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.clientWithScope(realmName, idClient, scopeID)
+	if err != nil {
+		return err
+	}
+	c.optionalScopes = remove(c.optionalScopes, scopeID)
+	if !contains(c.defaultScopes, scopeID) {
+		c.defaultScopes = append(c.defaultScopes, scopeID)
+	}
+	return nil
+}
+
+// RemoveClientDefaultScope removes a default client scope from the client.
+func (f *Fake) RemoveClientDefaultScope(accessToken string, realmName, idClient, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.clientWithScope(realmName, idClient, scopeID)
+	if err != nil {
+		return err
+	}
+	c.defaultScopes = remove(c.defaultScopes, scopeID)
+	return nil
+}
+
+// GetClientOptionalScopes returns the optional client scopes of the client.
+func (f *Fake) GetClientOptionalScopes(accessToken string, realmName, idClient string) ([]keycloak.ClientScopeRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return nil, err
+	}
+	return r.scopeSummaries(c.optionalScopes), nil
+}
+
+// AddClientOptionalScope assigns the client scope as an optional scope of the client. The protocols of the client
+// and of the scope must match.
+func (f *Fake) AddClientOptionalScope(accessToken string, realmName, idClient, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.clientWithScope(realmName, idClient, scopeID)
+	if err != nil {
+		return err
+	}
+	c.defaultScopes = remove(c.defaultScopes, scopeID)
+	if !contains(c.optionalScopes, scopeID) {
+		c.optionalScopes = append(c.optionalScopes, scopeID)
+	}
+	return nil
+}
+
+// RemoveClientOptionalScope removes an optional client scope from the client.
+func (f *Fake) RemoveClientOptionalScope(accessToken string, realmName, idClient, scopeID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.clientWithScope(realmName, idClient, scopeID)
+	if err != nil {
+		return err
+	}
+	c.optionalScopes = remove(c.optionalScopes, scopeID)
+	return nil
+}
+
+func (r *realm) clientScope(scopeID string) (*clientScope, error) {
+	for _, s := range r.clientScopes {
+		if *s.rep.ID == scopeID {
+			return s, nil
+		}
+	}
+	return nil, notFound("Could not find client scope")
+}
+
+func (f *Fake) realmAndClientScope(realmName string, scopeID string) (*realm, *clientScope, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := r.clientScope(scopeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, s, nil
+}
+
+func (f *Fake) clientScopeAndClient(realmName string, scopeID string, idClient string) (*clientScope, *client, error) {
+	var r, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, c, nil
+}
+
+// clientWithScope returns the client, after checking the scope can be assigned to it.
+func (f *Fake) clientWithScope(realmName string, idClient string, scopeID string) (*client, error) {
+	var s, c, err = f.clientScopeAndClient(realmName, scopeID, idClient)
+	if err != nil {
+		return nil, err
+	}
+	if *s.rep.Protocol != *c.rep.Protocol {
+		return nil, badRequest(fmt.Sprintf("Client scope %s has protocol %s, which does not match the protocol of the client", *s.rep.Name, *s.rep.Protocol))
+	}
+	return c, nil
+}
+
+func (r *realm) checkClientScopeUniqueness(name string, scopeID string) error {
+	for _, other := range r.clientScopes {
+		if *other.rep.ID != scopeID && strings.EqualFold(*other.rep.Name, name) {
+			return conflict(fmt.Sprintf("Client Scope %s already exists", name))
+		}
+	}
+	return nil
+}
+
+// scopeSummaries returns the ID and name of the given scopes, like Keycloak lists the scopes assigned by default.
+func (r *realm) scopeSummaries(scopeIDs []string) []keycloak.ClientScopeRepresentation {
+	var res = []keycloak.ClientScopeRepresentation{}
+	for _, id := range scopeIDs {
+		if s, err := r.clientScope(id); err == nil {
+			res = append(res, keycloak.ClientScopeRepresentation{ID: strPtr(*s.rep.ID), Name: strPtr(*s.rep.Name)})
+		}
+	}
+	return res
+}
+
+// scopesForProtocol returns the scopes among scopeIDs which can be assigned to a client of the given protocol.
+func (r *realm) scopesForProtocol(scopeIDs []string, protocol string) []string {
+	var res []string
+	for _, id := range scopeIDs {
+		if s, err := r.clientScope(id); err == nil && *s.rep.Protocol == protocol {
+			res = append(res, id)
+		}
+	}
+	return res
+}
@@ -0,0 +1,343 @@
+package keycloaktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+const (
+	credentialTypeSecret = "secret"
+	// maskedSecret replaces the secrets of the exported clients.
+	maskedSecret = "**********"
+)
+
+// GetClients returns the clients of the realm. It supports all the fields of the query but ViewableOnly.
+func (f *Fake) GetClients(accessToken string, realmName string, query keycloak.ClientQuery) ([]keycloak.ClientRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	r, err := f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*client
+	for _, c := range r.clients {
+		switch {
+		case query.ClientID == "":
+			matches = append(matches, c)
+		case query.Search && containsFold(c.rep.ClientID, query.ClientID):
+			matches = append(matches, c)
+		case *c.rep.ClientID == query.ClientID:
+			matches = append(matches, c)
+		}
+	}
+
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.ClientRepresentation{}
+	for _, c := range matches[first:last] {
+		res = append(res, c.representation())
+	}
+	return res, nil
+}
+
+func (c *client) representation() keycloak.ClientRepresentation {
+	var rep keycloak.ClientRepresentation
+	deepCopy(c.rep, &rep)
+	return rep
+}
+
+// GetClient returns the client. idClient is the id of client (not client-id).
+func (f *Fake) GetClient(accessToken string, realmName, idClient string) (keycloak.ClientRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return keycloak.ClientRepresentation{}, err
+	}
+	return c.representation(), nil
+}
+
+func (f *Fake) client(realmName, idClient string) (*client, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	return r.client(idClient)
+}
+
+// UpdateClient updates the non nil fields of the client, but its protocol mappers. idClient is the id of client
+// (not client-id).
+func (f *Fake) UpdateClient(accessToken string, realmName, idClient string, clientRep keycloak.ClientRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return err
+	}
+	if clientRep.ClientID != nil {
+		if err = r.checkClientUniqueness(*clientRep.ClientID, idClient); err != nil {
+			return err
+		}
+	}
+	clientRep.ID, clientRep.ProtocolMappers = nil, nil
+	merge(&c.rep, clientRep)
+	r.updateServiceAccount(c)
+	c.updateResourceServer()
+	return nil
+}
+
+// CreateClient creates the client. Its clientId must be unique within the realm.
+func (f *Fake) CreateClient(accessToken string, realmName string, clientRep keycloak.ClientRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	c, err := r.createClient(clientRep)
+	if err != nil {
+		return "", err
+	}
+	return location(realmName, "clients", *c.rep.ID), nil
+}
+
+func (r *realm) createClient(clientRep keycloak.ClientRepresentation) (*client, error) {
+	if clientRep.ClientID == nil || *clientRep.ClientID == "" {
+		return nil, badRequest("Client id is missing")
+	}
+	if err := r.checkClientUniqueness(*clientRep.ClientID, ""); err != nil {
+		return nil, err
+	}
+
+	var c = &client{}
+	deepCopy(clientRep, &c.rep)
+	c.rep.ID = strPtr(newID())
+	if c.rep.Enabled == nil {
+		c.rep.Enabled = boolPtr(true)
+	}
+	if c.rep.Protocol == nil {
+		c.rep.Protocol = strPtr("openid-connect")
+	}
+	if c.rep.ClientAuthenticatorType == nil {
+		c.rep.ClientAuthenticatorType = strPtr("client-secret")
+	}
+	c.rep.ProtocolMappers = nil
+	if clientRep.ProtocolMappers != nil {
+		if _, err := addProtocolMappers(&c.rep.ProtocolMappers, *c.rep.Protocol, *clientRep.ProtocolMappers...); err != nil {
+			return nil, err
+		}
+	}
+	var public = c.rep.PublicClient != nil && *c.rep.PublicClient
+	if !public && c.rep.Secret == nil {
+		c.rep.Secret = strPtr(newID())
+	}
+	c.defaultScopes = r.scopesForProtocol(r.defaultScopes, *c.rep.Protocol)
+	c.optionalScopes = r.scopesForProtocol(r.optionalScopes, *c.rep.Protocol)
+	r.clients = append(r.clients, c)
+	r.updateServiceAccount(c)
+	c.updateResourceServer()
+	return c, nil
+}
+
+// updateServiceAccount creates the service account user of the client once its service account is enabled.
+func (r *realm) updateServiceAccount(c *client) {
+	if c.rep.ServiceAccountsEnabled == nil || !*c.rep.ServiceAccountsEnabled || r.serviceAccount(*c.rep.ID) != nil {
+		return
+	}
+	_, _ = r.createUser(*r.rep.Realm, keycloak.UserRepresentation{
+		Username:               strPtr("service-account-" + *c.rep.ClientID),
+		Enabled:                boolPtr(true),
+		ServiceAccountClientID: strPtr(*c.rep.ID),
+	})
+}
+
+func (r *realm) serviceAccount(idClient string) *user {
+	for _, u := range r.users {
+		if u.rep.ServiceAccountClientID != nil && *u.rep.ServiceAccountClientID == idClient {
+			return u
+		}
+	}
+	return nil
+}
+
+func (r *realm) checkClientUniqueness(clientID string, idClient string) error {
+	for _, other := range r.clients {
+		if *other.rep.ID != idClient && strings.EqualFold(*other.rep.ClientID, clientID) {
+			return conflict(fmt.Sprintf("Client %s already exists", clientID))
+		}
+	}
+	return nil
+}
+
+// GetClientMappers is not implemented.
+func (f *Fake) GetClientMappers(accessToken string, realmName, idClient string) ([]keycloak.ClientMapperRepresentation, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetSecret returns the secret of the client. idClient is the id of client (not client-id).
+func (f *Fake) GetSecret(accessToken string, realmName, idClient string) (keycloak.CredentialRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return keycloak.CredentialRepresentation{}, err
+	}
+	return keycloak.CredentialRepresentation{Type: strPtr(credentialTypeSecret), Value: c.rep.Secret}, nil
+}
+
+// RegenerateSecret generates a new secret for the client. Public clients have no secret.
+func (f *Fake) RegenerateSecret(accessToken string, realmName, idClient string) (keycloak.CredentialRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return keycloak.CredentialRepresentation{}, err
+	}
+	if c.rep.PublicClient != nil && *c.rep.PublicClient {
+		return keycloak.CredentialRepresentation{}, badRequest("Public clients have no secret")
+	}
+	c.rep.Secret = strPtr(newID())
+	return keycloak.CredentialRepresentation{Type: strPtr(credentialTypeSecret), Value: strPtr(*c.rep.Secret)}, nil
+}
+
+// DeleteClient deletes the client, along with its roles, its service account user and its scope assignments.
+func (f *Fake) DeleteClient(accessToken string, realmName, idClient string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range c.roles {
+		r.deleteRole(*role.ID)
+	}
+	if u := r.serviceAccount(idClient); u != nil {
+		r.deleteUser(*u.rep.ID)
+	}
+	for _, u := range r.users {
+		delete(u.clientRoles, idClient)
+	}
+	for _, g := range r.groups {
+		delete(g.clientRoles, idClient)
+	}
+	for _, s := range r.clientScopes {
+		delete(s.clientRoles, idClient)
+	}
+	for _, s := range r.sessions {
+		if s.rep.Clients != nil {
+			delete(*s.rep.Clients, idClient)
+		}
+	}
+
+	var clients []*client
+	for _, other := range r.clients {
+		if other != c {
+			clients = append(clients, other)
+		}
+	}
+	r.clients = clients
+	return nil
+}
+
+// GetServiceAccountUser returns the service account user of the client.
+func (f *Fake) GetServiceAccountUser(accessToken string, realmName, idClient string) (keycloak.UserRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.UserRepresentation{}, err
+	}
+	if _, err = r.client(idClient); err != nil {
+		return keycloak.UserRepresentation{}, err
+	}
+	var u = r.serviceAccount(idClient)
+	if u == nil {
+		return keycloak.UserRepresentation{}, badRequest("Service account not enabled for the client")
+	}
+	return u.representation(), nil
+}
+
+// GetClientInstallationProvider returns the keycloak.json of the client for InstallationProviderKeycloakOIDCJSON
+// and ErrNotImplemented for the other providers.
+func (f *Fake) GetClientInstallationProvider(accessToken string, realmName, idClient, providerID string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return nil, err
+	}
+	if providerID != keycloak.InstallationProviderKeycloakOIDCJSON {
+		return nil, ErrNotImplemented
+	}
+
+	var installation = keycloak.KeycloakOIDCInstallation{
+		Realm:         realmName,
+		AuthServerURL: BaseURL + "/auth/",
+		SSLRequired:   "external",
+		Resource:      *c.rep.ClientID,
+		PublicClient:  c.rep.PublicClient != nil && *c.rep.PublicClient,
+		BearerOnly:    c.rep.BearerOnly != nil && *c.rep.BearerOnly,
+	}
+	if r.rep.SslRequired != nil {
+		installation.SSLRequired = *r.rep.SslRequired
+	}
+	if c.rep.Secret != nil && !installation.PublicClient {
+		installation.Credentials = map[string]interface{}{"secret": *c.rep.Secret}
+	}
+	return json.MarshalIndent(installation, "", "  ")
+}
+
+// PushRevocation sets the not-before of the client to now. As the fake has no adapter to notify, the result is
+// empty.
+func (f *Fake) PushRevocation(accessToken string, realmName, idClient string) (keycloak.GlobalRequestResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return keycloak.GlobalRequestResult{}, err
+	}
+	var notBefore = int32(time.Now().Unix())
+	c.rep.NotBefore = &notBefore
+	return keycloak.GlobalRequestResult{}, nil
+}
+
+// TestNodesAvailable returns an empty result, as the fake has no cluster node.
+func (f *Fake) TestNodesAvailable(accessToken string, realmName, idClient string) (keycloak.GlobalRequestResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, err = f.client(realmName, idClient)
+	return keycloak.GlobalRequestResult{}, err
+}
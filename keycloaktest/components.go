@@ -0,0 +1,173 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetComponents returns the components of the realm.
+func (f *Fake) GetComponents(accessToken string, realmName string) ([]keycloak.ComponentRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.ComponentRepresentation{}
+	deepCopy(r.components, &res)
+	return res, nil
+}
+
+// GetComponent returns the component, as the only element of a list like Client.GetComponent does.
+func (f *Fake) GetComponent(accessToken string, realmName string, componentID string) ([]keycloak.ComponentRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var i = r.componentIndex(componentID)
+	if i < 0 {
+		return nil, notFound("Could not find component")
+	}
+	var res = []keycloak.ComponentRepresentation{}
+	deepCopy(r.components[i:i+1], &res)
+	return res, nil
+}
+
+// CreateComponent creates the component. Its parent defaults to the realm.
+func (f *Fake) CreateComponent(accessToken string, realmName string, component keycloak.ComponentRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	if component.ProviderType == nil || *component.ProviderType == "" {
+		return "", badRequest("Provider type is missing")
+	}
+
+	var stored keycloak.ComponentRepresentation
+	deepCopy(component, &stored)
+	stored.ID = strPtr(newID())
+	if stored.ParentID == nil {
+		stored.ParentID = r.rep.ID
+	}
+	r.components = append(r.components, stored)
+	return location(realmName, "components", *stored.ID), nil
+}
+
+// UpdateComponent updates the non nil fields of the component.
+func (f *Fake) UpdateComponent(accessToken string, realmName, componentID string, component keycloak.ComponentRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	var i = r.componentIndex(componentID)
+	if i < 0 {
+		return notFound("Could not find component")
+	}
+	component.ID = nil
+	merge(&r.components[i], component)
+	return nil
+}
+
+// DeleteComponent deletes the component and its sub components, along with the users imported from them.
+func (f *Fake) DeleteComponent(accessToken string, realmName, componentID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if r.componentIndex(componentID) < 0 {
+		return notFound("Could not find component")
+	}
+	r.deleteComponent(componentID)
+	return nil
+}
+
+func (r *realm) deleteComponent(componentID string) {
+	var kept []keycloak.ComponentRepresentation
+	var children []string
+	for _, component := range r.components {
+		switch {
+		case *component.ID == componentID:
+		case component.ParentID != nil && *component.ParentID == componentID:
+			children = append(children, *component.ID)
+			kept = append(kept, component)
+		default:
+			kept = append(kept, component)
+		}
+	}
+	r.components = kept
+	for _, userID := range r.linkedUsers(componentID) {
+		r.deleteUser(userID)
+	}
+	for _, child := range children {
+		r.deleteComponent(child)
+	}
+}
+
+func (r *realm) componentIndex(componentID string) int {
+	for i, component := range r.components {
+		if *component.ID == componentID {
+			return i
+		}
+	}
+	return -1
+}
+
+// RemoveImportedUsers deletes the users whose federation link is the user storage provider.
+func (f *Fake) RemoveImportedUsers(accessToken string, realmName, providerID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if r.componentIndex(providerID) < 0 {
+		return notFound("Could not find component")
+	}
+	for _, userID := range r.linkedUsers(providerID) {
+		r.deleteUser(userID)
+	}
+	return nil
+}
+
+// UnlinkUsers clears the federation link of the users imported from the user storage provider.
+func (f *Fake) UnlinkUsers(accessToken string, realmName, providerID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if r.componentIndex(providerID) < 0 {
+		return notFound("Could not find component")
+	}
+	for _, u := range r.users {
+		if u.rep.FederationLink != nil && *u.rep.FederationLink == providerID {
+			u.rep.FederationLink = nil
+		}
+	}
+	return nil
+}
+
+func (r *realm) linkedUsers(providerID string) []string {
+	var ids []string
+	for _, u := range r.users {
+		if u.rep.FederationLink != nil && *u.rep.FederationLink == providerID {
+			ids = append(ids, *u.rep.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,170 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+const (
+	credentialTypePassword = "password"
+	actionUpdatePassword   = "UPDATE_PASSWORD"
+)
+
+// setCredential replaces the credential of the same type, or adds it. Secret values are not kept.
+func (u *user) setCredential(cred keycloak.CredentialRepresentation) {
+	var stored = keycloak.CredentialRepresentation{
+		ID:          strPtr(newID()),
+		Type:        cred.Type,
+		UserLabel:   cred.UserLabel,
+		CreatedDate: int64Ptr(nowMillis()),
+	}
+	if stored.Type == nil {
+		stored.Type = strPtr(credentialTypePassword)
+	}
+
+	for i, existing := range u.credentials {
+		if *existing.Type == *stored.Type {
+			u.credentials[i] = stored
+			return
+		}
+	}
+	u.credentials = append(u.credentials, stored)
+}
+
+// ResetPassword sets the password of the user. A temporary password adds the UPDATE_PASSWORD required action.
+func (f *Fake) ResetPassword(accessToken string, realmName, userID string, cred keycloak.CredentialRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return err
+	}
+	if cred.Value == nil || *cred.Value == "" {
+		return badRequest("Empty password")
+	}
+
+	cred.Type = strPtr(credentialTypePassword)
+	u.setCredential(cred)
+	if cred.Temporary != nil && *cred.Temporary {
+		var actions []string
+		if u.rep.RequiredActions != nil {
+			actions = *u.rep.RequiredActions
+		}
+		if !contains(actions, actionUpdatePassword) {
+			actions = append(actions, actionUpdatePassword)
+		}
+		u.rep.RequiredActions = &actions
+	}
+	return nil
+}
+
+// GetCredentials returns the credentials of the user, without their secret value.
+func (f *Fake) GetCredentials(accessToken string, realmName string, userID string) ([]keycloak.CredentialRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.CredentialRepresentation{}
+	deepCopy(u.credentials, &res)
+	return res, nil
+}
+
+// GetCredentialTypes is not implemented.
+func (f *Fake) GetCredentialTypes(accessToken string, realmName string) ([]string, error) {
+	return nil, ErrNotImplemented
+}
+
+// UpdateLabelCredential updates the label of the credential.
+func (f *Fake) UpdateLabelCredential(accessToken string, realmName string, userID string, credentialID string, label string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return err
+	}
+	var i = u.credentialIndex(credentialID)
+	if i < 0 {
+		return notFound("Credential not found")
+	}
+	u.credentials[i].UserLabel = &label
+	return nil
+}
+
+// DeleteCredential deletes the credential.
+func (f *Fake) DeleteCredential(accessToken string, realmName string, userID string, credentialID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return err
+	}
+	var i = u.credentialIndex(credentialID)
+	if i < 0 {
+		return notFound("Credential not found")
+	}
+	u.credentials = append(u.credentials[:i], u.credentials[i+1:]...)
+	return nil
+}
+
+// MoveToFirst moves the credential at the top of the list.
+func (f *Fake) MoveToFirst(accessToken string, realmName string, userID string, credentialID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return err
+	}
+	var i = u.credentialIndex(credentialID)
+	if i < 0 {
+		return notFound("Credential not found")
+	}
+	var cred = u.credentials[i]
+	copy(u.credentials[1:i+1], u.credentials[:i])
+	u.credentials[0] = cred
+	return nil
+}
+
+// MoveAfter moves the credential right after previousCredentialID.
+func (f *Fake) MoveAfter(accessToken string, realmName string, userID string, credentialID string, previousCredentialID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return err
+	}
+	var i = u.credentialIndex(credentialID)
+	if i < 0 || u.credentialIndex(previousCredentialID) < 0 {
+		return notFound("Credential not found")
+	}
+	var cred = u.credentials[i]
+	u.credentials = append(u.credentials[:i], u.credentials[i+1:]...)
+	var j = u.credentialIndex(previousCredentialID) + 1
+	u.credentials = append(u.credentials[:j], append([]keycloak.CredentialRepresentation{cred}, u.credentials[j:]...)...)
+	return nil
+}
+
+func (u *user) credentialIndex(credentialID string) int {
+	for i, cred := range u.credentials {
+		if *cred.ID == credentialID {
+			return i
+		}
+	}
+	return -1
+}
+
+// CreateRecoveryCode is not implemented.
+func (f *Fake) CreateRecoveryCode(accessToken string, realmName string, userID string) (keycloak.RecoveryCodeRepresentation, error) {
+	return keycloak.RecoveryCodeRepresentation{}, ErrNotImplemented
+}
+
+// CreateActivationCode is not implemented.
+func (f *Fake) CreateActivationCode(accessToken string, realmName string, userID string) (keycloak.ActivationCodeRepresentation, error) {
+	return keycloak.ActivationCodeRepresentation{}, ErrNotImplemented
+}
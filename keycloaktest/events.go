@@ -0,0 +1,226 @@
+package keycloaktest
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// AddEvents records login events in the realm, as if users had logged in. The events without a time are
+// timestamped with the current time.
+func (f *Fake) AddEvents(realmName string, events ...keycloak.EventRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		var rep keycloak.EventRepresentation
+		deepCopy(e, &rep)
+		if rep.Time == nil {
+			var now = nowMillis()
+			rep.Time = &now
+		}
+		rep.RealmID = r.rep.ID
+		r.events = append(r.events, rep)
+	}
+	return nil
+}
+
+// AddAdminEvents records admin events in the realm. The events without a time are timestamped with the
+// current time.
+func (f *Fake) AddAdminEvents(realmName string, events ...keycloak.AdminEventRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		var rep keycloak.AdminEventRepresentation
+		deepCopy(e, &rep)
+		if rep.Time == nil {
+			var now = nowMillis()
+			rep.Time = &now
+		}
+		rep.RealmID = r.rep.ID
+		r.adminEvents = append(r.adminEvents, rep)
+	}
+	return nil
+}
+
+// GetEvents returns the login events of the realm matching the query, most recent first.
+func (f *Fake) GetEvents(accessToken string, realmName string, query keycloak.EventQuery) ([]keycloak.EventRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	r, err := f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []keycloak.EventRepresentation
+	for _, e := range r.events {
+		if eventMatches(e, query) {
+			matches = append(matches, e)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return *matches[i].Time > *matches[j].Time })
+
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.EventRepresentation{}
+	deepCopy(matches[first:last], &res)
+	return res, nil
+}
+
+// ClearEvents deletes the login events of the realm.
+func (f *Fake) ClearEvents(accessToken string, realmName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	r.events = nil
+	return nil
+}
+
+// GetAdminEvents returns the admin events of the realm matching the query, most recent first.
+func (f *Fake) GetAdminEvents(accessToken string, realmName string, query keycloak.AdminEventQuery) ([]keycloak.AdminEventRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	r, err := f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []keycloak.AdminEventRepresentation
+	for _, e := range r.adminEvents {
+		if adminEventMatches(e, query) {
+			matches = append(matches, e)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return *matches[i].Time > *matches[j].Time })
+
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.AdminEventRepresentation{}
+	deepCopy(matches[first:last], &res)
+	return res, nil
+}
+
+// ClearAdminEvents deletes the admin events of the realm.
+func (f *Fake) ClearAdminEvents(accessToken string, realmName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	r.adminEvents = nil
+	return nil
+}
+
+// GetEventsConfig returns the events configuration of the realm. Events are disabled by default.
+func (f *Fake) GetEventsConfig(accessToken string, realmName string) (keycloak.RealmEventsConfigRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.RealmEventsConfigRepresentation{}, err
+	}
+
+	var res = keycloak.RealmEventsConfigRepresentation{
+		EventsEnabled:             boolPtr(false),
+		AdminEventsEnabled:        boolPtr(false),
+		AdminEventsDetailsEnabled: boolPtr(false),
+		EnabledEventTypes:         &[]string{},
+		EventsListeners:           &[]string{"jboss-logging"},
+	}
+	deepCopy(r.eventsConfig, &res)
+	return res, nil
+}
+
+// UpdateEventsConfig updates the fields of the events configuration which are set.
+func (f *Fake) UpdateEventsConfig(accessToken string, realmName string, config keycloak.RealmEventsConfigRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	deepCopy(config, &r.eventsConfig)
+	return nil
+}
+
+func eventMatches(e keycloak.EventRepresentation, query keycloak.EventQuery) bool {
+	switch {
+	case len(query.Types) > 0 && (e.Type == nil || !contains(query.Types, *e.Type)):
+		return false
+	case !equals(e.ClientID, query.Client), !equals(e.UserID, query.User), !equals(e.IPAddress, query.IPAddress):
+		return false
+	default:
+		return inDates(e.GetTime(), query.DateFrom, query.DateTo)
+	}
+}
+
+func adminEventMatches(e keycloak.AdminEventRepresentation, query keycloak.AdminEventQuery) bool {
+	var auth = keycloak.AuthDetailsRepresentation{}
+	if e.AuthDetails != nil {
+		auth = *e.AuthDetails
+	}
+	switch {
+	case len(query.OperationTypes) > 0 && (e.OperationType == nil || !contains(query.OperationTypes, *e.OperationType)):
+		return false
+	case len(query.ResourceTypes) > 0 && (e.ResourceType == nil || !contains(query.ResourceTypes, *e.ResourceType)):
+		return false
+	case query.ResourcePath != "" && (e.ResourcePath == nil || !wildcardMatch(query.ResourcePath, *e.ResourcePath)):
+		return false
+	case !equals(auth.RealmID, query.AuthRealm), !equals(auth.ClientID, query.AuthClient):
+		return false
+	case !equals(auth.UserID, query.AuthUser), !equals(auth.IPAddress, query.AuthIPAddress):
+		return false
+	default:
+		return inDates(e.GetTime(), query.DateFrom, query.DateTo)
+	}
+}
+
+// equals tells whether value matches filter, an empty filter matching everything.
+func equals(value *string, filter string) bool {
+	return filter == "" || (value != nil && *value == filter)
+}
+
+// inDates tells whether t falls within the days from and to, which are ignored when zero.
+func inDates(t time.Time, from time.Time, to time.Time) bool {
+	var day = t.Format("2006-01-02")
+	return (from.IsZero() || day >= from.Format("2006-01-02")) && (to.IsZero() || day <= to.Format("2006-01-02"))
+}
+
+// wildcardMatch matches value against pattern, in which * stands for any sequence of characters.
+func wildcardMatch(pattern string, value string) bool {
+	var parts = strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(value)
+}
@@ -0,0 +1,332 @@
+// Package keycloaktest provides an in-memory implementation of keycloak.KeycloakAdmin for unit tests.
+package keycloaktest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+const (
+	// BaseURL is the address used in the Location returned by the creation methods.
+	BaseURL = "http://keycloak.test"
+
+	fakeAccessToken = "keycloaktest-access-token"
+	defaultMax      = 100
+)
+
+// ErrNotImplemented is returned by the operations the fake does not model.
+var ErrNotImplemented = errors.New("keycloaktest: not implemented")
+
+// Fake is an in-memory Keycloak. It stores realms, users, groups, clients and their authorization settings, roles,
+// components, authentication flows, required actions, credentials, sessions and events and mimics the behaviour
+// of the admin REST API: 404 for unknown resources, 409 for duplicates, the Location of the created resources
+// and the user search parameters. Access tokens are neither issued for real nor checked. A Fake is safe for
+// concurrent use.
+type Fake struct {
+	mutex  sync.Mutex
+	realms map[string]*realm
+}
+
+type realm struct {
+	rep        keycloak.RealmRepresentation
+	users      []*user
+	groups     []*group
+	clients    []*client
+	roles      []keycloak.RoleRepresentation
+	components []keycloak.ComponentRepresentation
+	sessions   []*session
+	composites map[string][]string
+
+	clientScopes   []*clientScope
+	defaultScopes  []string
+	optionalScopes []string
+	idps           []*identityProvider
+
+	flows                []*keycloak.AuthenticationFlowRepresentation
+	executions           []*execution
+	authenticatorConfigs []*keycloak.AuthenticatorConfigRepresentation
+	requiredActions      []keycloak.RequiredActionProviderRepresentation
+
+	events       []keycloak.EventRepresentation
+	adminEvents  []keycloak.AdminEventRepresentation
+	eventsConfig keycloak.RealmEventsConfigRepresentation
+}
+
+type user struct {
+	rep         keycloak.UserRepresentation
+	credentials []keycloak.CredentialRepresentation
+	groupIDs    []string
+	realmRoles  []string
+	clientRoles map[string][]string
+}
+
+type group struct {
+	rep         keycloak.GroupRepresentation
+	parentID    string
+	realmRoles  []string
+	clientRoles map[string][]string
+}
+
+type session struct {
+	rep     keycloak.UserSessionRepresentation
+	offline bool
+}
+
+type client struct {
+	rep            keycloak.ClientRepresentation
+	roles          []keycloak.RoleRepresentation
+	defaultScopes  []string
+	optionalScopes []string
+	authz          *resourceServer
+}
+
+type resourceServer struct {
+	rep       keycloak.ResourceServerRepresentation
+	resources []keycloak.ResourceRepresentation
+	scopes    []keycloak.ScopeRepresentation
+	policies  []policy
+}
+
+type identityProvider struct {
+	rep         keycloak.IdentityProviderRepresentation
+	mappers     []keycloak.IdentityProviderMapperRepresentation
+	permissions *keycloak.ManagementPermissionReference
+}
+
+type clientScope struct {
+	rep         keycloak.ClientScopeRepresentation
+	realmRoles  []string
+	clientRoles map[string][]string
+}
+
+var _ keycloak.KeycloakAdmin = (*Fake)(nil)
+
+// New returns an empty fake, containing only the master realm.
+func New() *Fake {
+	var f = &Fake{realms: map[string]*realm{}}
+	var master = "master"
+	var enabled = true
+	f.realms[master] = &realm{rep: keycloak.RealmRepresentation{ID: &master, Realm: &master, Enabled: &enabled}}
+	return f
+}
+
+func (f *Fake) realm(realmName string) (*realm, error) {
+	var r, ok = f.realms[realmName]
+	if !ok {
+		return nil, notFound("Realm not found.")
+	}
+	return r, nil
+}
+
+func (r *realm) user(userID string) (*user, error) {
+	for _, u := range r.users {
+		if *u.rep.ID == userID {
+			return u, nil
+		}
+	}
+	return nil, notFound("User not found")
+}
+
+func (r *realm) group(groupID string) (*group, error) {
+	for _, g := range r.groups {
+		if *g.rep.ID == groupID {
+			return g, nil
+		}
+	}
+	return nil, notFound("Could not find group by id")
+}
+
+func (r *realm) client(idClient string) (*client, error) {
+	for _, c := range r.clients {
+		if *c.rep.ID == idClient {
+			return c, nil
+		}
+	}
+	return nil, notFound("Could not find client")
+}
+
+// role looks for a realm or client role by ID.
+func (r *realm) role(roleID string) (*keycloak.RoleRepresentation, error) {
+	for i := range r.roles {
+		if *r.roles[i].ID == roleID {
+			return &r.roles[i], nil
+		}
+	}
+	for _, c := range r.clients {
+		for i := range c.roles {
+			if *c.roles[i].ID == roleID {
+				return &c.roles[i], nil
+			}
+		}
+	}
+	return nil, notFound("Could not find role with id")
+}
+
+// resolveRoles returns the IDs of the given roles, identified by ID or else by name among candidates.
+func resolveRoles(candidates []keycloak.RoleRepresentation, roles []keycloak.RoleRepresentation) ([]string, error) {
+	var ids []string
+	for _, role := range roles {
+		var found = false
+		for _, candidate := range candidates {
+			if (role.ID != nil && *role.ID == *candidate.ID) || (role.ID == nil && role.Name != nil && *role.Name == *candidate.Name) {
+				ids = append(ids, *candidate.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, notFound("Could not find role")
+		}
+	}
+	return ids, nil
+}
+
+func rolesByID(candidates []keycloak.RoleRepresentation, ids []string) []keycloak.RoleRepresentation {
+	var res = []keycloak.RoleRepresentation{}
+	for _, candidate := range candidates {
+		if contains(ids, *candidate.ID) {
+			res = append(res, cloneRole(candidate))
+		}
+	}
+	return res
+}
+
+func location(realmName string, resource string, id string) string {
+	return fmt.Sprintf("%s/auth/admin/realms/%s/%s/%s", BaseURL, realmName, resource, id)
+}
+
+func httpError(status int, message string) error {
+	var body, _ = json.Marshal(map[string]string{"errorMessage": message})
+	return keycloak.HTTPError{HTTPStatus: status, Message: string(body), ErrorMessage: message}
+}
+
+func notFound(message string) error {
+	return httpError(http.StatusNotFound, message)
+}
+
+func conflict(message string) error {
+	return httpError(http.StatusConflict, message)
+}
+
+func badRequest(message string) error {
+	return httpError(http.StatusBadRequest, message)
+}
+
+// newID returns a random UUID.
+func newID() string {
+	var b = make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func nowMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// deepCopy copies src into dst, which must be a pointer, so that the caller and the fake never share data.
+func deepCopy(src interface{}, dst interface{}) {
+	var b, err = json.Marshal(src)
+	if err != nil {
+		panic(err)
+	}
+	if err = json.Unmarshal(b, dst); err != nil {
+		panic(err)
+	}
+}
+
+func cloneRole(role keycloak.RoleRepresentation) keycloak.RoleRepresentation {
+	var res keycloak.RoleRepresentation
+	deepCopy(role, &res)
+	return res
+}
+
+// merge overwrites the fields of dst with the non nil fields of src, like Keycloak does with partial updates.
+// dst must be a pointer to a struct of the same type as src.
+func merge(dst interface{}, src interface{}) {
+	var d = reflect.ValueOf(dst).Elem()
+	var s = reflect.ValueOf(src)
+	for i := 0; i < s.NumField(); i++ {
+		var field = s.Field(i)
+		switch field.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice:
+			if !field.IsNil() {
+				var copied = reflect.New(field.Type())
+				deepCopy(field.Interface(), copied.Interface())
+				d.Field(i).Set(copied.Elem())
+			}
+		default:
+			d.Field(i).Set(field)
+		}
+	}
+}
+
+// page returns the bounds of the page defined by the first and max parameters within a list of size n.
+func page(first int, max int, n int) (int, int) {
+	if max == 0 {
+		max = defaultMax
+	}
+	if first > n {
+		first = n
+	}
+	var last = first + max
+	if last > n {
+		last = n
+	}
+	return first, last
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(values []string, value string) []string {
+	var res []string
+	for _, v := range values {
+		if v != value {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func containsFold(value *string, substr string) bool {
+	return value != nil && strings.Contains(strings.ToLower(*value), strings.ToLower(substr))
+}
+
+func sortedKeys(m map[string][]keycloak.ComponentExportRepresentation) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
@@ -0,0 +1,888 @@
+package keycloaktest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRealm = "test"
+
+func newFakeWithRealm(t *testing.T) *Fake {
+	var f = New()
+	var _, err = f.CreateRealm(fakeAccessToken, keycloak.RealmRepresentation{Realm: strPtr(testRealm)})
+	assert.Nil(t, err)
+	return f
+}
+
+func status(err error) int {
+	if e, ok := err.(keycloak.HTTPError); ok {
+		return e.HTTPStatus
+	}
+	return 0
+}
+
+func idFromLocation(location string) string {
+	return location[strings.LastIndex(location, "/")+1:]
+}
+
+func TestRealms(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var _, err = f.CreateRealm(fakeAccessToken, keycloak.RealmRepresentation{Realm: strPtr(testRealm)})
+	assert.Equal(t, http.StatusConflict, status(err))
+
+	_, err = f.GetRealm(fakeAccessToken, "unknown")
+	assert.Equal(t, http.StatusNotFound, status(err))
+
+	assert.Nil(t, f.UpdateRealm(fakeAccessToken, testRealm, keycloak.RealmRepresentation{DisplayName: strPtr("Test")}))
+	realm, err := f.GetRealm(fakeAccessToken, testRealm)
+	assert.Nil(t, err)
+	assert.Equal(t, "Test", *realm.DisplayName)
+	assert.Equal(t, testRealm, *realm.Realm)
+
+	assert.Nil(t, f.DeleteRealm(fakeAccessToken, testRealm))
+	realms, err := f.GetRealms(fakeAccessToken)
+	assert.Nil(t, err)
+	assert.Len(t, realms, 1)
+}
+
+func TestUsers(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	for _, username := range []string{"John.Doe", "jane.doe", "johnny.cash"} {
+		var _, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{
+			Username: strPtr(username),
+			Email:    strPtr(strings.ToLower(username) + "@example.com"),
+		})
+		assert.Nil(t, err)
+	}
+
+	var _, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("JOHN.DOE")})
+	assert.Equal(t, http.StatusConflict, status(err))
+	_, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("other"), Email: strPtr("jane.doe@example.com")})
+	assert.Equal(t, http.StatusConflict, status(err))
+
+	users, err := f.GetUsers(fakeAccessToken, testRealm, keycloak.UserQuery{Search: "john"})
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "john.doe", *users[0].Username)
+
+	users, err = f.GetUsers(fakeAccessToken, testRealm, keycloak.UserQuery{Username: "john.doe", Exact: true})
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+
+	users, err = f.GetUsers(fakeAccessToken, testRealm, keycloak.UserQuery{First: 1, Max: 1})
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "john.doe", *users[0].Username)
+
+	_, err = f.GetUsers(fakeAccessToken, testRealm, keycloak.UserQuery{Search: "doe", Exact: true})
+	assert.NotNil(t, err)
+
+	// Partial updates keep the fields which are not set.
+	var userID = *users[0].ID
+	assert.Nil(t, f.UpdateUser(fakeAccessToken, testRealm, userID, keycloak.UserRepresentation{FirstName: strPtr("John")}))
+	user, err := f.GetUser(fakeAccessToken, testRealm, userID)
+	assert.Nil(t, err)
+	assert.Equal(t, "John", *user.FirstName)
+	assert.Equal(t, "john.doe@example.com", *user.Email)
+
+	// The fake does not share data with its callers.
+	*user.FirstName = "Changed"
+	user, _ = f.GetUser(fakeAccessToken, testRealm, userID)
+	assert.Equal(t, "John", *user.FirstName)
+
+	assert.Nil(t, f.DeleteUser(fakeAccessToken, testRealm, userID))
+	assert.Equal(t, http.StatusNotFound, status(f.DeleteUser(fakeAccessToken, testRealm, userID)))
+}
+
+func TestGroupsAndRoles(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var location, err = f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("app")})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+	_, err = f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("app")})
+	assert.Equal(t, http.StatusConflict, status(err))
+
+	_, err = f.CreateClientRole(fakeAccessToken, testRealm, idClient, keycloak.RoleRepresentation{Name: strPtr("admin")})
+	assert.Nil(t, err)
+	roles, err := f.GetClientRoles(fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 1)
+
+	location, err = f.CreateGroup(fakeAccessToken, testRealm, keycloak.GroupRepresentation{
+		Name:      strPtr("parent"),
+		SubGroups: &[]keycloak.GroupRepresentation{{Name: strPtr("child")}},
+	})
+	assert.Nil(t, err)
+	var groupID = idFromLocation(location)
+	_, err = f.CreateGroup(fakeAccessToken, testRealm, keycloak.GroupRepresentation{Name: strPtr("parent")})
+	assert.Equal(t, http.StatusConflict, status(err))
+
+	groups, err := f.GetGroups(fakeAccessToken, testRealm, keycloak.GroupQuery{})
+	assert.Nil(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "/parent/child", *(*groups[0].SubGroups)[0].Path)
+
+	assert.Nil(t, f.AssignClientRole(fakeAccessToken, testRealm, groupID, idClient, []keycloak.RoleRepresentation{{Name: strPtr("admin")}}))
+	roles, err = f.GetGroupClientRoles(fakeAccessToken, testRealm, groupID, idClient)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 1)
+	roles, err = f.GetAvailableGroupClientRoles(fakeAccessToken, testRealm, groupID, idClient)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 0)
+
+	assert.Nil(t, f.DeleteGroup(fakeAccessToken, testRealm, groupID))
+	groups, err = f.GetGroups(fakeAccessToken, testRealm, keycloak.GroupQuery{})
+	assert.Nil(t, err)
+	assert.Len(t, groups, 0)
+}
+
+func TestNotImplemented(t *testing.T) {
+	var f = New()
+	var _, err = f.ImportIdpConfig(fakeAccessToken, "master", "oidc", "https://accounts.example.com/.well-known/openid-configuration")
+	assert.Equal(t, ErrNotImplemented, err)
+}
+
+func TestEvents(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var login, loginError = "LOGIN", "LOGIN_ERROR"
+	for i := int64(1); i <= 3; i++ {
+		var millis = i * 1000
+		assert.Nil(t, f.AddEvents(testRealm, keycloak.EventRepresentation{Type: &login, UserID: strPtr("john"), Time: &millis}))
+	}
+	assert.Nil(t, f.AddEvents(testRealm, keycloak.EventRepresentation{Type: &loginError, UserID: strPtr("jane")}))
+
+	events, err := f.GetEvents(fakeAccessToken, testRealm, keycloak.EventQuery{Types: []string{login}, Max: 2})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(3000), *events[0].Time)
+	events, err = f.GetEvents(fakeAccessToken, testRealm, keycloak.EventQuery{User: "jane"})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+
+	assert.Nil(t, f.AddAdminEvents(testRealm, keycloak.AdminEventRepresentation{
+		OperationType: strPtr(keycloak.OperationTypeCreate),
+		ResourcePath:  strPtr("users/42"),
+	}))
+	adminEvents, err := f.GetAdminEvents(fakeAccessToken, testRealm, keycloak.AdminEventQuery{ResourcePath: "users/*"})
+	assert.Nil(t, err)
+	assert.Len(t, adminEvents, 1)
+	adminEvents, err = f.GetAdminEvents(fakeAccessToken, testRealm, keycloak.AdminEventQuery{ResourcePath: "groups/*"})
+	assert.Nil(t, err)
+	assert.Len(t, adminEvents, 0)
+
+	assert.Nil(t, f.ClearEvents(fakeAccessToken, testRealm))
+	events, _ = f.GetEvents(fakeAccessToken, testRealm, keycloak.EventQuery{})
+	assert.Len(t, events, 0)
+
+	assert.Nil(t, f.UpdateEventsConfig(fakeAccessToken, testRealm, keycloak.RealmEventsConfigRepresentation{EventsEnabled: boolPtr(true)}))
+	config, err := f.GetEventsConfig(fakeAccessToken, testRealm)
+	assert.Nil(t, err)
+	assert.True(t, *config.EventsEnabled)
+	assert.False(t, *config.AdminEventsEnabled)
+}
+
+func TestSessions(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var location, err = f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("app")})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+	location, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("john")})
+	assert.Nil(t, err)
+	var userID = idFromLocation(location)
+
+	sessionID, err := f.AddSession(testRealm, userID, false, idClient)
+	assert.Nil(t, err)
+	_, err = f.AddSession(testRealm, userID, false)
+	assert.Nil(t, err)
+	_, err = f.AddSession(testRealm, userID, true, idClient)
+	assert.Nil(t, err)
+
+	sessions, err := f.GetUserSessions(fakeAccessToken, testRealm, userID)
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, "john", *sessions[0].Username)
+	assert.False(t, sessions[0].GetStart().IsZero())
+	count, err := f.GetClientSessionCount(fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.Nil(t, f.DeleteSession(fakeAccessToken, testRealm, sessionID))
+	assert.Equal(t, http.StatusNotFound, status(f.DeleteSession(fakeAccessToken, testRealm, sessionID)))
+
+	_, err = f.LogoutAll(fakeAccessToken, testRealm)
+	assert.Nil(t, err)
+	sessions, _ = f.GetUserSessions(fakeAccessToken, testRealm, userID)
+	assert.Len(t, sessions, 0)
+	sessions, err = f.GetUserOfflineSessions(fakeAccessToken, testRealm, userID, idClient)
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 1)
+
+	assert.Nil(t, f.LogoutUser(fakeAccessToken, testRealm, userID))
+	sessions, _ = f.GetClientOfflineSessions(fakeAccessToken, testRealm, idClient, keycloak.PageQuery{})
+	assert.Len(t, sessions, 0)
+}
+
+func TestRoles(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	for _, name := range []string{"admin", "reader", "writer"} {
+		var _, err = f.CreateRole(fakeAccessToken, testRealm, keycloak.RoleRepresentation{Name: strPtr(name)})
+		assert.Nil(t, err)
+	}
+	var _, err = f.CreateRole(fakeAccessToken, testRealm, keycloak.RoleRepresentation{Name: strPtr("admin")})
+	assert.Equal(t, http.StatusConflict, status(err))
+
+	location, err := f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("app")})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+	_, err = f.CreateClientRole(fakeAccessToken, testRealm, idClient, keycloak.RoleRepresentation{Name: strPtr("manage")})
+	assert.Nil(t, err)
+
+	assert.Nil(t, f.UpdateRole(fakeAccessToken, testRealm, "reader", keycloak.RoleRepresentation{Description: strPtr("Read only")}))
+	reader, err := f.GetRoleByName(fakeAccessToken, testRealm, "reader")
+	assert.Nil(t, err)
+	assert.Equal(t, "Read only", *reader.Description)
+	assert.Equal(t, http.StatusConflict, status(f.UpdateRoleByID(fakeAccessToken, testRealm, *reader.ID, keycloak.RoleRepresentation{Name: strPtr("writer")})))
+
+	// Composites are set by name, and expanded transitively.
+	admin, _ := f.GetRoleByName(fakeAccessToken, testRealm, "admin")
+	writer, _ := f.GetRoleByName(fakeAccessToken, testRealm, "writer")
+	assert.Nil(t, keycloak.SetRoleComposites(f, fakeAccessToken, testRealm, *writer.ID, keycloak.RoleRepresentationComposites{Realm: &[]string{"reader"}}))
+	assert.Nil(t, keycloak.SetRoleComposites(f, fakeAccessToken, testRealm, *admin.ID, keycloak.RoleRepresentationComposites{
+		Realm:  &[]string{"writer"},
+		Client: &map[string]interface{}{"app": []string{"manage"}},
+	}))
+	composites, err := f.GetRealmRoleComposites(fakeAccessToken, testRealm, *admin.ID)
+	assert.Nil(t, err)
+	assert.Len(t, composites, 1)
+	composites, err = f.GetClientRoleComposites(fakeAccessToken, testRealm, *admin.ID, idClient)
+	assert.Nil(t, err)
+	assert.Len(t, composites, 1)
+	composites, err = keycloak.GetEffectiveRoleComposites(f, fakeAccessToken, testRealm, *admin.ID)
+	assert.Nil(t, err)
+	assert.Len(t, composites, 3)
+
+	assert.Nil(t, keycloak.SetRoleComposites(f, fakeAccessToken, testRealm, *admin.ID, keycloak.RoleRepresentationComposites{Realm: &[]string{"reader"}}))
+	composites, _ = f.GetRoleComposites(fakeAccessToken, testRealm, *admin.ID)
+	assert.Len(t, composites, 1)
+	assert.Equal(t, "reader", *composites[0].Name)
+	err = keycloak.SetRoleComposites(f, fakeAccessToken, testRealm, *admin.ID, keycloak.RoleRepresentationComposites{Client: &map[string]interface{}{"unknown": []string{"x"}}})
+	assert.True(t, errors.Is(err, keycloak.ErrNotFound))
+
+	location, err = f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("api")})
+	assert.Nil(t, err)
+	_, err = f.CreateClientRole(fakeAccessToken, testRealm, idFromLocation(location), keycloak.RoleRepresentation{Name: strPtr("call")})
+	assert.Nil(t, err)
+	resolved, err := keycloak.ResolveComposites(f, fakeAccessToken, testRealm, keycloak.RoleRepresentationComposites{
+		Client: &map[string]interface{}{"app": []string{"manage"}, "api": []string{"call"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"call", "manage"}, []string{*resolved[0].Name, *resolved[1].Name})
+
+	// Role holders.
+	location, err = f.CreateGroup(fakeAccessToken, testRealm, keycloak.GroupRepresentation{Name: strPtr("team")})
+	assert.Nil(t, err)
+	var groupID = idFromLocation(location)
+	assert.Nil(t, f.AssignClientRole(fakeAccessToken, testRealm, groupID, idClient, []keycloak.RoleRepresentation{{Name: strPtr("manage")}}))
+	groups, err := f.GetClientRoleGroups(fakeAccessToken, testRealm, idClient, "manage", keycloak.PageQuery{})
+	assert.Nil(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "team", *groups[0].Name)
+
+	location, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("john")})
+	assert.Nil(t, err)
+	assert.Nil(t, f.AddClientRolesToUserRoleMapping(fakeAccessToken, testRealm, idFromLocation(location), idClient, []keycloak.RoleRepresentation{{Name: strPtr("manage")}}))
+	users, err := f.GetClientRoleUsers(fakeAccessToken, testRealm, idClient, "manage", keycloak.PageQuery{})
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+
+	assert.Nil(t, f.DeleteClientRole(fakeAccessToken, testRealm, idClient, "manage"))
+	roles, _ := f.GetGroupClientRoles(fakeAccessToken, testRealm, groupID, idClient)
+	assert.Len(t, roles, 0)
+	assert.Nil(t, f.DeleteRole(fakeAccessToken, testRealm, "reader"))
+	composites, _ = f.GetRoleComposites(fakeAccessToken, testRealm, *admin.ID)
+	assert.Len(t, composites, 0)
+	assert.Equal(t, http.StatusNotFound, status(f.DeleteRoleByID(fakeAccessToken, testRealm, *reader.ID)))
+}
+
+func TestUserRoleMappings(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	for _, name := range []string{"admin", "reader", "auditor"} {
+		var _, err = f.CreateRole(fakeAccessToken, testRealm, keycloak.RoleRepresentation{Name: strPtr(name)})
+		assert.Nil(t, err)
+	}
+	admin, _ := f.GetRoleByName(fakeAccessToken, testRealm, "admin")
+	reader, _ := f.GetRoleByName(fakeAccessToken, testRealm, "reader")
+	assert.Nil(t, f.AddRoleComposites(fakeAccessToken, testRealm, *admin.ID, []keycloak.RoleRepresentation{reader}))
+
+	location, err := f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("app")})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+	for _, name := range []string{"view", "edit"} {
+		_, err = f.CreateClientRole(fakeAccessToken, testRealm, idClient, keycloak.RoleRepresentation{Name: strPtr(name)})
+		assert.Nil(t, err)
+	}
+
+	location, err = f.CreateGroup(fakeAccessToken, testRealm, keycloak.GroupRepresentation{Name: strPtr("editors")})
+	assert.Nil(t, err)
+	var groupID = idFromLocation(location)
+	assert.Nil(t, f.AssignClientRole(fakeAccessToken, testRealm, groupID, idClient, []keycloak.RoleRepresentation{{Name: strPtr("edit")}}))
+
+	location, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("john")})
+	assert.Nil(t, err)
+	var userID = idFromLocation(location)
+	assert.Nil(t, f.AddGroupToUser(fakeAccessToken, testRealm, userID, groupID))
+	assert.Nil(t, f.AddRealmRolesToUserRoleMapping(fakeAccessToken, testRealm, userID, []keycloak.RoleRepresentation{{Name: strPtr("admin")}}))
+	assert.Nil(t, f.AddClientRolesToUserRoleMapping(fakeAccessToken, testRealm, userID, idClient, []keycloak.RoleRepresentation{{Name: strPtr("view")}}))
+
+	roles, err := f.GetEffectiveRealmRoleMappings(fakeAccessToken, testRealm, userID)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 2)
+	roles, err = f.GetAvailableRealmRoleMappings(fakeAccessToken, testRealm, userID)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 1)
+	assert.Equal(t, "auditor", *roles[0].Name)
+	roles, err = f.GetEffectiveClientRoleMappings(fakeAccessToken, testRealm, userID, idClient)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 2)
+
+	mappings, err := f.GetRoleMappings(fakeAccessToken, testRealm, userID)
+	assert.Nil(t, err)
+	assert.Len(t, *mappings.RealmMappings, 1)
+	assert.Equal(t, idClient, *(*mappings.ClientMappings)["app"].ID)
+	assert.Equal(t, "view", *(*(*mappings.ClientMappings)["app"].Mappings)[0].Name)
+
+	assert.Nil(t, f.DeleteClientRolesFromUserRoleMapping(fakeAccessToken, testRealm, userID, idClient, []keycloak.RoleRepresentation{{Name: strPtr("view")}}))
+	roles, _ = f.GetAvailableClientRoleMappings(fakeAccessToken, testRealm, userID, idClient)
+	assert.Len(t, roles, 1)
+	assert.Nil(t, f.DeleteRealmRolesFromUserRoleMapping(fakeAccessToken, testRealm, userID, []keycloak.RoleRepresentation{admin}))
+	roles, _ = f.GetRealmLevelRoleMappings(fakeAccessToken, testRealm, userID)
+	assert.Len(t, roles, 0)
+}
+
+func TestGroupHierarchy(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var location, err = f.CreateGroup(fakeAccessToken, testRealm, keycloak.GroupRepresentation{Name: strPtr("org")})
+	assert.Nil(t, err)
+	var orgID = idFromLocation(location)
+	location, err = f.CreateChildGroup(fakeAccessToken, testRealm, orgID, keycloak.GroupRepresentation{Name: strPtr("dept")})
+	assert.Nil(t, err)
+	var deptID = idFromLocation(location)
+	location, err = f.CreateChildGroup(fakeAccessToken, testRealm, deptID, keycloak.GroupRepresentation{Name: strPtr("team")})
+	assert.Nil(t, err)
+	var teamID = idFromLocation(location)
+	_, err = f.CreateChildGroup(fakeAccessToken, testRealm, orgID, keycloak.GroupRepresentation{Name: strPtr("dept")})
+	assert.Equal(t, http.StatusConflict, status(err))
+
+	team, err := keycloak.GetGroupByPath(f, fakeAccessToken, testRealm, "/org/dept/team")
+	assert.Nil(t, err)
+	assert.Equal(t, teamID, *team.ID)
+	_, err = keycloak.GetGroupByPath(f, fakeAccessToken, testRealm, "/org/team")
+	assert.True(t, errors.Is(err, keycloak.ErrNotFound))
+
+	// Renaming and moving a group updates the paths of its sub groups.
+	assert.Nil(t, f.UpdateGroup(fakeAccessToken, testRealm, deptID, keycloak.GroupRepresentation{Name: strPtr("division")}))
+	team, _ = f.GetGroup(fakeAccessToken, testRealm, teamID)
+	assert.Equal(t, "/org/division/team", *team.Path)
+	assert.Nil(t, f.MoveGroup(fakeAccessToken, testRealm, teamID, ""))
+	team, err = keycloak.GetGroupByPath(f, fakeAccessToken, testRealm, "/team")
+	assert.Nil(t, err)
+	assert.Equal(t, teamID, *team.ID)
+	assert.Equal(t, http.StatusBadRequest, status(f.MoveGroup(fakeAccessToken, testRealm, orgID, deptID)))
+
+	count, err := f.GetGroupCount(fakeAccessToken, testRealm, keycloak.GroupCountQuery{Top: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	// Members and realm roles.
+	for _, username := range []string{"john", "jane"} {
+		location, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr(username)})
+		assert.Nil(t, err)
+		assert.Nil(t, f.AddGroupToUser(fakeAccessToken, testRealm, idFromLocation(location), deptID))
+	}
+	members, err := f.GetGroupMembers(fakeAccessToken, testRealm, deptID, keycloak.PageQuery{Max: 1})
+	assert.Nil(t, err)
+	assert.Len(t, members, 1)
+	assert.Equal(t, "jane", *members[0].Username)
+
+	for _, name := range []string{"employee", "manager"} {
+		_, err = f.CreateRole(fakeAccessToken, testRealm, keycloak.RoleRepresentation{Name: strPtr(name)})
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, f.AddGroupRealmRoles(fakeAccessToken, testRealm, orgID, []keycloak.RoleRepresentation{{Name: strPtr("employee")}}))
+	roles, err := f.GetEffectiveGroupRealmRoles(fakeAccessToken, testRealm, deptID)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 1)
+	roles, err = f.GetAvailableGroupRealmRoles(fakeAccessToken, testRealm, deptID)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 1)
+	assert.Equal(t, "manager", *roles[0].Name)
+	assert.Nil(t, f.DeleteGroupRealmRoles(fakeAccessToken, testRealm, orgID, []keycloak.RoleRepresentation{{Name: strPtr("employee")}}))
+	roles, _ = f.GetGroupRealmRoles(fakeAccessToken, testRealm, orgID)
+	assert.Len(t, roles, 0)
+}
+
+func TestClientScopes(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var location, err = f.CreateClientScope(fakeAccessToken, testRealm, keycloak.ClientScopeRepresentation{
+		Name: strPtr("profile"),
+		ProtocolMappers: &[]keycloak.ProtocolMapperRepresentation{
+			{Name: strPtr("email"), ProtocolMapper: strPtr("oidc-usermodel-property-mapper")},
+		},
+	})
+	assert.Nil(t, err)
+	var profileID = idFromLocation(location)
+	_, err = f.CreateClientScope(fakeAccessToken, testRealm, keycloak.ClientScopeRepresentation{Name: strPtr("Profile")})
+	assert.Equal(t, http.StatusConflict, status(err))
+	location, err = f.CreateClientScope(fakeAccessToken, testRealm, keycloak.ClientScopeRepresentation{Name: strPtr("saml"), Protocol: strPtr("saml")})
+	assert.Nil(t, err)
+	var samlID = idFromLocation(location)
+
+	// Mappers.
+	location, err = f.CreateClientScopeMapper(fakeAccessToken, testRealm, profileID, keycloak.ProtocolMapperRepresentation{
+		Name: strPtr("username"), ProtocolMapper: strPtr("oidc-usermodel-property-mapper"),
+	})
+	assert.Nil(t, err)
+	var mapperID = idFromLocation(location)
+	_, err = f.CreateClientScopeMapper(fakeAccessToken, testRealm, profileID, keycloak.ProtocolMapperRepresentation{
+		Name: strPtr("email"), ProtocolMapper: strPtr("oidc-usermodel-property-mapper"),
+	})
+	assert.Equal(t, http.StatusConflict, status(err))
+	assert.Nil(t, f.UpdateClientScopeMapper(fakeAccessToken, testRealm, profileID, mapperID, keycloak.ProtocolMapperRepresentation{ConsentRequired: boolPtr(true)}))
+	mapper, err := f.GetClientScopeMapper(fakeAccessToken, testRealm, profileID, mapperID)
+	assert.Nil(t, err)
+	assert.Equal(t, "openid-connect", *mapper.Protocol)
+	assert.True(t, *mapper.ConsentRequired)
+	assert.Nil(t, f.DeleteClientScopeMapper(fakeAccessToken, testRealm, profileID, mapperID))
+	mappers, _ := f.GetClientScopeMappers(fakeAccessToken, testRealm, profileID)
+	assert.Len(t, mappers, 1)
+
+	// Scope mappings.
+	_, err = f.CreateRole(fakeAccessToken, testRealm, keycloak.RoleRepresentation{Name: strPtr("reader")})
+	assert.Nil(t, err)
+	assert.Nil(t, f.AddClientScopeRealmScopeMappings(fakeAccessToken, testRealm, profileID, []keycloak.RoleRepresentation{{Name: strPtr("reader")}}))
+	mappings, err := f.GetClientScopeScopeMappings(fakeAccessToken, testRealm, profileID)
+	assert.Nil(t, err)
+	assert.Len(t, *mappings.RealmMappings, 1)
+	assert.Nil(t, mappings.ClientMappings)
+	assert.Nil(t, f.DeleteRole(fakeAccessToken, testRealm, "reader"))
+	roles, _ := f.GetClientScopeRealmScopeMappings(fakeAccessToken, testRealm, profileID)
+	assert.Len(t, roles, 0)
+
+	// The realm default scopes are assigned to the new clients of the same protocol.
+	assert.Nil(t, f.AddRealmOptionalClientScope(fakeAccessToken, testRealm, profileID))
+	assert.Nil(t, f.AddRealmDefaultClientScope(fakeAccessToken, testRealm, profileID))
+	assert.Nil(t, f.AddRealmDefaultClientScope(fakeAccessToken, testRealm, samlID))
+	scopes, _ := f.GetRealmOptionalClientScopes(fakeAccessToken, testRealm)
+	assert.Len(t, scopes, 0)
+	location, err = f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("app")})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+	scopes, err = f.GetClientDefaultScopes(fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.Equal(t, []keycloak.ClientScopeRepresentation{{ID: &profileID, Name: strPtr("profile")}}, scopes)
+
+	assert.Equal(t, http.StatusBadRequest, status(f.AddClientOptionalScope(fakeAccessToken, testRealm, idClient, samlID)))
+	assert.Nil(t, f.AddClientOptionalScope(fakeAccessToken, testRealm, idClient, profileID))
+	scopes, _ = f.GetClientDefaultScopes(fakeAccessToken, testRealm, idClient)
+	assert.Len(t, scopes, 0)
+	scopes, _ = f.GetClientOptionalScopes(fakeAccessToken, testRealm, idClient)
+	assert.Len(t, scopes, 1)
+
+	assert.Nil(t, f.DeleteClientScope(fakeAccessToken, testRealm, profileID))
+	scopes, _ = f.GetClientOptionalScopes(fakeAccessToken, testRealm, idClient)
+	assert.Len(t, scopes, 0)
+	_, err = f.GetClientScope(fakeAccessToken, testRealm, profileID)
+	assert.Equal(t, http.StatusNotFound, status(err))
+}
+
+func TestClientProtocolMappers(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var location, err = f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{
+		ClientID:        strPtr("app"),
+		ProtocolMappers: &[]keycloak.ProtocolMapperRepresentation{keycloak.NewAudienceMapper("audience", "api", "", keycloak.AllTokens)},
+	})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+
+	// Bulk additions are all or none.
+	err = f.AddClientProtocolMappers(fakeAccessToken, testRealm, idClient, []keycloak.ProtocolMapperRepresentation{
+		keycloak.NewGroupMembershipMapper("groups", "groups", false, keycloak.AllTokens),
+		keycloak.NewAudienceMapper("audience", "other", "", keycloak.AllTokens),
+	})
+	assert.Equal(t, http.StatusConflict, status(err))
+	mappers, err := f.GetClientProtocolMappers(fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.Len(t, mappers, 1)
+	assert.NotNil(t, mappers[0].ID)
+
+	location, err = f.CreateClientProtocolMapper(fakeAccessToken, testRealm, idClient, keycloak.NewUserRealmRoleMapper("roles", "roles", "", keycloak.AllTokens))
+	assert.Nil(t, err)
+	var mapperID = idFromLocation(location)
+	assert.Nil(t, f.UpdateClientProtocolMapper(fakeAccessToken, testRealm, idClient, mapperID, keycloak.ProtocolMapperRepresentation{Name: strPtr("realm roles")}))
+	mapper, err := f.GetClientProtocolMapper(fakeAccessToken, testRealm, idClient, mapperID)
+	assert.Nil(t, err)
+	assert.Equal(t, "realm roles", *mapper.Name)
+
+	// Updating the client leaves its mappers alone.
+	assert.Nil(t, f.UpdateClient(fakeAccessToken, testRealm, idClient, keycloak.ClientRepresentation{ProtocolMappers: &[]keycloak.ProtocolMapperRepresentation{}}))
+	assert.Nil(t, f.DeleteClientProtocolMapper(fakeAccessToken, testRealm, idClient, mapperID))
+	assert.Equal(t, http.StatusNotFound, status(f.DeleteClientProtocolMapper(fakeAccessToken, testRealm, idClient, mapperID)))
+	mappers, _ = f.GetClientProtocolMappers(fakeAccessToken, testRealm, idClient)
+	assert.Len(t, mappers, 1)
+}
+
+func TestClientLifecycle(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var location, err = f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("backend")})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+	_, err = f.CreateClientRole(fakeAccessToken, testRealm, idClient, keycloak.RoleRepresentation{Name: strPtr("admin")})
+	assert.Nil(t, err)
+
+	client, err := keycloak.GetClientByClientID(f, fakeAccessToken, testRealm, "backend")
+	assert.Nil(t, err)
+	assert.Equal(t, idClient, *client.ID)
+	_, err = keycloak.GetClientByClientID(f, fakeAccessToken, testRealm, "back")
+	assert.True(t, errors.Is(err, keycloak.ErrNotFound))
+
+	secret, _ := f.GetSecret(fakeAccessToken, testRealm, idClient)
+	regenerated, err := f.RegenerateSecret(fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.NotEqual(t, *secret.Value, *regenerated.Value)
+
+	installation, err := keycloak.GetKeycloakOIDCInstallation(f, fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.Equal(t, "backend", installation.Resource)
+	assert.Equal(t, *regenerated.Value, installation.Credentials["secret"])
+
+	// The service account user is created once the service account is enabled.
+	_, err = f.GetServiceAccountUser(fakeAccessToken, testRealm, idClient)
+	assert.Equal(t, http.StatusBadRequest, status(err))
+	assert.Nil(t, f.UpdateClient(fakeAccessToken, testRealm, idClient, keycloak.ClientRepresentation{ServiceAccountsEnabled: boolPtr(true)}))
+	serviceAccount, err := f.GetServiceAccountUser(fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.Equal(t, "service-account-backend", *serviceAccount.Username)
+
+	assert.Nil(t, f.DeleteClient(fakeAccessToken, testRealm, idClient))
+	_, err = f.GetUser(fakeAccessToken, testRealm, *serviceAccount.ID)
+	assert.Equal(t, http.StatusNotFound, status(err))
+	assert.Equal(t, http.StatusNotFound, status(f.DeleteClient(fakeAccessToken, testRealm, idClient)))
+}
+
+func TestIdentityProviders(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var _, err = f.CreateIdp(fakeAccessToken, testRealm, keycloak.IdentityProviderRepresentation{
+		Alias:      strPtr("partner"),
+		ProviderID: strPtr("saml"),
+		Config:     &map[string]interface{}{"singleSignOnServiceUrl": "https://partner.example.com/sso"},
+	})
+	assert.Nil(t, err)
+	_, err = f.CreateIdp(fakeAccessToken, testRealm, keycloak.IdentityProviderRepresentation{Alias: strPtr("partner"), ProviderID: strPtr("oidc")})
+	assert.Equal(t, http.StatusConflict, status(err))
+
+	location, err := f.CreateIdpMapper(fakeAccessToken, testRealm, "partner", keycloak.IdentityProviderMapperRepresentation{
+		Name:                   strPtr("email"),
+		IdentityProviderMapper: strPtr("saml-user-attribute-idp-mapper"),
+		Config:                 &map[string]interface{}{"attribute.name": "mail", "user.attribute": "email"},
+	})
+	assert.Nil(t, err)
+	var mapperID = idFromLocation(location)
+	assert.Nil(t, f.UpdateIdpMapper(fakeAccessToken, testRealm, "partner", mapperID, keycloak.IdentityProviderMapperRepresentation{Name: strPtr("mail")}))
+
+	// Renaming the identity provider carries its mappers along.
+	assert.Nil(t, f.UpdateIdp(fakeAccessToken, testRealm, "partner", keycloak.IdentityProviderRepresentation{Alias: strPtr("acme"), Enabled: boolPtr(false)}))
+	_, err = f.GetIdp(fakeAccessToken, testRealm, "partner")
+	assert.Equal(t, http.StatusNotFound, status(err))
+	idp, err := f.GetIdp(fakeAccessToken, testRealm, "acme")
+	assert.Nil(t, err)
+	assert.False(t, *idp.Enabled)
+	assert.Equal(t, "https://partner.example.com/sso", (*idp.Config)["singleSignOnServiceUrl"])
+	mapper, err := f.GetIdpMapper(fakeAccessToken, testRealm, "acme", mapperID)
+	assert.Nil(t, err)
+	assert.Equal(t, "acme", *mapper.IdentityProviderAlias)
+	assert.Equal(t, "mail", *mapper.Name)
+
+	permissions, err := f.SetIdpManagementPermissions(fakeAccessToken, testRealm, "acme", true)
+	assert.Nil(t, err)
+	assert.True(t, *permissions.Enabled)
+	assert.Contains(t, *permissions.ScopePermissions, "token-exchange")
+	permissions, _ = f.GetIdpManagementPermissions(fakeAccessToken, testRealm, "acme")
+	assert.True(t, *permissions.Enabled)
+
+	assert.Nil(t, f.DeleteIdpMapper(fakeAccessToken, testRealm, "acme", mapperID))
+	assert.Nil(t, f.DeleteIdp(fakeAccessToken, testRealm, "acme"))
+	idps, _ := f.GetIdps(fakeAccessToken, testRealm)
+	assert.Len(t, idps, 0)
+}
+
+func TestUserStorage(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	location, err := f.CreateComponent(fakeAccessToken, testRealm, keycloak.LDAPProvider{Name: "ldap", ConnectionURL: "ldap://ldap.example.com"}.Component())
+	assert.Nil(t, err)
+	var providerID = idFromLocation(location)
+	for _, username := range []string{"alice", "bob"} {
+		_, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr(username), FederationLink: strPtr(providerID)})
+		assert.Nil(t, err)
+	}
+	_, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("carol")})
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusNotFound, status(f.UnlinkUsers(fakeAccessToken, testRealm, "unknown")))
+	assert.Nil(t, f.RemoveImportedUsers(fakeAccessToken, testRealm, providerID))
+	count, _ := f.CountUsers(fakeAccessToken, testRealm)
+	assert.Equal(t, 1, count)
+
+	_, err = f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("dave"), FederationLink: strPtr(providerID)})
+	assert.Nil(t, err)
+	assert.Nil(t, f.UnlinkUsers(fakeAccessToken, testRealm, providerID))
+	assert.Nil(t, f.DeleteComponent(fakeAccessToken, testRealm, providerID))
+	users, _ := f.GetUsers(fakeAccessToken, testRealm, keycloak.UserQuery{Username: "dave"})
+	assert.Len(t, users, 1)
+	assert.Nil(t, users[0].FederationLink)
+}
+
+func TestAuthorization(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	location, err := f.CreateClient(fakeAccessToken, testRealm, keycloak.ClientRepresentation{ClientID: strPtr("api")})
+	assert.Nil(t, err)
+	var idClient = idFromLocation(location)
+	_, err = f.GetResourceServer(fakeAccessToken, testRealm, idClient)
+	assert.Equal(t, http.StatusNotFound, status(err))
+	assert.Nil(t, f.UpdateClient(fakeAccessToken, testRealm, idClient, keycloak.ClientRepresentation{AuthorizationServicesEnabled: boolPtr(true)}))
+	assert.Nil(t, f.UpdateResourceServer(fakeAccessToken, testRealm, idClient, keycloak.ResourceServerRepresentation{
+		DecisionStrategy: strPtr(keycloak.DecisionStrategyAffirmative),
+	}))
+	settings, err := f.GetResourceServer(fakeAccessToken, testRealm, idClient)
+	assert.Nil(t, err)
+	assert.Equal(t, keycloak.DecisionStrategyAffirmative, *settings.DecisionStrategy)
+	assert.Equal(t, keycloak.PolicyEnforcementModeEnforcing, *settings.PolicyEnforcementMode)
+
+	// The scopes of a resource are created on the fly.
+	resource, err := f.CreateResource(fakeAccessToken, testRealm, idClient, keycloak.ResourceRepresentation{
+		Name:   strPtr("documents"),
+		URIs:   &[]string{"/documents/*"},
+		Scopes: &[]keycloak.ScopeRepresentation{{Name: strPtr("read")}, {Name: strPtr("write")}},
+	})
+	assert.Nil(t, err)
+	_, err = f.CreateResource(fakeAccessToken, testRealm, idClient, keycloak.ResourceRepresentation{Name: strPtr("documents")})
+	assert.Equal(t, http.StatusConflict, status(err))
+	scopes, _ := f.GetAuthorizationScopes(fakeAccessToken, testRealm, idClient, keycloak.AuthorizationScopeQuery{})
+	assert.Len(t, scopes, 2)
+	resources, _ := f.GetResources(fakeAccessToken, testRealm, idClient, keycloak.ResourceQuery{URI: "/documents/42", MatchingURI: true})
+	assert.Len(t, resources, 1)
+
+	policy, err := f.CreatePolicy(fakeAccessToken, testRealm, idClient, keycloak.UserPolicyRepresentation{
+		PolicyRepresentation: keycloak.PolicyRepresentation{Name: strPtr("alice only")},
+		Users:                &[]string{"alice"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, keycloak.LogicPositive, *policy.Logic)
+	permission, err := f.CreatePermission(fakeAccessToken, testRealm, idClient, keycloak.ScopePermissionRepresentation{
+		PolicyRepresentation: keycloak.PolicyRepresentation{
+			Name:      strPtr("read documents"),
+			Resources: &[]string{"documents"},
+			Scopes:    &[]string{"read"},
+			Policies:  &[]string{"alice only"},
+		},
+	})
+	assert.Nil(t, err)
+	_, err = f.CreatePermission(fakeAccessToken, testRealm, idClient, keycloak.ResourcePermissionRepresentation{
+		PolicyRepresentation: keycloak.PolicyRepresentation{Name: strPtr("broken"), Resources: &[]string{"unknown"}},
+	})
+	assert.Equal(t, http.StatusBadRequest, status(err))
+
+	policies, _ := f.GetPolicies(fakeAccessToken, testRealm, idClient, keycloak.PolicyQuery{})
+	assert.Len(t, policies, 1)
+	permissions, _ := f.GetPermissions(fakeAccessToken, testRealm, idClient, keycloak.PolicyQuery{Resource: "documents"})
+	assert.Len(t, permissions, 1)
+	dependents, _ := f.GetDependentPolicies(fakeAccessToken, testRealm, idClient, *policy.ID)
+	assert.Equal(t, *permission.ID, *dependents[0].ID)
+
+	var typed keycloak.ScopePermissionRepresentation
+	assert.Nil(t, f.GetTypedPermission(fakeAccessToken, testRealm, idClient, *permission.ID, &typed))
+	assert.Equal(t, []string{*resource.ID}, *typed.Resources)
+	var wrongType keycloak.UserPolicyRepresentation
+	assert.Equal(t, http.StatusNotFound, status(f.GetTypedPolicy(fakeAccessToken, testRealm, idClient, *permission.ID, &wrongType)))
+
+	// Deleting a scope or a policy removes it from the permissions.
+	var readScopeID = (*resource.Scopes)[0].ID
+	assert.Nil(t, f.DeleteAuthorizationScope(fakeAccessToken, testRealm, idClient, *readScopeID))
+	assert.Nil(t, f.DeletePolicy(fakeAccessToken, testRealm, idClient, *policy.ID))
+	typed = keycloak.ScopePermissionRepresentation{}
+	assert.Nil(t, f.GetTypedPermission(fakeAccessToken, testRealm, idClient, *permission.ID, &typed))
+	assert.Empty(t, *typed.Scopes)
+	assert.Empty(t, *typed.Policies)
+	resource, _ = f.GetResource(fakeAccessToken, testRealm, idClient, *resource.ID)
+	assert.Len(t, *resource.Scopes, 1)
+}
+
+func TestPartialImport(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	_, err := f.CreateUser(fakeAccessToken, testRealm, keycloak.UserRepresentation{Username: strPtr("john"), FirstName: strPtr("John")})
+	assert.Nil(t, err)
+	var rep = keycloak.PartialImportRepresentation{
+		Clients: &[]keycloak.ClientRepresentation{{ClientID: strPtr("app")}},
+		Roles: &keycloak.RolesRepresentation{
+			Realm:  &[]keycloak.RoleRepresentation{{Name: strPtr("viewer")}},
+			Client: &map[string]interface{}{"app": []keycloak.RoleRepresentation{{Name: strPtr("admin")}}},
+		},
+		Users: &[]keycloak.UserRepresentation{{Username: strPtr("John"), FirstName: strPtr("Johnny")}, {Username: strPtr("jane")}},
+	}
+
+	// A conflict aborts the whole import.
+	_, err = f.PartialImport(fakeAccessToken, testRealm, rep)
+	assert.Equal(t, http.StatusConflict, status(err))
+	_, err = keycloak.GetClientByClientID(f, fakeAccessToken, testRealm, "app")
+	assert.NotNil(t, err)
+
+	rep.IfResourceExists = keycloak.IfResourceExistsSkip
+	results, err := f.PartialImport(fakeAccessToken, testRealm, rep)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, results.Added)
+	assert.Equal(t, 1, results.Skipped)
+	app, err := keycloak.GetClientByClientID(f, fakeAccessToken, testRealm, "app")
+	assert.Nil(t, err)
+	_, err = f.GetClientRole(fakeAccessToken, testRealm, *app.ID, "admin")
+	assert.Nil(t, err)
+
+	rep.IfResourceExists = keycloak.IfResourceExistsOverwrite
+	results, err = f.PartialImport(fakeAccessToken, testRealm, rep)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, results.Overwritten)
+	users, _ := f.GetUsers(fakeAccessToken, testRealm, keycloak.UserQuery{Username: "john", Exact: true})
+	assert.Equal(t, "Johnny", *users[0].FirstName)
+
+	export, err := f.PartialExport(fakeAccessToken, testRealm, keycloak.PartialExportOptions{Clients: true})
+	assert.Nil(t, err)
+	assert.Nil(t, export.Roles)
+	for _, c := range *export.Clients {
+		assert.Equal(t, "**********", *c.Secret)
+	}
+	full, err := f.ExportRealm(fakeAccessToken, testRealm)
+	assert.Nil(t, err)
+	assert.NotNil(t, full.Roles)
+	for _, c := range *full.Clients {
+		if c.Secret != nil {
+			assert.NotEqual(t, "**********", *c.Secret)
+		}
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	assert.Nil(t, f.CreateAuthenticationFlow(fakeAccessToken, testRealm, keycloak.AuthenticationFlowRepresentation{Alias: strPtr("custom")}))
+	assert.Equal(t, http.StatusConflict, status(f.CreateAuthenticationFlow(fakeAccessToken, testRealm, keycloak.AuthenticationFlowRepresentation{Alias: strPtr("custom")})))
+	assert.Nil(t, f.CreateAuthenticationFlow(fakeAccessToken, testRealm, keycloak.AuthenticationFlowRepresentation{Alias: strPtr("browser"), BuiltIn: boolPtr(true)}))
+	var flows, err = f.GetAuthenticationFlows(fakeAccessToken, testRealm)
+	assert.Nil(t, err)
+	assert.Len(t, flows, 2)
+	assert.Equal(t, "basic-flow", *flows[0].ProviderID)
+	assert.Nil(t, f.UpdateAuthenticationFlow(fakeAccessToken, testRealm, *flows[0].ID, keycloak.AuthenticationFlowRepresentation{Description: strPtr("Custom flow")}))
+	flow, err := f.GetAuthenticationFlow(fakeAccessToken, testRealm, *flows[0].ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "custom", *flow.Alias)
+	assert.Equal(t, "Custom flow", *flow.Description)
+	assert.Equal(t, http.StatusConflict, status(f.UpdateAuthenticationFlow(fakeAccessToken, testRealm, *flows[0].ID, keycloak.AuthenticationFlowRepresentation{Alias: strPtr("browser")})))
+	assert.Equal(t, http.StatusBadRequest, status(f.UpdateAuthenticationFlow(fakeAccessToken, testRealm, *flows[1].ID, keycloak.AuthenticationFlowRepresentation{Description: strPtr("")})))
+	assert.Equal(t, http.StatusBadRequest, status(f.DeleteAuthenticationFlow(fakeAccessToken, testRealm, *flows[1].ID)))
+	assert.Nil(t, f.DeleteAuthenticationFlow(fakeAccessToken, testRealm, *flows[0].ID))
+	_, err = f.GetAuthenticationFlow(fakeAccessToken, testRealm, *flows[0].ID)
+	assert.Equal(t, http.StatusNotFound, status(err))
+
+	assert.Nil(t, f.RegisterRequiredAction(fakeAccessToken, testRealm, "CONFIGURE_TOTP", "Configure OTP"))
+	assert.Equal(t, http.StatusConflict, status(f.RegisterRequiredAction(fakeAccessToken, testRealm, "CONFIGURE_TOTP", "Configure OTP")))
+	assert.Nil(t, f.UpdateRequiredAction(fakeAccessToken, testRealm, "CONFIGURE_TOTP", keycloak.RequiredActionProviderRepresentation{Name: strPtr("ignored"), DefaultAction: boolPtr(true)}))
+	action, err := f.GetRequiredAction(fakeAccessToken, testRealm, "CONFIGURE_TOTP")
+	assert.Nil(t, err)
+	assert.Equal(t, "Configure OTP", *action.Name)
+	assert.True(t, *action.Enabled)
+	assert.True(t, *action.DefaultAction)
+	assert.Nil(t, f.DeleteRequiredAction(fakeAccessToken, testRealm, "CONFIGURE_TOTP"))
+	actions, err := f.GetRequiredActions(fakeAccessToken, testRealm)
+	assert.Nil(t, err)
+	assert.Empty(t, actions)
+}
+
+func TestAuthenticationExecutions(t *testing.T) {
+	var f = newFakeWithRealm(t)
+
+	var flow = keycloak.NewAuthenticationFlow("custom", "").
+		Execution("auth-cookie", keycloak.RequirementAlternative).
+		SubFlow(keycloak.NewAuthenticationFlow("custom forms", "").
+			Execution("auth-username-password-form", keycloak.RequirementRequired).
+			ConfiguredExecution("auth-otp-form", keycloak.RequirementDisabled, keycloak.FlowAuthenticatorConfig{Alias: "otp", Config: map[string]string{"period": "30"}}),
+			keycloak.RequirementAlternative)
+	assert.Nil(t, keycloak.SyncAuthenticationFlow(f, fakeAccessToken, testRealm, *flow))
+	assert.Nil(t, keycloak.SetAuthenticationExecutionRequirement(f, fakeAccessToken, testRealm, "custom", "auth-otp-form", keycloak.RequirementRequired))
+
+	var executions, err = f.GetAuthenticationExecutionForFlow(fakeAccessToken, testRealm, "custom")
+	assert.Nil(t, err)
+	var tree = keycloak.AuthenticationExecutionTree(executions)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "custom forms", *tree[1].Execution.DisplayName)
+	var otp = tree[1].Executions[1].Execution
+	assert.Equal(t, keycloak.RequirementRequired, *otp.Requirement)
+	assert.Equal(t, "otp", *otp.Alias)
+	config, err := f.GetAuthenticatorConfig(fakeAccessToken, testRealm, *otp.AuthenticationConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"period": "30"}, *config.Config)
+
+	assert.Nil(t, f.RaiseExecutionPriority(fakeAccessToken, testRealm, *otp.ID))
+	assert.Nil(t, f.RaiseExecutionPriority(fakeAccessToken, testRealm, *otp.ID))
+	executions, _ = f.GetAuthenticationExecutionForFlow(fakeAccessToken, testRealm, "custom forms")
+	assert.Equal(t, []string{"auth-otp-form", "auth-username-password-form"}, []string{*executions[0].ProviderID, *executions[1].ProviderID})
+
+	realm, err := f.ExportRealm(fakeAccessToken, testRealm)
+	assert.Nil(t, err)
+	assert.Len(t, *realm.AuthenticationFlows, 2)
+	assert.Len(t, *realm.AuthenticatorConfig, 1)
+	realm.Realm, realm.ID = strPtr("copy"), nil
+	_, err = f.CreateRealm(fakeAccessToken, realm)
+	assert.Nil(t, err)
+	copied, err := f.GetAuthenticationExecutionForFlow(fakeAccessToken, "copy", "custom")
+	assert.Nil(t, err)
+	assert.Len(t, copied, 4)
+	assert.Equal(t, "otp", *copied[2].Alias)
+
+	assert.Equal(t, http.StatusNotFound, status(f.DeleteAuthenticationExecution(fakeAccessToken, testRealm, "unknown")))
+	assert.Nil(t, f.DeleteAuthenticationExecution(fakeAccessToken, testRealm, *tree[1].Execution.ID))
+	_, err = f.GetAuthenticationExecutionForFlow(fakeAccessToken, testRealm, "custom forms")
+	assert.Equal(t, http.StatusNotFound, status(err))
+	_, err = f.GetAuthenticatorConfig(fakeAccessToken, testRealm, *otp.AuthenticationConfig)
+	assert.Equal(t, http.StatusNotFound, status(err))
+
+	assert.Nil(t, keycloak.SyncAuthenticationFlow(f, fakeAccessToken, testRealm, *flow))
+	forms, err := keycloak.GetAuthenticationExecutionByDisplayName(f, fakeAccessToken, testRealm, "custom", "custom forms")
+	assert.Nil(t, err)
+	flow.Executions[1].SubFlow.ProviderID = keycloak.FlowProviderForm
+	assert.Nil(t, keycloak.SyncAuthenticationFlow(f, fakeAccessToken, testRealm, *flow))
+	recreated, err := keycloak.GetAuthenticationExecutionByDisplayName(f, fakeAccessToken, testRealm, "custom", "custom forms")
+	assert.Nil(t, err)
+	assert.NotEqual(t, *forms.ID, *recreated.ID)
+	subFlow, err := f.GetAuthenticationFlow(fakeAccessToken, testRealm, *recreated.FlowID)
+	assert.Nil(t, err)
+	assert.Equal(t, keycloak.FlowProviderForm, *subFlow.ProviderID)
+	err = keycloak.SyncAuthenticationFlow(f, fakeAccessToken, testRealm, keycloak.AuthenticationFlow{Alias: "custom forms"})
+	assert.True(t, errors.Is(err, keycloak.ErrConflict))
+}
@@ -0,0 +1,515 @@
+package keycloaktest
+
+import (
+	"fmt"
+	"sort"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetGroups returns the top level groups of the realm, along with their sub groups. It supports all the fields
+// of the query but BriefRepresentation, Search keeping the top level groups having a matching name in their
+// hierarchy.
+func (f *Fake) GetGroups(accessToken string, realmName string, query keycloak.GroupQuery) ([]keycloak.GroupRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	r, err := f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches = []keycloak.GroupRepresentation{}
+	for _, g := range r.groupTree("") {
+		if query.Search == "" || groupMatches(g, query.Search) {
+			matches = append(matches, g)
+		}
+	}
+	var first, last = page(query.First, query.Max, len(matches))
+	return matches[first:last], nil
+}
+
+func groupMatches(g keycloak.GroupRepresentation, search string) bool {
+	if containsFold(g.Name, search) {
+		return true
+	}
+	if g.SubGroups != nil {
+		for _, sub := range *g.SubGroups {
+			if groupMatches(sub, search) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupTree returns the children of the group parentID, or the top level groups if parentID is empty.
+func (r *realm) groupTree(parentID string) []keycloak.GroupRepresentation {
+	var res = []keycloak.GroupRepresentation{}
+	for _, g := range r.groups {
+		if g.parentID == parentID {
+			var rep = g.representation()
+			var subGroups = r.groupTree(*g.rep.ID)
+			rep.SubGroups = &subGroups
+			res = append(res, rep)
+		}
+	}
+	return res
+}
+
+func (g *group) representation() keycloak.GroupRepresentation {
+	var rep keycloak.GroupRepresentation
+	deepCopy(g.rep, &rep)
+	return rep
+}
+
+// GetGroup returns the group along with its sub groups.
+func (f *Fake) GetGroup(accessToken string, realmName string, groupID string) (keycloak.GroupRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.GroupRepresentation{}, err
+	}
+	g, err := r.group(groupID)
+	if err != nil {
+		return keycloak.GroupRepresentation{}, err
+	}
+	var rep = g.representation()
+	var subGroups = r.groupTree(groupID)
+	rep.SubGroups = &subGroups
+	return rep, nil
+}
+
+// CreateGroup creates a top level group. Its name must be unique among the top level groups.
+func (f *Fake) CreateGroup(accessToken string, reqRealmName string, groupRep keycloak.GroupRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(reqRealmName)
+	if err != nil {
+		return "", err
+	}
+	g, err := r.createGroup(groupRep, nil)
+	if err != nil {
+		return "", err
+	}
+	return location(reqRealmName, "groups", *g.rep.ID), nil
+}
+
+// createGroup creates the group, and the sub groups it contains, under parent.
+func (r *realm) createGroup(groupRep keycloak.GroupRepresentation, parent *group) (*group, error) {
+	if groupRep.Name == nil || *groupRep.Name == "" {
+		return nil, badRequest("Group name is missing")
+	}
+
+	var parentID, parentPath = "", ""
+	if parent != nil {
+		parentID, parentPath = *parent.rep.ID, *parent.rep.Path
+	}
+	if err := r.checkSiblings(parentID, *groupRep.Name); err != nil {
+		return nil, err
+	}
+
+	var g = &group{parentID: parentID, clientRoles: map[string][]string{}}
+	deepCopy(groupRep, &g.rep)
+	g.rep.ID = strPtr(newID())
+	g.rep.Path = strPtr(parentPath + "/" + *groupRep.Name)
+	g.rep.SubGroups, g.rep.RealmRoles, g.rep.ClientRoles = nil, nil, nil
+	r.groups = append(r.groups, g)
+
+	if groupRep.SubGroups != nil {
+		for _, sub := range *groupRep.SubGroups {
+			if _, err := r.createGroup(sub, g); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return g, nil
+}
+
+// DeleteGroup deletes the group and its sub groups.
+func (f *Fake) DeleteGroup(accessToken string, realmName string, groupID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if _, err = r.group(groupID); err != nil {
+		return err
+	}
+	r.deleteGroup(groupID)
+	return nil
+}
+
+func (r *realm) deleteGroup(groupID string) {
+	for _, g := range r.groups {
+		if g.parentID == groupID {
+			r.deleteGroup(*g.rep.ID)
+		}
+	}
+	for i, g := range r.groups {
+		if *g.rep.ID == groupID {
+			r.groups = append(r.groups[:i], r.groups[i+1:]...)
+			break
+		}
+	}
+	for _, u := range r.users {
+		u.groupIDs = remove(u.groupIDs, groupID)
+	}
+}
+
+// AssignClientRole adds client roles to the group.
+func (f *Fake) AssignClientRole(accessToken string, realmName string, groupID string, clientID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var g, c, err = f.groupAndClient(realmName, groupID, clientID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(c.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !contains(g.clientRoles[clientID], id) {
+			g.clientRoles[clientID] = append(g.clientRoles[clientID], id)
+		}
+	}
+	return nil
+}
+
+// RemoveClientRole removes client roles from the group.
+func (f *Fake) RemoveClientRole(accessToken string, realmName string, groupID string, clientID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var g, c, err = f.groupAndClient(realmName, groupID, clientID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(c.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		g.clientRoles[clientID] = remove(g.clientRoles[clientID], id)
+	}
+	return nil
+}
+
+// GetGroupClientRoles returns the client roles of the group.
+func (f *Fake) GetGroupClientRoles(accessToken string, realmName string, groupID string, clientID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var g, c, err = f.groupAndClient(realmName, groupID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(c.roles, g.clientRoles[clientID]), nil
+}
+
+// GetAvailableGroupClientRoles returns the client roles which are not assigned to the group.
+func (f *Fake) GetAvailableGroupClientRoles(accessToken string, realmName string, groupID string, clientID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var g, c, err = f.groupAndClient(realmName, groupID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.RoleRepresentation{}
+	for _, role := range c.roles {
+		if !contains(g.clientRoles[clientID], *role.ID) {
+			res = append(res, cloneRole(role))
+		}
+	}
+	return res, nil
+}
+
+func (f *Fake) groupAndClient(realmName string, groupID string, idClient string) (*group, *client, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, nil, err
+	}
+	g, err := r.group(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := r.client(idClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, c, nil
+}
+
+// UpdateGroup updates the name and the attributes of the group. Its name must stay unique among its siblings.
+func (f *Fake) UpdateGroup(accessToken string, realmName string, groupID string, groupRep keycloak.GroupRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	g, err := r.group(groupID)
+	if err != nil {
+		return err
+	}
+	if groupRep.Name != nil && *groupRep.Name != *g.rep.Name {
+		if err = r.checkSiblings(g.parentID, *groupRep.Name); err != nil {
+			return err
+		}
+		g.rep.Name = strPtr(*groupRep.Name)
+		r.updatePaths(g)
+	}
+	if groupRep.Attributes != nil {
+		deepCopy(groupRep.Attributes, &g.rep.Attributes)
+	}
+	return nil
+}
+
+// CreateChildGroup creates a group under the parent group. Its name must be unique among its siblings.
+func (f *Fake) CreateChildGroup(accessToken string, realmName string, parentID string, groupRep keycloak.GroupRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	parent, err := r.group(parentID)
+	if err != nil {
+		return "", err
+	}
+	g, err := r.createGroup(groupRep, parent)
+	if err != nil {
+		return "", err
+	}
+	return location(realmName, "groups", *g.rep.ID), nil
+}
+
+// MoveGroup moves the group under the parent group, or to the top level if parentID is empty.
+func (f *Fake) MoveGroup(accessToken string, realmName string, groupID string, parentID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	g, err := r.group(groupID)
+	if err != nil {
+		return err
+	}
+	if parentID != "" {
+		if _, err = r.group(parentID); err != nil {
+			return err
+		}
+		for ancestor := parentID; ancestor != ""; {
+			if ancestor == groupID {
+				return badRequest("Cannot move group under itself")
+			}
+			var a, _ = r.group(ancestor)
+			ancestor = a.parentID
+		}
+	}
+	if g.parentID == parentID {
+		return nil
+	}
+	if err = r.checkSiblings(parentID, *g.rep.Name); err != nil {
+		return err
+	}
+	g.parentID = parentID
+	r.updatePaths(g)
+	return nil
+}
+
+// GetGroupMembers returns the direct members of the group, sorted by username.
+func (f *Fake) GetGroupMembers(accessToken string, realmName string, groupID string, query keycloak.PageQuery) ([]keycloak.UserRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.group(groupID); err != nil {
+		return nil, err
+	}
+
+	var matches []*user
+	for _, u := range r.users {
+		if contains(u.groupIDs, groupID) {
+			matches = append(matches, u)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return *matches[i].rep.Username < *matches[j].rep.Username })
+
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.UserRepresentation{}
+	for _, u := range matches[first:last] {
+		res = append(res, u.representation())
+	}
+	return res, nil
+}
+
+// GetGroupCount returns the number of groups matching the query.
+func (f *Fake) GetGroupCount(accessToken string, realmName string, query keycloak.GroupCountQuery) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return 0, err
+	}
+	var count = 0
+	for _, g := range r.groups {
+		if (!query.Top || g.parentID == "") && (query.Search == "" || containsFold(g.rep.Name, query.Search)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// AddGroupRealmRoles adds realm roles to the group.
+func (f *Fake) AddGroupRealmRoles(accessToken string, realmName string, groupID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, g, err = f.realmAndGroup(realmName, groupID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(r.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !contains(g.realmRoles, id) {
+			g.realmRoles = append(g.realmRoles, id)
+		}
+	}
+	return nil
+}
+
+// DeleteGroupRealmRoles removes realm roles from the group.
+func (f *Fake) DeleteGroupRealmRoles(accessToken string, realmName string, groupID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, g, err = f.realmAndGroup(realmName, groupID)
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRoles(r.roles, roles)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		g.realmRoles = remove(g.realmRoles, id)
+	}
+	return nil
+}
+
+// GetGroupRealmRoles returns the realm roles of the group.
+func (f *Fake) GetGroupRealmRoles(accessToken string, realmName string, groupID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, g, err = f.realmAndGroup(realmName, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(r.roles, g.realmRoles), nil
+}
+
+// GetAvailableGroupRealmRoles returns the realm roles the group is not granted, even indirectly.
+func (f *Fake) GetAvailableGroupRealmRoles(accessToken string, realmName string, groupID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, g, err = f.realmAndGroup(realmName, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesNotIn(r.roles, r.effectiveGroupRoles(g)), nil
+}
+
+// GetEffectiveGroupRealmRoles returns the realm roles granted to the group, directly, through composite roles or
+// through its parent groups.
+func (f *Fake) GetEffectiveGroupRealmRoles(accessToken string, realmName string, groupID string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, g, err = f.realmAndGroup(realmName, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return rolesByID(r.roles, r.effectiveGroupRoles(g)), nil
+}
+
+func (f *Fake) realmAndGroup(realmName string, groupID string) (*realm, *group, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, nil, err
+	}
+	g, err := r.group(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, g, nil
+}
+
+// effectiveGroupRoles returns the IDs of the roles granted to the group or its parents, expanded with the
+// composites of these roles.
+func (r *realm) effectiveGroupRoles(g *group) []string {
+	var granted []string
+	for err := error(nil); err == nil; g, err = r.group(g.parentID) {
+		granted = append(granted, g.realmRoles...)
+		for _, ids := range g.clientRoles {
+			granted = append(granted, ids...)
+		}
+	}
+	return r.expandComposites(granted)
+}
+
+func (r *realm) checkSiblings(parentID string, name string) error {
+	for _, sibling := range r.groups {
+		if sibling.parentID == parentID && *sibling.rep.Name == name {
+			if parentID == "" {
+				return conflict(fmt.Sprintf("Top level group named '%s' already exists.", name))
+			}
+			return conflict(fmt.Sprintf("Sibling group named '%s' already exists.", name))
+		}
+	}
+	return nil
+}
+
+// updatePaths recomputes the path of the group and of its sub groups, after it was renamed or moved.
+func (r *realm) updatePaths(g *group) {
+	var parentPath = ""
+	if parent, err := r.group(g.parentID); err == nil {
+		parentPath = *parent.rep.Path
+	}
+	g.rep.Path = strPtr(parentPath + "/" + *g.rep.Name)
+	for _, child := range r.groups {
+		if child.parentID == *g.rep.ID {
+			r.updatePaths(child)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package keycloaktest
+
+import (
+	"fmt"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetIdps returns the identity providers of the realm.
+func (f *Fake) GetIdps(accessToken string, realmName string) ([]keycloak.IdentityProviderRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.IdentityProviderRepresentation{}
+	for _, idp := range r.idps {
+		res = append(res, idp.representation())
+	}
+	return res, nil
+}
+
+func (idp *identityProvider) representation() keycloak.IdentityProviderRepresentation {
+	var rep keycloak.IdentityProviderRepresentation
+	deepCopy(idp.rep, &rep)
+	return rep
+}
+
+// GetIdp returns the identity provider.
+func (f *Fake) GetIdp(accessToken string, realmName string, idpAlias string) (keycloak.IdentityProviderRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return keycloak.IdentityProviderRepresentation{}, err
+	}
+	return idp.representation(), nil
+}
+
+// CreateIdp creates the identity provider. Its alias must be unique within the realm.
+func (f *Fake) CreateIdp(accessToken string, realmName string, idpRep keycloak.IdentityProviderRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	idp, err := r.createIdp(idpRep)
+	if err != nil {
+		return "", err
+	}
+	return location(realmName, "identity-provider/instances", *idp.rep.Alias), nil
+}
+
+func (r *realm) createIdp(idpRep keycloak.IdentityProviderRepresentation) (*identityProvider, error) {
+	if idpRep.Alias == nil || *idpRep.Alias == "" || idpRep.ProviderID == nil {
+		return nil, badRequest("Identity provider alias and providerId are required")
+	}
+	if err := r.checkIdpUniqueness(*idpRep.Alias, ""); err != nil {
+		return nil, err
+	}
+
+	var idp = &identityProvider{}
+	deepCopy(idpRep, &idp.rep)
+	idp.rep.InternalID = strPtr(newID())
+	if idp.rep.Enabled == nil {
+		idp.rep.Enabled = boolPtr(true)
+	}
+	if idp.rep.FirstBrokerLoginFlowAlias == nil {
+		idp.rep.FirstBrokerLoginFlowAlias = strPtr("first broker login")
+	}
+	r.idps = append(r.idps, idp)
+	return idp, nil
+}
+
+// UpdateIdp updates the non nil fields of the identity provider. Changing its alias renames it.
+func (f *Fake) UpdateIdp(accessToken string, realmName string, idpAlias string, idpRep keycloak.IdentityProviderRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return err
+	}
+	if idpRep.Alias != nil {
+		if err = r.checkIdpUniqueness(*idpRep.Alias, idpAlias); err != nil {
+			return err
+		}
+		for i := range idp.mappers {
+			idp.mappers[i].IdentityProviderAlias = strPtr(*idpRep.Alias)
+		}
+	}
+	idpRep.InternalID = nil
+	merge(&idp.rep, idpRep)
+	return nil
+}
+
+// DeleteIdp deletes the identity provider, along with its mappers.
+func (f *Fake) DeleteIdp(accessToken string, realmName string, idpAlias string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return err
+	}
+	var idps []*identityProvider
+	for _, other := range r.idps {
+		if other != idp {
+			idps = append(idps, other)
+		}
+	}
+	r.idps = idps
+	return nil
+}
+
+// GetIdpMappers returns the mappers of the identity provider.
+func (f *Fake) GetIdpMappers(accessToken string, realmName string, idpAlias string) ([]keycloak.IdentityProviderMapperRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.IdentityProviderMapperRepresentation{}
+	deepCopy(idp.mappers, &res)
+	return res, nil
+}
+
+// GetIdpMapper returns a mapper of the identity provider.
+func (f *Fake) GetIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string) (keycloak.IdentityProviderMapperRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return keycloak.IdentityProviderMapperRepresentation{}, err
+	}
+	mapper, err := idp.mapper(mapperID)
+	if err != nil {
+		return keycloak.IdentityProviderMapperRepresentation{}, err
+	}
+	var rep keycloak.IdentityProviderMapperRepresentation
+	deepCopy(*mapper, &rep)
+	return rep, nil
+}
+
+// CreateIdpMapper adds a mapper to the identity provider.
+func (f *Fake) CreateIdpMapper(accessToken string, realmName string, idpAlias string, mapper keycloak.IdentityProviderMapperRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return "", err
+	}
+	if mapper.Name == nil || *mapper.Name == "" || mapper.IdentityProviderMapper == nil {
+		return "", badRequest("Identity provider mapper name and type are required")
+	}
+
+	var rep keycloak.IdentityProviderMapperRepresentation
+	deepCopy(mapper, &rep)
+	rep.ID = strPtr(newID())
+	rep.IdentityProviderAlias = strPtr(*idp.rep.Alias)
+	idp.mappers = append(idp.mappers, rep)
+	return location(realmName, "identity-provider/instances/"+idpAlias+"/mappers", *rep.ID), nil
+}
+
+// UpdateIdpMapper updates the non nil fields of a mapper of the identity provider.
+func (f *Fake) UpdateIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string, mapper keycloak.IdentityProviderMapperRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return err
+	}
+	existing, err := idp.mapper(mapperID)
+	if err != nil {
+		return err
+	}
+	mapper.ID, mapper.IdentityProviderAlias = nil, nil
+	merge(existing, mapper)
+	return nil
+}
+
+// DeleteIdpMapper deletes a mapper of the identity provider.
+func (f *Fake) DeleteIdpMapper(accessToken string, realmName string, idpAlias string, mapperID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return err
+	}
+	if _, err = idp.mapper(mapperID); err != nil {
+		return err
+	}
+	var mappers []keycloak.IdentityProviderMapperRepresentation
+	for _, mapper := range idp.mappers {
+		if *mapper.ID != mapperID {
+			mappers = append(mappers, mapper)
+		}
+	}
+	idp.mappers = mappers
+	return nil
+}
+
+// ImportIdpConfig is not implemented.
+func (f *Fake) ImportIdpConfig(accessToken string, realmName string, providerID string, fromURL string) (map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// ImportIdpConfigFromMetadata is not implemented.
+func (f *Fake) ImportIdpConfigFromMetadata(accessToken string, realmName string, providerID string, metadata []byte) (map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetIdpManagementPermissions tells whether the fine grained permissions of the identity provider are enabled.
+func (f *Fake) GetIdpManagementPermissions(accessToken string, realmName string, idpAlias string) (keycloak.ManagementPermissionReference, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return keycloak.ManagementPermissionReference{}, err
+	}
+	return idp.managementPermissions(), nil
+}
+
+// SetIdpManagementPermissions enables or disables the fine grained permissions of the identity provider. Enabling
+// them creates the token-exchange scope permission.
+func (f *Fake) SetIdpManagementPermissions(accessToken string, realmName string, idpAlias string, enabled bool) (keycloak.ManagementPermissionReference, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, idp, err = f.realmAndIdp(realmName, idpAlias)
+	if err != nil {
+		return keycloak.ManagementPermissionReference{}, err
+	}
+	switch {
+	case !enabled:
+		idp.permissions = nil
+	case idp.permissions == nil:
+		idp.permissions = &keycloak.ManagementPermissionReference{
+			Enabled:          boolPtr(true),
+			Resource:         strPtr(newID()),
+			ScopePermissions: &map[string]interface{}{"token-exchange": newID()},
+		}
+	}
+	return idp.managementPermissions(), nil
+}
+
+func (idp *identityProvider) managementPermissions() keycloak.ManagementPermissionReference {
+	if idp.permissions == nil {
+		return keycloak.ManagementPermissionReference{Enabled: boolPtr(false)}
+	}
+	var rep keycloak.ManagementPermissionReference
+	deepCopy(*idp.permissions, &rep)
+	return rep
+}
+
+func (idp *identityProvider) mapper(mapperID string) (*keycloak.IdentityProviderMapperRepresentation, error) {
+	for i := range idp.mappers {
+		if *idp.mappers[i].ID == mapperID {
+			return &idp.mappers[i], nil
+		}
+	}
+	return nil, notFound("Model not found")
+}
+
+func (f *Fake) realmAndIdp(realmName string, idpAlias string) (*realm, *identityProvider, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, idp := range r.idps {
+		if *idp.rep.Alias == idpAlias {
+			return r, idp, nil
+		}
+	}
+	return nil, nil, notFound("Could not find identity provider")
+}
+
+func (r *realm) checkIdpUniqueness(alias string, idpAlias string) error {
+	for _, other := range r.idps {
+		if *other.rep.Alias != idpAlias && *other.rep.Alias == alias {
+			return conflict(fmt.Sprintf("Identity Provider %s already exists", alias))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+package keycloaktest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// importedResource is a resource of a partial import. existing returns the id of the resource of the realm
+// having the same name, if any.
+type importedResource struct {
+	resourceType string
+	name         string
+	existing     func() string
+	create       func() (string, error)
+	overwrite    func(id string) (string, error)
+}
+
+var importLabels = map[string]string{
+	keycloak.PartialImportResourceClient:     "Client id",
+	keycloak.PartialImportResourceRealmRole:  "Realm role",
+	keycloak.PartialImportResourceClientRole: "Client role",
+	keycloak.PartialImportResourceIdp:        "Identity provider",
+	keycloak.PartialImportResourceGroup:      "Group",
+	keycloak.PartialImportResourceUser:       "User",
+}
+
+// PartialImport imports the clients, roles, identity providers, groups and users into the realm. It checks all
+// the resources before importing any, so that a conflict with IfResourceExistsFail imports nothing. With
+// IfResourceExistsOverwrite, the existing groups are recreated and the other resources are updated.
+func (f *Fake) PartialImport(accessToken string, realmName string, rep keycloak.PartialImportRepresentation) (keycloak.PartialImportResults, error) {
+	if err := rep.Validate(); err != nil {
+		return keycloak.PartialImportResults{}, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.PartialImportResults{}, err
+	}
+	resources, err := r.importedResources(rep)
+	if err != nil {
+		return keycloak.PartialImportResults{}, err
+	}
+	if rep.IfResourceExists == "" || rep.IfResourceExists == keycloak.IfResourceExistsFail {
+		for _, resource := range resources {
+			if resource.existing() != "" {
+				return keycloak.PartialImportResults{}, conflict(fmt.Sprintf("%s '%s' already exists", importLabels[resource.resourceType], resource.name))
+			}
+		}
+	}
+
+	var res = keycloak.PartialImportResults{Results: []keycloak.PartialImportResult{}}
+	for _, resource := range resources {
+		var result = keycloak.PartialImportResult{ResourceType: resource.resourceType, ResourceName: resource.name}
+		var id = resource.existing()
+		switch {
+		case id == "":
+			result.Action = keycloak.PartialImportActionAdded
+			result.ID, err = resource.create()
+			res.Added++
+		case rep.IfResourceExists == keycloak.IfResourceExistsSkip:
+			result.Action, result.ID = keycloak.PartialImportActionSkipped, id
+			res.Skipped++
+		default:
+			result.Action = keycloak.PartialImportActionOverwritten
+			result.ID, err = resource.overwrite(id)
+			res.Overwritten++
+		}
+		if err != nil {
+			return keycloak.PartialImportResults{}, err
+		}
+		res.Results = append(res.Results, result)
+	}
+	return res, nil
+}
+
+// importedResources lists the resources of the import, in the order Keycloak imports them, once checked that
+// they can be created.
+func (r *realm) importedResources(rep keycloak.PartialImportRepresentation) ([]importedResource, error) {
+	var resources []importedResource
+	var importedClients []string
+
+	if rep.Clients != nil {
+		for _, clientRep := range *rep.Clients {
+			if clientRep.ClientID == nil || *clientRep.ClientID == "" {
+				return nil, badRequest("Client id is missing")
+			}
+			resources = append(resources, r.importedClient(clientRep))
+			importedClients = append(importedClients, *clientRep.ClientID)
+		}
+	}
+	if rep.Roles != nil && rep.Roles.Realm != nil {
+		for _, role := range *rep.Roles.Realm {
+			if role.Name == nil || *role.Name == "" {
+				return nil, badRequest("Role name is missing")
+			}
+			resources = append(resources, r.importedRealmRole(role))
+		}
+	}
+	if rep.Roles != nil && rep.Roles.Client != nil {
+		var clientRoles map[string][]keycloak.RoleRepresentation
+		deepCopy(*rep.Roles.Client, &clientRoles)
+		for _, clientID := range sortedRoleKeys(clientRoles) {
+			if r.clientByClientID(clientID) == nil && !contains(importedClients, clientID) {
+				return nil, badRequest(fmt.Sprintf("Client '%s' does not exist", clientID))
+			}
+			for _, role := range clientRoles[clientID] {
+				if role.Name == nil || *role.Name == "" {
+					return nil, badRequest("Role name is missing")
+				}
+				resources = append(resources, r.importedClientRole(clientID, role))
+			}
+		}
+	}
+	if rep.IdentityProviders != nil {
+		for _, idpRep := range *rep.IdentityProviders {
+			if idpRep.Alias == nil || *idpRep.Alias == "" || idpRep.ProviderID == nil {
+				return nil, badRequest("Identity provider alias and providerId are required")
+			}
+			resources = append(resources, r.importedIdp(idpRep))
+		}
+	}
+	if rep.Groups != nil {
+		for _, groupRep := range *rep.Groups {
+			if groupRep.Name == nil || *groupRep.Name == "" {
+				return nil, badRequest("Group name is missing")
+			}
+			resources = append(resources, r.importedGroup(groupRep))
+		}
+	}
+	if rep.Users != nil {
+		for _, userRep := range *rep.Users {
+			if userRep.Username == nil || *userRep.Username == "" {
+				return nil, badRequest("User name is missing")
+			}
+			resources = append(resources, r.importedUser(userRep))
+		}
+	}
+	return resources, nil
+}
+
+func (r *realm) importedClient(clientRep keycloak.ClientRepresentation) importedResource {
+	return importedResource{
+		resourceType: keycloak.PartialImportResourceClient,
+		name:         *clientRep.ClientID,
+		existing: func() string {
+			if c := r.clientByClientID(*clientRep.ClientID); c != nil {
+				return *c.rep.ID
+			}
+			return ""
+		},
+		create: func() (string, error) {
+			var c, err = r.createClient(clientRep)
+			if err != nil {
+				return "", err
+			}
+			return *c.rep.ID, nil
+		},
+		overwrite: func(id string) (string, error) {
+			var c, _ = r.client(id)
+			var update keycloak.ClientRepresentation
+			deepCopy(clientRep, &update)
+			update.ID, update.ProtocolMappers = nil, nil
+			merge(&c.rep, update)
+			r.updateServiceAccount(c)
+			c.updateResourceServer()
+			return id, nil
+		},
+	}
+}
+
+func (r *realm) importedRealmRole(role keycloak.RoleRepresentation) importedResource {
+	return importedResource{
+		resourceType: keycloak.PartialImportResourceRealmRole,
+		name:         *role.Name,
+		existing: func() string {
+			if existing, err := r.roleByName(*role.Name); err == nil {
+				return *existing.ID
+			}
+			return ""
+		},
+		create: func() (string, error) {
+			var created = r.newRole(role, *r.rep.ID, false)
+			r.roles = append(r.roles, created)
+			return *created.ID, nil
+		},
+		overwrite: func(id string) (string, error) {
+			var existing, _ = r.role(id)
+			return id, r.updateRole(existing, keycloak.RoleRepresentation{Description: role.Description})
+		},
+	}
+}
+
+func (r *realm) importedClientRole(clientID string, role keycloak.RoleRepresentation) importedResource {
+	var find = func() *keycloak.RoleRepresentation {
+		var c = r.clientByClientID(clientID)
+		if c == nil {
+			return nil
+		}
+		for i := range c.roles {
+			if *c.roles[i].Name == *role.Name {
+				return &c.roles[i]
+			}
+		}
+		return nil
+	}
+	return importedResource{
+		resourceType: keycloak.PartialImportResourceClientRole,
+		name:         *role.Name,
+		existing: func() string {
+			if existing := find(); existing != nil {
+				return *existing.ID
+			}
+			return ""
+		},
+		create: func() (string, error) {
+			var c = r.clientByClientID(clientID)
+			var created = r.newRole(role, *c.rep.ID, true)
+			c.roles = append(c.roles, created)
+			return *created.ID, nil
+		},
+		overwrite: func(id string) (string, error) {
+			return id, r.updateRole(find(), keycloak.RoleRepresentation{Description: role.Description})
+		},
+	}
+}
+
+func (r *realm) importedIdp(idpRep keycloak.IdentityProviderRepresentation) importedResource {
+	var find = func() *identityProvider {
+		for _, idp := range r.idps {
+			if *idp.rep.Alias == *idpRep.Alias {
+				return idp
+			}
+		}
+		return nil
+	}
+	return importedResource{
+		resourceType: keycloak.PartialImportResourceIdp,
+		name:         *idpRep.Alias,
+		existing: func() string {
+			if idp := find(); idp != nil {
+				return *idp.rep.InternalID
+			}
+			return ""
+		},
+		create: func() (string, error) {
+			var idp, err = r.createIdp(idpRep)
+			if err != nil {
+				return "", err
+			}
+			return *idp.rep.InternalID, nil
+		},
+		overwrite: func(id string) (string, error) {
+			var idp = find()
+			var update keycloak.IdentityProviderRepresentation
+			deepCopy(idpRep, &update)
+			update.InternalID = nil
+			merge(&idp.rep, update)
+			return id, nil
+		},
+	}
+}
+
+func (r *realm) importedGroup(groupRep keycloak.GroupRepresentation) importedResource {
+	var create = func() (string, error) {
+		var g, err = r.createGroup(groupRep, nil)
+		if err != nil {
+			return "", err
+		}
+		return *g.rep.ID, nil
+	}
+	return importedResource{
+		resourceType: keycloak.PartialImportResourceGroup,
+		name:         *groupRep.Name,
+		existing: func() string {
+			for _, g := range r.groups {
+				if g.parentID == "" && *g.rep.Name == *groupRep.Name {
+					return *g.rep.ID
+				}
+			}
+			return ""
+		},
+		create: create,
+		overwrite: func(id string) (string, error) {
+			r.deleteGroup(id)
+			return create()
+		},
+	}
+}
+
+func (r *realm) importedUser(userRep keycloak.UserRepresentation) importedResource {
+	var username = strings.ToLower(*userRep.Username)
+	var find = func() *user {
+		for _, u := range r.users {
+			if *u.rep.Username == username {
+				return u
+			}
+		}
+		return nil
+	}
+	return importedResource{
+		resourceType: keycloak.PartialImportResourceUser,
+		name:         username,
+		existing: func() string {
+			if u := find(); u != nil {
+				return *u.rep.ID
+			}
+			return ""
+		},
+		create: func() (string, error) {
+			if _, err := r.createUser(*r.rep.Realm, userRep); err != nil {
+				return "", err
+			}
+			return *find().rep.ID, nil
+		},
+		overwrite: func(id string) (string, error) {
+			var update keycloak.UserRepresentation
+			deepCopy(userRep, &update)
+			update.Username = strPtr(username)
+			if err := r.checkUserUniqueness(update, id); err != nil {
+				return "", err
+			}
+			update.ID, update.CreatedTimestamp, update.Credentials, update.Groups = nil, nil, nil, nil
+			update.RealmRoles, update.ClientRoles = nil, nil
+			merge(&find().rep, update)
+			return id, nil
+		},
+	}
+}
+
+func (r *realm) clientByClientID(clientID string) *client {
+	for _, c := range r.clients {
+		if *c.rep.ClientID == clientID {
+			return c
+		}
+	}
+	return nil
+}
+
+func sortedRoleKeys(m map[string][]keycloak.RoleRepresentation) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PartialExport returns the realm along with, according to the options, its groups, roles and clients, whose
+// secrets are masked.
+func (f *Fake) PartialExport(accessToken string, realmName string, options keycloak.PartialExportOptions) (keycloak.RealmRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.RealmRepresentation{}, err
+	}
+	return r.export(options), nil
+}
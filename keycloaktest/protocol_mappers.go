@@ -0,0 +1,182 @@
+package keycloaktest
+
+import (
+	"fmt"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetClientProtocolMappers returns the protocol mappers defined on the client.
+func (f *Fake) GetClientProtocolMappers(accessToken string, realmName, idClient string) ([]keycloak.ProtocolMapperRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return nil, err
+	}
+	return protocolMappers(c.rep.ProtocolMappers), nil
+}
+
+// GetClientProtocolMapper returns a protocol mapper of the client.
+func (f *Fake) GetClientProtocolMapper(accessToken string, realmName, idClient, mapperID string) (keycloak.ProtocolMapperRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return keycloak.ProtocolMapperRepresentation{}, err
+	}
+	mapper, err := protocolMapper(c.rep.ProtocolMappers, mapperID)
+	if err != nil {
+		return keycloak.ProtocolMapperRepresentation{}, err
+	}
+	var rep keycloak.ProtocolMapperRepresentation
+	deepCopy(*mapper, &rep)
+	return rep, nil
+}
+
+// CreateClientProtocolMapper adds a protocol mapper to the client. Its name must be unique within the client.
+func (f *Fake) CreateClientProtocolMapper(accessToken string, realmName, idClient string, mapper keycloak.ProtocolMapperRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return "", err
+	}
+	ids, err := addProtocolMappers(&c.rep.ProtocolMappers, *c.rep.Protocol, mapper)
+	if err != nil {
+		return "", err
+	}
+	return location(realmName, "clients/"+idClient+"/protocol-mappers/models", ids[0]), nil
+}
+
+// AddClientProtocolMappers adds several protocol mappers to the client. None is added if one of them is rejected.
+func (f *Fake) AddClientProtocolMappers(accessToken string, realmName, idClient string, mappers []keycloak.ProtocolMapperRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	_, err = addProtocolMappers(&c.rep.ProtocolMappers, *c.rep.Protocol, mappers...)
+	return err
+}
+
+// UpdateClientProtocolMapper updates the non nil fields of a protocol mapper of the client.
+func (f *Fake) UpdateClientProtocolMapper(accessToken string, realmName, idClient, mapperID string, mapper keycloak.ProtocolMapperRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	return updateProtocolMapper(c.rep.ProtocolMappers, mapperID, mapper)
+}
+
+// DeleteClientProtocolMapper deletes a protocol mapper of the client.
+func (f *Fake) DeleteClientProtocolMapper(accessToken string, realmName, idClient, mapperID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return err
+	}
+	return deleteProtocolMapper(&c.rep.ProtocolMappers, mapperID)
+}
+
+// AddClientScopeMappers adds several protocol mappers to the client scope. None is added if one of them is
+// rejected.
+func (f *Fake) AddClientScopeMappers(accessToken string, realmName, scopeID string, mappers []keycloak.ProtocolMapperRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var _, s, err = f.realmAndClientScope(realmName, scopeID)
+	if err != nil {
+		return err
+	}
+	_, err = addProtocolMappers(&s.rep.ProtocolMappers, *s.rep.Protocol, mappers...)
+	return err
+}
+
+func protocolMappers(mappers *[]keycloak.ProtocolMapperRepresentation) []keycloak.ProtocolMapperRepresentation {
+	var res = []keycloak.ProtocolMapperRepresentation{}
+	if mappers != nil {
+		deepCopy(*mappers, &res)
+	}
+	return res
+}
+
+func protocolMapper(mappers *[]keycloak.ProtocolMapperRepresentation, mapperID string) (*keycloak.ProtocolMapperRepresentation, error) {
+	if mappers != nil {
+		for i := range *mappers {
+			if *(*mappers)[i].ID == mapperID {
+				return &(*mappers)[i], nil
+			}
+		}
+	}
+	return nil, notFound("Model not found")
+}
+
+// addProtocolMappers appends copies of the given mappers to mappers and returns their IDs. The mappers are added
+// all or none, and default to the protocol of their container.
+func addProtocolMappers(mappers **[]keycloak.ProtocolMapperRepresentation, protocol string, added ...keycloak.ProtocolMapperRepresentation) ([]string, error) {
+	var res = protocolMappers(*mappers)
+	var ids []string
+	for _, mapper := range added {
+		if mapper.Name == nil || *mapper.Name == "" || mapper.ProtocolMapper == nil {
+			return nil, badRequest("Protocol mapper name and type are required")
+		}
+		for _, other := range res {
+			if *other.Name == *mapper.Name {
+				return nil, conflict(fmt.Sprintf("Protocol mapper exists with same name: %s", *mapper.Name))
+			}
+		}
+
+		var rep keycloak.ProtocolMapperRepresentation
+		deepCopy(mapper, &rep)
+		rep.ID = strPtr(newID())
+		if rep.Protocol == nil {
+			rep.Protocol = strPtr(protocol)
+		}
+		res = append(res, rep)
+		ids = append(ids, *rep.ID)
+	}
+	*mappers = &res
+	return ids, nil
+}
+
+func updateProtocolMapper(mappers *[]keycloak.ProtocolMapperRepresentation, mapperID string, mapper keycloak.ProtocolMapperRepresentation) error {
+	var existing, err = protocolMapper(mappers, mapperID)
+	if err != nil {
+		return err
+	}
+	if mapper.Name != nil {
+		for _, other := range *mappers {
+			if *other.ID != mapperID && *other.Name == *mapper.Name {
+				return conflict(fmt.Sprintf("Protocol mapper exists with same name: %s", *mapper.Name))
+			}
+		}
+	}
+	mapper.ID = nil
+	merge(existing, mapper)
+	return nil
+}
+
+func deleteProtocolMapper(mappers **[]keycloak.ProtocolMapperRepresentation, mapperID string) error {
+	if _, err := protocolMapper(*mappers, mapperID); err != nil {
+		return err
+	}
+	var res = []keycloak.ProtocolMapperRepresentation{}
+	for _, mapper := range **mappers {
+		if *mapper.ID != mapperID {
+			res = append(res, mapper)
+		}
+	}
+	**mappers = res
+	return nil
+}
@@ -0,0 +1,363 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetToken returns a fake access token.
+func (f *Fake) GetToken(realm string, username string, password string) (string, error) {
+	return fakeAccessToken, nil
+}
+
+// GetTokenWithClientCredentials returns a fake token.
+func (f *Fake) GetTokenWithClientCredentials(realm string, clientID string, clientSecret string) (keycloak.TokenResponse, error) {
+	return fakeTokenResponse(), nil
+}
+
+// GetTokenWithClientAssertion returns a fake token.
+func (f *Fake) GetTokenWithClientAssertion(realm string, clientID string, clientAssertion string) (keycloak.TokenResponse, error) {
+	return fakeTokenResponse(), nil
+}
+
+// RefreshToken returns a fake token.
+func (f *Fake) RefreshToken(realm string, refreshToken string, tokenReq keycloak.TokenRequest) (keycloak.TokenResponse, error) {
+	return fakeTokenResponse(), nil
+}
+
+// ExchangeToken returns a fake token.
+func (f *Fake) ExchangeToken(realm string, tokenReq keycloak.TokenRequest) (keycloak.TokenResponse, error) {
+	return fakeTokenResponse(), nil
+}
+
+// RequestToken returns a fake token.
+func (f *Fake) RequestToken(realm string, tokenReq keycloak.TokenRequest) (keycloak.TokenResponse, error) {
+	return fakeTokenResponse(), nil
+}
+
+func fakeTokenResponse() keycloak.TokenResponse {
+	return keycloak.TokenResponse{
+		AccessToken:      fakeAccessToken,
+		ExpiresIn:        300,
+		RefreshToken:     "keycloaktest-refresh-token",
+		RefreshExpiresIn: 1800,
+		TokenType:        "bearer",
+	}
+}
+
+// GetRealms returns the top level representation of all the realms.
+func (f *Fake) GetRealms(accessToken string) ([]keycloak.RealmRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var res = []keycloak.RealmRepresentation{}
+	for _, r := range f.realms {
+		var rep keycloak.RealmRepresentation
+		deepCopy(r.rep, &rep)
+		res = append(res, rep)
+	}
+	return res, nil
+}
+
+// CreateRealm creates the realm, importing the users, groups, roles, client scopes, clients, components,
+// authentication flows and required actions it contains.
+func (f *Fake) CreateRealm(accessToken string, realmRep keycloak.RealmRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if realmRep.Realm == nil || *realmRep.Realm == "" {
+		return "", badRequest("Realm name cannot be empty")
+	}
+	var name = *realmRep.Realm
+	if _, ok := f.realms[name]; ok {
+		return "", conflict("Conflict detected. See logs for details")
+	}
+
+	var r = &realm{}
+	deepCopy(realmRep, &r.rep)
+	if r.rep.ID == nil {
+		r.rep.ID = strPtr(name)
+	}
+	r.rep.Users, r.rep.Groups, r.rep.Clients, r.rep.Roles, r.rep.Components = nil, nil, nil, nil, nil
+	r.rep.ClientScopes, r.rep.AuthenticationFlows, r.rep.AuthenticatorConfig, r.rep.RequiredActions = nil, nil, nil, nil
+
+	if err := r.importContent(realmRep); err != nil {
+		return "", err
+	}
+	f.realms[name] = r
+	return BaseURL + "/auth/admin/realms/" + name, nil
+}
+
+func (r *realm) importContent(realmRep keycloak.RealmRepresentation) error {
+	var name = *r.rep.Realm
+
+	if realmRep.Roles != nil && realmRep.Roles.Realm != nil {
+		for _, role := range *realmRep.Roles.Realm {
+			r.roles = append(r.roles, r.newRole(role, *r.rep.ID, false))
+		}
+	}
+	if realmRep.ClientScopes != nil {
+		for _, scope := range *realmRep.ClientScopes {
+			if _, err := r.createClientScope(scope); err != nil {
+				return err
+			}
+		}
+	}
+	if realmRep.Clients != nil {
+		for _, c := range *realmRep.Clients {
+			if _, err := r.createClient(c); err != nil {
+				return err
+			}
+		}
+	}
+	if realmRep.Roles != nil && realmRep.Roles.Client != nil {
+		var clientRoles map[string][]keycloak.RoleRepresentation
+		deepCopy(*realmRep.Roles.Client, &clientRoles)
+		for clientID, roles := range clientRoles {
+			for _, c := range r.clients {
+				if c.rep.ClientID != nil && *c.rep.ClientID == clientID {
+					for _, role := range roles {
+						c.roles = append(c.roles, r.newRole(role, *c.rep.ID, true))
+					}
+				}
+			}
+		}
+	}
+	if realmRep.Groups != nil {
+		for _, g := range *realmRep.Groups {
+			if _, err := r.createGroup(g, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if realmRep.Users != nil {
+		for _, u := range *realmRep.Users {
+			if _, err := r.createUser(name, u); err != nil {
+				return err
+			}
+		}
+	}
+	if realmRep.Components != nil {
+		r.importComponents(*realmRep.Components, *r.rep.ID)
+	}
+	if err := r.importFlows(realmRep); err != nil {
+		return err
+	}
+	if realmRep.RequiredActions != nil {
+		for _, action := range *realmRep.RequiredActions {
+			if err := r.registerRequiredAction(action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importFlows imports the flows, then their executions which reference the sub flows and the configurations by
+// alias.
+func (r *realm) importFlows(realmRep keycloak.RealmRepresentation) error {
+	if realmRep.AuthenticationFlows == nil {
+		return nil
+	}
+	var configIDs = map[string]string{}
+	if realmRep.AuthenticatorConfig != nil {
+		for _, config := range *realmRep.AuthenticatorConfig {
+			var created, err = r.createAuthenticatorConfig(config)
+			if err != nil {
+				return err
+			}
+			configIDs[*created.Alias] = *created.ID
+		}
+	}
+	for _, flow := range *realmRep.AuthenticationFlows {
+		if _, err := r.createFlow(flow); err != nil {
+			return err
+		}
+	}
+	for _, flow := range *realmRep.AuthenticationFlows {
+		if flow.AuthenticationExecutions == nil {
+			continue
+		}
+		var flowID = *r.flowByAlias(*flow.Alias).ID
+		for _, exported := range *flow.AuthenticationExecutions {
+			var e = &execution{flowID: flowID}
+			if exported.Authenticator != nil {
+				e.authenticator = *exported.Authenticator
+			}
+			if exported.FlowAlias != nil {
+				var sub = r.flowByAlias(*exported.FlowAlias)
+				if sub == nil {
+					return badRequest("Flow " + *exported.FlowAlias + " does not exist")
+				}
+				e.subFlowID = *sub.ID
+			}
+			if exported.AuthenticatorConfig != nil {
+				e.configID = configIDs[*exported.AuthenticatorConfig]
+			}
+			r.addExecution(e)
+			if exported.Requirement != nil {
+				e.requirement = *exported.Requirement
+			}
+		}
+	}
+	return nil
+}
+
+func (r *realm) importComponents(components keycloak.ComponentsExportRepresentation, parentID string) {
+	for _, providerType := range sortedKeys(components) {
+		for _, exported := range components[providerType] {
+			var component = keycloak.ComponentRepresentation{
+				ID:           exported.ID,
+				Name:         exported.Name,
+				ParentID:     strPtr(parentID),
+				ProviderID:   exported.ProviderID,
+				ProviderType: strPtr(providerType),
+				SubType:      exported.SubType,
+				Config:       exported.Config,
+			}
+			if component.ID == nil {
+				component.ID = strPtr(newID())
+			}
+			r.components = append(r.components, component)
+			if exported.SubComponents != nil {
+				r.importComponents(*exported.SubComponents, *component.ID)
+			}
+		}
+	}
+}
+
+// GetRealm returns the top level representation of the realm.
+func (f *Fake) GetRealm(accessToken string, realmName string) (keycloak.RealmRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var rep keycloak.RealmRepresentation
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return rep, err
+	}
+	deepCopy(r.rep, &rep)
+	return rep, nil
+}
+
+// UpdateRealm updates the top level information of the realm.
+func (f *Fake) UpdateRealm(accessToken string, realmName string, realmRep keycloak.RealmRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	realmRep.Users, realmRep.Groups, realmRep.Clients, realmRep.Roles, realmRep.Components = nil, nil, nil, nil, nil
+	realmRep.ClientScopes, realmRep.AuthenticationFlows, realmRep.AuthenticatorConfig, realmRep.RequiredActions = nil, nil, nil, nil
+	realmRep.ID = nil
+	if realmRep.Realm != nil && *realmRep.Realm != realmName {
+		if _, ok := f.realms[*realmRep.Realm]; ok {
+			return conflict("Realm with same name exists")
+		}
+		delete(f.realms, realmName)
+		f.realms[*realmRep.Realm] = r
+	}
+	merge(&r.rep, realmRep)
+	return nil
+}
+
+// DeleteRealm deletes the realm.
+func (f *Fake) DeleteRealm(accessToken string, realmName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, err := f.realm(realmName); err != nil {
+		return err
+	}
+	delete(f.realms, realmName)
+	return nil
+}
+
+// ExportRealm returns the realm along with its groups, roles, client scopes, clients, components, authentication
+// flows and required actions. Unlike PartialExport, it keeps the secrets of the clients.
+func (f *Fake) ExportRealm(accessToken string, realmName string) (keycloak.RealmRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.RealmRepresentation{}, err
+	}
+	var rep = r.export(keycloak.PartialExportOptions{GroupsAndRoles: true, Clients: true})
+	var clients = []keycloak.ClientRepresentation{}
+	for _, c := range r.clients {
+		clients = append(clients, c.representation())
+	}
+	rep.Clients = &clients
+	return rep, nil
+}
+
+func (r *realm) export(options keycloak.PartialExportOptions) keycloak.RealmRepresentation {
+	var rep keycloak.RealmRepresentation
+	deepCopy(r.rep, &rep)
+
+	if options.Clients {
+		var clients = []keycloak.ClientRepresentation{}
+		for _, c := range r.clients {
+			var exported = c.representation()
+			if exported.Secret != nil {
+				exported.Secret = strPtr(maskedSecret)
+			}
+			clients = append(clients, exported)
+		}
+		rep.Clients = &clients
+	}
+	if options.GroupsAndRoles {
+		var clientRoles = map[string]interface{}{}
+		for _, c := range r.clients {
+			clientRoles[*c.rep.ClientID] = c.roles
+		}
+		var realmRoles = append([]keycloak.RoleRepresentation{}, r.roles...)
+		var groups = r.groupTree("")
+		deepCopy(groups, &rep.Groups)
+		deepCopy(keycloak.RolesRepresentation{Realm: &realmRoles, Client: &clientRoles}, &rep.Roles)
+	}
+	var components = keycloak.ComponentsExportRepresentation{}
+	r.exportComponents(components, *r.rep.ID)
+	rep.Components = &components
+	var scopes = []keycloak.ClientScopeRepresentation{}
+	for _, s := range r.clientScopes {
+		scopes = append(scopes, s.representation())
+	}
+	rep.ClientScopes = &scopes
+	var flows = []keycloak.AuthenticationFlowRepresentation{}
+	for _, flow := range r.flows {
+		flows = append(flows, r.flowRepresentation(flow))
+	}
+	rep.AuthenticationFlows = &flows
+	var configs = []keycloak.AuthenticatorConfigRepresentation{}
+	deepCopy(r.authenticatorConfigs, &configs)
+	rep.AuthenticatorConfig = &configs
+	deepCopy(r.requiredActions, &rep.RequiredActions)
+	return rep
+}
+
+func (r *realm) exportComponents(dst keycloak.ComponentsExportRepresentation, parentID string) {
+	for _, component := range r.components {
+		if component.ParentID == nil || *component.ParentID != parentID || component.ProviderType == nil {
+			continue
+		}
+		var exported = keycloak.ComponentExportRepresentation{
+			ID:         component.ID,
+			Name:       component.Name,
+			ProviderID: component.ProviderID,
+			SubType:    component.SubType,
+			Config:     component.Config,
+		}
+		var sub = keycloak.ComponentsExportRepresentation{}
+		r.exportComponents(sub, *component.ID)
+		exported.SubComponents = &sub
+		dst[*component.ProviderType] = append(dst[*component.ProviderType], exported)
+	}
+}
+
+// GetRealmCredentialRegistrators is not implemented.
+func (f *Fake) GetRealmCredentialRegistrators(accessToken string, realmName string) ([]string, error) {
+	return nil, ErrNotImplemented
+}
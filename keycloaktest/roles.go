@@ -0,0 +1,519 @@
+package keycloaktest
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// newRole returns a copy of role with a fresh ID, owned by the realm or client containerID.
+func (r *realm) newRole(role keycloak.RoleRepresentation, containerID string, clientRole bool) keycloak.RoleRepresentation {
+	var res = cloneRole(role)
+	res.ID = strPtr(newID())
+	res.ContainerID = strPtr(containerID)
+	res.ClientRole = boolPtr(clientRole)
+	if res.Composite == nil {
+		res.Composite = boolPtr(false)
+	}
+	return res
+}
+
+// GetClientRoles returns the roles of the client.
+func (f *Fake) GetClientRoles(accessToken string, realmName, idClient string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.RoleRepresentation{}
+	for _, role := range c.roles {
+		res = append(res, cloneRole(role))
+	}
+	return res, nil
+}
+
+// CreateClientRole creates a role for the client. Its name must be unique within the client.
+func (f *Fake) CreateClientRole(accessToken string, realmName, clientID string, role keycloak.RoleRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	c, err := r.client(clientID)
+	if err != nil {
+		return "", err
+	}
+	if role.Name == nil || *role.Name == "" {
+		return "", badRequest("Role name is missing")
+	}
+	for _, existing := range c.roles {
+		if *existing.Name == *role.Name {
+			return "", conflict(fmt.Sprintf("Role with name %s already exists", *role.Name))
+		}
+	}
+	c.roles = append(c.roles, r.newRole(role, clientID, true))
+	return location(realmName, "clients", clientID+"/roles/"+url.PathEscape(*role.Name)), nil
+}
+
+// GetRoles returns the realm roles.
+func (f *Fake) GetRoles(accessToken string, realmName string) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	var res = []keycloak.RoleRepresentation{}
+	for _, role := range r.roles {
+		res = append(res, cloneRole(role))
+	}
+	return res, nil
+}
+
+// GetRole returns the realm or client role.
+func (f *Fake) GetRole(accessToken string, realmName string, roleID string) (keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.RoleRepresentation{}, err
+	}
+	role, err := r.role(roleID)
+	if err != nil {
+		return keycloak.RoleRepresentation{}, err
+	}
+	return cloneRole(*role), nil
+}
+
+// CreateRole creates a realm role. Its name must be unique within the realm.
+func (f *Fake) CreateRole(accessToken string, realmName string, role keycloak.RoleRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	if role.Name == nil || *role.Name == "" {
+		return "", badRequest("Role name is missing")
+	}
+	if _, err = r.roleByName(*role.Name); err == nil {
+		return "", conflict(fmt.Sprintf("Role with name %s already exists", *role.Name))
+	}
+	r.roles = append(r.roles, r.newRole(role, *r.rep.ID, false))
+	return location(realmName, "roles", url.PathEscape(*role.Name)), nil
+}
+
+// GetRoleByName returns the realm role with the given name.
+func (f *Fake) GetRoleByName(accessToken string, realmName, roleName string) (keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.roleByName(realmName, roleName)
+	if err != nil {
+		return keycloak.RoleRepresentation{}, err
+	}
+	return cloneRole(*role), nil
+}
+
+// UpdateRole updates the name and the description of the realm role.
+func (f *Fake) UpdateRole(accessToken string, realmName, roleName string, role keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var existing, err = f.roleByName(realmName, roleName)
+	if err != nil {
+		return err
+	}
+	return f.realms[realmName].updateRole(existing, role)
+}
+
+// UpdateRoleByID updates the name and the description of the realm or client role.
+func (f *Fake) UpdateRoleByID(accessToken string, realmName, roleID string, role keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	existing, err := r.role(roleID)
+	if err != nil {
+		return err
+	}
+	return r.updateRole(existing, role)
+}
+
+// DeleteRole deletes the realm role, revoking it from the users and groups holding it.
+func (f *Fake) DeleteRole(accessToken string, realmName, roleName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.roleByName(realmName, roleName)
+	if err != nil {
+		return err
+	}
+	f.realms[realmName].deleteRole(*role.ID)
+	return nil
+}
+
+// DeleteRoleByID deletes the realm or client role, revoking it from the users and groups holding it.
+func (f *Fake) DeleteRoleByID(accessToken string, realmName, roleID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if _, err = r.role(roleID); err != nil {
+		return err
+	}
+	r.deleteRole(roleID)
+	return nil
+}
+
+// GetRoleUsers returns the users directly granted the realm role, sorted by username.
+func (f *Fake) GetRoleUsers(accessToken string, realmName, roleName string, query keycloak.PageQuery) ([]keycloak.UserRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.roleByName(realmName, roleName)
+	if err != nil {
+		return nil, err
+	}
+	return f.realms[realmName].roleUsers(*role.ID, query), nil
+}
+
+// GetRoleGroups returns the groups directly granted the realm role, sorted by path.
+func (f *Fake) GetRoleGroups(accessToken string, realmName, roleName string, query keycloak.PageQuery) ([]keycloak.GroupRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.roleByName(realmName, roleName)
+	if err != nil {
+		return nil, err
+	}
+	return f.realms[realmName].roleGroups(*role.ID, query), nil
+}
+
+// GetClientRole returns the client role with the given name.
+func (f *Fake) GetClientRole(accessToken string, realmName, idClient, roleName string) (keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.clientRoleByName(realmName, idClient, roleName)
+	if err != nil {
+		return keycloak.RoleRepresentation{}, err
+	}
+	return cloneRole(*role), nil
+}
+
+// UpdateClientRole updates the name and the description of the client role.
+func (f *Fake) UpdateClientRole(accessToken string, realmName, idClient, roleName string, role keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var existing, err = f.clientRoleByName(realmName, idClient, roleName)
+	if err != nil {
+		return err
+	}
+	return f.realms[realmName].updateRole(existing, role)
+}
+
+// DeleteClientRole deletes the client role, revoking it from the users and groups holding it.
+func (f *Fake) DeleteClientRole(accessToken string, realmName, idClient, roleName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.clientRoleByName(realmName, idClient, roleName)
+	if err != nil {
+		return err
+	}
+	f.realms[realmName].deleteRole(*role.ID)
+	return nil
+}
+
+// GetClientRoleUsers returns the users directly granted the client role, sorted by username.
+func (f *Fake) GetClientRoleUsers(accessToken string, realmName, idClient, roleName string, query keycloak.PageQuery) ([]keycloak.UserRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.clientRoleByName(realmName, idClient, roleName)
+	if err != nil {
+		return nil, err
+	}
+	return f.realms[realmName].roleUsers(*role.ID, query), nil
+}
+
+// GetClientRoleGroups returns the groups directly granted the client role, sorted by path.
+func (f *Fake) GetClientRoleGroups(accessToken string, realmName, idClient, roleName string, query keycloak.PageQuery) ([]keycloak.GroupRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var role, err = f.clientRoleByName(realmName, idClient, roleName)
+	if err != nil {
+		return nil, err
+	}
+	return f.realms[realmName].roleGroups(*role.ID, query), nil
+}
+
+// GetRoleComposites returns the roles directly included in the role.
+func (f *Fake) GetRoleComposites(accessToken string, realmName, roleID string) ([]keycloak.RoleRepresentation, error) {
+	return f.roleComposites(realmName, roleID, func(keycloak.RoleRepresentation) bool { return true })
+}
+
+// GetRealmRoleComposites returns the realm roles directly included in the role.
+func (f *Fake) GetRealmRoleComposites(accessToken string, realmName, roleID string) ([]keycloak.RoleRepresentation, error) {
+	return f.roleComposites(realmName, roleID, func(role keycloak.RoleRepresentation) bool { return !*role.ClientRole })
+}
+
+// GetClientRoleComposites returns the roles of the client directly included in the role.
+func (f *Fake) GetClientRoleComposites(accessToken string, realmName, roleID, idClient string) ([]keycloak.RoleRepresentation, error) {
+	return f.roleComposites(realmName, roleID, func(role keycloak.RoleRepresentation) bool {
+		return *role.ClientRole && *role.ContainerID == idClient
+	})
+}
+
+// AddRoleComposites includes the roles, identified by ID, in the role.
+func (f *Fake) AddRoleComposites(accessToken string, realmName, roleID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	composite, err := r.role(roleID)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, role := range roles {
+		if role.ID == nil {
+			return notFound("Could not find composite role")
+		}
+		if _, err = r.role(*role.ID); err != nil {
+			return err
+		}
+		ids = append(ids, *role.ID)
+	}
+
+	if r.composites == nil {
+		r.composites = map[string][]string{}
+	}
+	for _, id := range ids {
+		if !contains(r.composites[roleID], id) {
+			r.composites[roleID] = append(r.composites[roleID], id)
+		}
+	}
+	composite.Composite = boolPtr(len(r.composites[roleID]) > 0)
+	return nil
+}
+
+// DeleteRoleComposites removes the roles, identified by ID, from the role.
+func (f *Fake) DeleteRoleComposites(accessToken string, realmName, roleID string, roles []keycloak.RoleRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	composite, err := r.role(roleID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if role.ID != nil {
+			r.composites[roleID] = remove(r.composites[roleID], *role.ID)
+		}
+	}
+	composite.Composite = boolPtr(len(r.composites[roleID]) > 0)
+	return nil
+}
+
+func (f *Fake) roleComposites(realmName, roleID string, match func(keycloak.RoleRepresentation) bool) ([]keycloak.RoleRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.role(roleID); err != nil {
+		return nil, err
+	}
+	var res = []keycloak.RoleRepresentation{}
+	for _, id := range r.composites[roleID] {
+		var role, err = r.role(id)
+		if err == nil && match(*role) {
+			res = append(res, cloneRole(*role))
+		}
+	}
+	return res, nil
+}
+
+func (f *Fake) roleByName(realmName, roleName string) (*keycloak.RoleRepresentation, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	return r.roleByName(roleName)
+}
+
+func (f *Fake) clientRoleByName(realmName, idClient, roleName string) (*keycloak.RoleRepresentation, error) {
+	var c, err = f.client(realmName, idClient)
+	if err != nil {
+		return nil, err
+	}
+	for i := range c.roles {
+		if *c.roles[i].Name == roleName {
+			return &c.roles[i], nil
+		}
+	}
+	return nil, notFound("Could not find role")
+}
+
+func (r *realm) roleByName(roleName string) (*keycloak.RoleRepresentation, error) {
+	for i := range r.roles {
+		if *r.roles[i].Name == roleName {
+			return &r.roles[i], nil
+		}
+	}
+	return nil, notFound("Could not find role")
+}
+
+// updateRole applies the changes Keycloak allows on a role: its name, which must stay unique, and its description.
+func (r *realm) updateRole(existing *keycloak.RoleRepresentation, role keycloak.RoleRepresentation) error {
+	if role.Name != nil && *role.Name != *existing.Name {
+		var siblings = r.roles
+		if *existing.ClientRole {
+			var c, err = r.client(*existing.ContainerID)
+			if err != nil {
+				return err
+			}
+			siblings = c.roles
+		}
+		for _, sibling := range siblings {
+			if *sibling.Name == *role.Name {
+				return conflict(fmt.Sprintf("Role with name %s already exists", *role.Name))
+			}
+		}
+		existing.Name = strPtr(*role.Name)
+	}
+	if role.Description != nil {
+		existing.Description = strPtr(*role.Description)
+	}
+	return nil
+}
+
+// deleteRole removes the role from its container, the composites and the role mappings.
+func (r *realm) deleteRole(roleID string) {
+	var without = func(roles []keycloak.RoleRepresentation) []keycloak.RoleRepresentation {
+		var res []keycloak.RoleRepresentation
+		for _, role := range roles {
+			if *role.ID != roleID {
+				res = append(res, role)
+			}
+		}
+		return res
+	}
+	r.roles = without(r.roles)
+	for _, c := range r.clients {
+		c.roles = without(c.roles)
+	}
+
+	delete(r.composites, roleID)
+	for id := range r.composites {
+		r.composites[id] = remove(r.composites[id], roleID)
+	}
+	for _, u := range r.users {
+		u.realmRoles = remove(u.realmRoles, roleID)
+		for idClient := range u.clientRoles {
+			u.clientRoles[idClient] = remove(u.clientRoles[idClient], roleID)
+		}
+	}
+	for _, g := range r.groups {
+		g.realmRoles = remove(g.realmRoles, roleID)
+		for idClient := range g.clientRoles {
+			g.clientRoles[idClient] = remove(g.clientRoles[idClient], roleID)
+		}
+	}
+	for _, s := range r.clientScopes {
+		s.realmRoles = remove(s.realmRoles, roleID)
+		for idClient := range s.clientRoles {
+			s.clientRoles[idClient] = remove(s.clientRoles[idClient], roleID)
+		}
+	}
+}
+
+func (r *realm) roleUsers(roleID string, query keycloak.PageQuery) []keycloak.UserRepresentation {
+	var matches []*user
+	for _, u := range r.users {
+		if contains(u.realmRoles, roleID) || contains(u.clientRoles[roleContainer(r, roleID)], roleID) {
+			matches = append(matches, u)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return *matches[i].rep.Username < *matches[j].rep.Username })
+
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.UserRepresentation{}
+	for _, u := range matches[first:last] {
+		res = append(res, u.representation())
+	}
+	return res
+}
+
+func (r *realm) roleGroups(roleID string, query keycloak.PageQuery) []keycloak.GroupRepresentation {
+	var matches []*group
+	for _, g := range r.groups {
+		if contains(g.realmRoles, roleID) || contains(g.clientRoles[roleContainer(r, roleID)], roleID) {
+			matches = append(matches, g)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return *matches[i].rep.Path < *matches[j].rep.Path })
+
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.GroupRepresentation{}
+	for _, g := range matches[first:last] {
+		var rep keycloak.GroupRepresentation
+		deepCopy(g.rep, &rep)
+		rep.SubGroups = nil
+		res = append(res, rep)
+	}
+	return res
+}
+
+// roleContainer returns the ID of the client owning the role, or "" for a realm role.
+func roleContainer(r *realm, roleID string) string {
+	var role, err = r.role(roleID)
+	if err != nil || !*role.ClientRole {
+		return ""
+	}
+	return *role.ContainerID
+}
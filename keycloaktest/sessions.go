@@ -0,0 +1,197 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// AddSession opens a session for the user with the given clients, as if the user had logged in to them, and
+// returns its ID. An offline session is only returned by the offline session listings and survives LogoutAll.
+func (f *Fake) AddSession(realmName string, userID string, offline bool, idClients ...string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return "", err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return "", err
+	}
+	var clients = map[string]interface{}{}
+	for _, idClient := range idClients {
+		var c, err = r.client(idClient)
+		if err != nil {
+			return "", err
+		}
+		clients[idClient] = *c.rep.ClientID
+	}
+
+	var now = nowMillis()
+	var s = &session{offline: offline, rep: keycloak.UserSessionRepresentation{
+		ID:         strPtr(newID()),
+		UserID:     u.rep.ID,
+		Username:   u.rep.Username,
+		IPAddress:  strPtr("127.0.0.1"),
+		Start:      &now,
+		LastAccess: &now,
+		Clients:    &clients,
+	}}
+	r.sessions = append(r.sessions, s)
+	return *s.rep.ID, nil
+}
+
+// GetUserSessions returns the online sessions of the user.
+func (f *Fake) GetUserSessions(accessToken string, realmName, userID string) ([]keycloak.UserSessionRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.user(userID); err != nil {
+		return nil, err
+	}
+	return r.findSessions(false, func(s *session) bool { return *s.rep.UserID == userID }), nil
+}
+
+// GetUserOfflineSessions returns the offline sessions of the user for the client.
+func (f *Fake) GetUserOfflineSessions(accessToken string, realmName, userID, idClient string) ([]keycloak.UserSessionRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.user(userID); err != nil {
+		return nil, err
+	}
+	if _, err = r.client(idClient); err != nil {
+		return nil, err
+	}
+	return r.findSessions(true, func(s *session) bool { return *s.rep.UserID == userID && s.hasClient(idClient) }), nil
+}
+
+// LogoutUser removes all the sessions of the user, including the offline ones.
+func (f *Fake) LogoutUser(accessToken string, realmName, userID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if _, err = r.user(userID); err != nil {
+		return err
+	}
+	r.removeSessions(func(s *session) bool { return *s.rep.UserID == userID })
+	return nil
+}
+
+// DeleteSession removes a session.
+func (f *Fake) DeleteSession(accessToken string, realmName, sessionID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if r.removeSessions(func(s *session) bool { return *s.rep.ID == sessionID }) == 0 {
+		return notFound("Session not found")
+	}
+	return nil
+}
+
+// LogoutAll removes the online sessions of the realm. No client is notified.
+func (f *Fake) LogoutAll(accessToken string, realmName string) (keycloak.GlobalRequestResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return keycloak.GlobalRequestResult{}, err
+	}
+	r.removeSessions(func(s *session) bool { return !s.offline })
+	return keycloak.GlobalRequestResult{SuccessRequests: &[]string{}, FailedRequests: &[]string{}}, nil
+}
+
+// GetClientSessions returns the online sessions of the client.
+func (f *Fake) GetClientSessions(accessToken string, realmName, idClient string, query keycloak.PageQuery) ([]keycloak.UserSessionRepresentation, error) {
+	return f.pageClientSessions(realmName, idClient, false, query)
+}
+
+// GetClientOfflineSessions returns the offline sessions of the client.
+func (f *Fake) GetClientOfflineSessions(accessToken string, realmName, idClient string, query keycloak.PageQuery) ([]keycloak.UserSessionRepresentation, error) {
+	return f.pageClientSessions(realmName, idClient, true, query)
+}
+
+// GetClientSessionCount returns the number of online sessions of the client.
+func (f *Fake) GetClientSessionCount(accessToken string, realmName, idClient string) (int, error) {
+	var sessions, err = f.clientSessions(realmName, idClient, false)
+	return len(sessions), err
+}
+
+// GetClientOfflineSessionCount returns the number of offline sessions of the client.
+func (f *Fake) GetClientOfflineSessionCount(accessToken string, realmName, idClient string) (int, error) {
+	var sessions, err = f.clientSessions(realmName, idClient, true)
+	return len(sessions), err
+}
+
+func (f *Fake) pageClientSessions(realmName, idClient string, offline bool, query keycloak.PageQuery) ([]keycloak.UserSessionRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	var sessions, err = f.clientSessions(realmName, idClient, offline)
+	if err != nil {
+		return nil, err
+	}
+	var first, last = page(query.First, query.Max, len(sessions))
+	return sessions[first:last], nil
+}
+
+func (f *Fake) clientSessions(realmName, idClient string, offline bool) ([]keycloak.UserSessionRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.client(idClient); err != nil {
+		return nil, err
+	}
+	return r.findSessions(offline, func(s *session) bool { return s.hasClient(idClient) }), nil
+}
+
+func (r *realm) findSessions(offline bool, match func(*session) bool) []keycloak.UserSessionRepresentation {
+	var res = []keycloak.UserSessionRepresentation{}
+	for _, s := range r.sessions {
+		if s.offline == offline && match(s) {
+			var rep keycloak.UserSessionRepresentation
+			deepCopy(s.rep, &rep)
+			res = append(res, rep)
+		}
+	}
+	return res
+}
+
+// removeSessions removes the sessions matching and returns how many were removed.
+func (r *realm) removeSessions(match func(*session) bool) int {
+	var kept []*session
+	for _, s := range r.sessions {
+		if !match(s) {
+			kept = append(kept, s)
+		}
+	}
+	var removed = len(r.sessions) - len(kept)
+	r.sessions = kept
+	return removed
+}
+
+func (s *session) hasClient(idClient string) bool {
+	var _, ok = (*s.rep.Clients)[idClient]
+	return ok
+}
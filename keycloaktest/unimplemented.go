@@ -0,0 +1,135 @@
+package keycloaktest
+
+import (
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetAuthenticatorProviders is not implemented.
+func (f *Fake) GetAuthenticatorProviders(accessToken string, realmName string) ([]map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetClientAuthenticatorProviders is not implemented.
+func (f *Fake) GetClientAuthenticatorProviders(accessToken string, realmName string) ([]map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetAuthenticatorProviderConfig is not implemented.
+func (f *Fake) GetAuthenticatorProviderConfig(accessToken string, realmName, providerID string) (keycloak.AuthenticatorConfigInfoRepresentation, error) {
+	return keycloak.AuthenticatorConfigInfoRepresentation{}, ErrNotImplemented
+}
+
+// CopyExistingAuthenticationFlow is not implemented.
+func (f *Fake) CopyExistingAuthenticationFlow(accessToken string, realmName, flowAlias, newName string) error {
+	return ErrNotImplemented
+}
+
+// GetFormActionProviders is not implemented.
+func (f *Fake) GetFormActionProviders(accessToken string, realmName string) ([]map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetFormProviders is not implemented.
+func (f *Fake) GetFormProviders(accessToken string, realmName string) ([]map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetConfigDescriptionForClients is not implemented.
+func (f *Fake) GetConfigDescriptionForClients(accessToken string, realmName string) (map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetUnregisteredRequiredActions is not implemented.
+func (f *Fake) GetUnregisteredRequiredActions(accessToken string, realmName string) ([]map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// ClearAllLoginFailures is not implemented.
+func (f *Fake) ClearAllLoginFailures(accessToken string, realmName string) error {
+	return ErrNotImplemented
+}
+
+// GetAttackDetectionStatus is not implemented.
+func (f *Fake) GetAttackDetectionStatus(accessToken string, realmName, userID string) (map[string]interface{}, error) {
+	return nil, ErrNotImplemented
+}
+
+// ClearUserLoginFailures is not implemented.
+func (f *Fake) ClearUserLoginFailures(accessToken string, realmName, userID string) error {
+	return ErrNotImplemented
+}
+
+// GetKeyInfo is not implemented.
+func (f *Fake) GetKeyInfo(accessToken string, realmName, idClient, attr string) (keycloak.CertificateRepresentation, error) {
+	return keycloak.CertificateRepresentation{}, ErrNotImplemented
+}
+
+// GetKeyStore is not implemented.
+func (f *Fake) GetKeyStore(accessToken string, realmName, idClient, attr string, keyStoreConfig keycloak.KeyStoreConfig) ([]byte, error) {
+	return nil, ErrNotImplemented
+}
+
+// GenerateCertificate is not implemented.
+func (f *Fake) GenerateCertificate(accessToken string, realmName, idClient, attr string) (keycloak.CertificateRepresentation, error) {
+	return keycloak.CertificateRepresentation{}, ErrNotImplemented
+}
+
+// GenerateKeyPairAndCertificate is not implemented.
+func (f *Fake) GenerateKeyPairAndCertificate(accessToken string, realmName, idClient, attr string, keyStoreConfig keycloak.KeyStoreConfig) ([]byte, error) {
+	return nil, ErrNotImplemented
+}
+
+// UploadCertificatePrivateKey is not implemented.
+func (f *Fake) UploadCertificatePrivateKey(accessToken string, realmName, idClient, attr string, file []byte) (keycloak.CertificateRepresentation, error) {
+	return keycloak.CertificateRepresentation{}, ErrNotImplemented
+}
+
+// UploadCertificate is not implemented.
+func (f *Fake) UploadCertificate(accessToken string, realmName, idClient, attr string, file []byte) (keycloak.CertificateRepresentation, error) {
+	return keycloak.CertificateRepresentation{}, ErrNotImplemented
+}
+
+// CreateClientInitialAccess is not implemented.
+func (f *Fake) CreateClientInitialAccess(accessToken string, realmName string, access keycloak.ClientInitialAccessCreatePresentation) (keycloak.ClientInitialAccessPresentation, error) {
+	return keycloak.ClientInitialAccessPresentation{}, ErrNotImplemented
+}
+
+// GetClientInitialAccess is not implemented.
+func (f *Fake) GetClientInitialAccess(accessToken string, realmName string) ([]keycloak.ClientInitialAccessPresentation, error) {
+	return nil, ErrNotImplemented
+}
+
+// DeleteClientInitialAccess is not implemented.
+func (f *Fake) DeleteClientInitialAccess(accessToken string, realmName, accessID string) error {
+	return ErrNotImplemented
+}
+
+// GetClientRegistrationPolicy is not implemented.
+func (f *Fake) GetClientRegistrationPolicy(accessToken string, realmName, configID string) ([]keycloak.ComponentTypeRepresentation, error) {
+	return nil, ErrNotImplemented
+}
+
+// TriggerFullSync is not implemented.
+func (f *Fake) TriggerFullSync(accessToken string, realmName, providerID string) (keycloak.SynchronizationResult, error) {
+	return keycloak.SynchronizationResult{}, ErrNotImplemented
+}
+
+// TriggerChangedUsersSync is not implemented.
+func (f *Fake) TriggerChangedUsersSync(accessToken string, realmName, providerID string) (keycloak.SynchronizationResult, error) {
+	return keycloak.SynchronizationResult{}, ErrNotImplemented
+}
+
+// SyncUserStorageMapper is not implemented.
+func (f *Fake) SyncUserStorageMapper(accessToken string, realmName, providerID, mapperID, direction string) (keycloak.SynchronizationResult, error) {
+	return keycloak.SynchronizationResult{}, ErrNotImplemented
+}
+
+// TestLDAPConnection is not implemented.
+func (f *Fake) TestLDAPConnection(accessToken string, realmName string, test keycloak.LDAPConnectionTest) error {
+	return ErrNotImplemented
+}
+
+// EvaluatePolicies is not implemented.
+func (f *Fake) EvaluatePolicies(accessToken string, realmName, idClient string, request keycloak.PolicyEvaluationRequest) (keycloak.PolicyEvaluationResponse, error) {
+	return keycloak.PolicyEvaluationResponse{}, ErrNotImplemented
+}
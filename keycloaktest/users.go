@@ -0,0 +1,338 @@
+package keycloaktest
+
+import (
+	"sort"
+	"strings"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// GetUsers returns the users of the realm, sorted by username. It supports all the fields of the query but
+// BriefRepresentation and IdpAlias.
+func (f *Fake) GetUsers(accessToken string, targetRealmName string, query keycloak.UserQuery) ([]keycloak.UserRepresentation, error) {
+	var err = query.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	r, err := f.realm(targetRealmName)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*user
+	for _, u := range r.users {
+		if u.matches(query) {
+			matches = append(matches, u)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return *matches[i].rep.Username < *matches[j].rep.Username })
+
+	var first, last = page(query.First, query.Max, len(matches))
+	var res = []keycloak.UserRepresentation{}
+	for _, u := range matches[first:last] {
+		res = append(res, u.representation())
+	}
+	return res, nil
+}
+
+func (u *user) matches(query keycloak.UserQuery) bool {
+	if query.Enabled != nil && (u.rep.Enabled == nil || *u.rep.Enabled != *query.Enabled) {
+		return false
+	}
+	if query.Search != "" {
+		var search = strings.Trim(query.Search, "*")
+		return search == "" || containsFold(u.rep.Username, search) || containsFold(u.rep.Email, search) ||
+			containsFold(u.rep.FirstName, search) || containsFold(u.rep.LastName, search)
+	}
+
+	var filters = []struct {
+		filter string
+		value  *string
+	}{
+		{query.Username, u.rep.Username},
+		{query.Email, u.rep.Email},
+		{query.FirstName, u.rep.FirstName},
+		{query.LastName, u.rep.LastName},
+	}
+	for _, f := range filters {
+		if f.filter == "" {
+			continue
+		}
+		if query.Exact && (f.value == nil || !strings.EqualFold(*f.value, f.filter)) {
+			return false
+		}
+		if !query.Exact && !containsFold(f.value, f.filter) {
+			return false
+		}
+	}
+	return true
+}
+
+func (u *user) representation() keycloak.UserRepresentation {
+	var rep keycloak.UserRepresentation
+	deepCopy(u.rep, &rep)
+	return rep
+}
+
+// CreateUser creates the user. Usernames are lower-cased and must be unique, as must be emails unless the
+// realm allows duplicate emails.
+func (f *Fake) CreateUser(accessToken string, targetRealmName string, userRep keycloak.UserRepresentation) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(targetRealmName)
+	if err != nil {
+		return "", err
+	}
+	return r.createUser(targetRealmName, userRep)
+}
+
+func (r *realm) createUser(realmName string, userRep keycloak.UserRepresentation) (string, error) {
+	var u = &user{clientRoles: map[string][]string{}}
+	deepCopy(userRep, &u.rep)
+	if u.rep.Username == nil || *u.rep.Username == "" {
+		return "", badRequest("User name is missing")
+	}
+	u.rep.Username = strPtr(strings.ToLower(*u.rep.Username))
+	if err := r.checkUserUniqueness(u.rep, ""); err != nil {
+		return "", err
+	}
+
+	u.rep.ID = strPtr(newID())
+	u.rep.CreatedTimestamp = int64Ptr(nowMillis())
+	if u.rep.Enabled == nil {
+		u.rep.Enabled = boolPtr(false)
+	}
+	if u.rep.EmailVerified == nil {
+		u.rep.EmailVerified = boolPtr(false)
+	}
+	if u.rep.Credentials != nil {
+		for _, cred := range *u.rep.Credentials {
+			u.setCredential(cred)
+		}
+	}
+	if u.rep.Groups != nil {
+		for _, path := range *u.rep.Groups {
+			for _, g := range r.groups {
+				if g.rep.Path != nil && *g.rep.Path == path {
+					u.groupIDs = append(u.groupIDs, *g.rep.ID)
+				}
+			}
+		}
+	}
+	u.rep.Credentials, u.rep.Groups, u.rep.RealmRoles, u.rep.ClientRoles = nil, nil, nil, nil
+
+	r.users = append(r.users, u)
+	return location(realmName, "users", *u.rep.ID), nil
+}
+
+func (r *realm) checkUserUniqueness(userRep keycloak.UserRepresentation, userID string) error {
+	var duplicateEmailsAllowed = r.rep.DuplicateEmailsAllowed != nil && *r.rep.DuplicateEmailsAllowed
+	for _, other := range r.users {
+		if *other.rep.ID == userID {
+			continue
+		}
+		if userRep.Username != nil && strings.EqualFold(*other.rep.Username, *userRep.Username) {
+			return conflict("User exists with same username")
+		}
+		if !duplicateEmailsAllowed && userRep.Email != nil && *userRep.Email != "" && other.rep.Email != nil && strings.EqualFold(*other.rep.Email, *userRep.Email) {
+			return conflict("User exists with same email")
+		}
+	}
+	return nil
+}
+
+// CountUsers returns the number of users in the realm.
+func (f *Fake) CountUsers(accessToken string, realmName string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return 0, err
+	}
+	return len(r.users), nil
+}
+
+// GetUser returns the user.
+func (f *Fake) GetUser(accessToken string, realmName, userID string) (keycloak.UserRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return keycloak.UserRepresentation{}, err
+	}
+	return u.representation(), nil
+}
+
+func (f *Fake) user(realmName, userID string) (*user, error) {
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	return r.user(userID)
+}
+
+// GetGroupsOfUser returns the groups the user is member of.
+func (f *Fake) GetGroupsOfUser(accessToken string, realmName, userID string) ([]keycloak.GroupRepresentation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res = []keycloak.GroupRepresentation{}
+	for _, g := range r.groups {
+		if contains(u.groupIDs, *g.rep.ID) {
+			res = append(res, g.representation())
+		}
+	}
+	return res, nil
+}
+
+// AddGroupToUser makes the user member of the group.
+func (f *Fake) AddGroupToUser(accessToken string, realmName, userID, groupID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+	if _, err = r.group(groupID); err != nil {
+		return err
+	}
+	if !contains(u.groupIDs, groupID) {
+		u.groupIDs = append(u.groupIDs, groupID)
+	}
+	return nil
+}
+
+// DeleteGroupFromUser removes the user from the group.
+func (f *Fake) DeleteGroupFromUser(accessToken string, realmName, userID, groupID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+	if _, err = r.group(groupID); err != nil {
+		return err
+	}
+	u.groupIDs = remove(u.groupIDs, groupID)
+	return nil
+}
+
+// UpdateUser updates the non nil fields of the user.
+func (f *Fake) UpdateUser(accessToken string, realmName, userID string, userRep keycloak.UserRepresentation) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	u, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	if userRep.Username != nil {
+		userRep.Username = strPtr(strings.ToLower(*userRep.Username))
+	}
+	if err = r.checkUserUniqueness(userRep, userID); err != nil {
+		return err
+	}
+	userRep.ID, userRep.CreatedTimestamp, userRep.Credentials, userRep.Groups = nil, nil, nil, nil
+	merge(&u.rep, userRep)
+	return nil
+}
+
+// DeleteUser deletes the user.
+func (f *Fake) DeleteUser(accessToken string, realmName, userID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var r, err = f.realm(realmName)
+	if err != nil {
+		return err
+	}
+	if _, err = r.user(userID); err != nil {
+		return err
+	}
+	r.deleteUser(userID)
+	return nil
+}
+
+func (r *realm) deleteUser(userID string) {
+	for i, u := range r.users {
+		if *u.rep.ID == userID {
+			r.users = append(r.users[:i], r.users[i+1:]...)
+			break
+		}
+	}
+	r.removeSessions(func(s *session) bool { return *s.rep.UserID == userID })
+}
+
+// ExecuteActionsEmail checks that the user exists, no email is sent.
+func (f *Fake) ExecuteActionsEmail(accessToken string, realmName string, userID string, actions []string, options keycloak.ActionsEmailOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var u, err = f.user(realmName, userID)
+	if err != nil {
+		return err
+	}
+	if u.rep.Email == nil || *u.rep.Email == "" {
+		return badRequest("User email missing")
+	}
+	return nil
+}
+
+// SendSmsCode is not implemented.
+func (f *Fake) SendSmsCode(accessToken string, realmName string, userID string) (keycloak.SmsCodeRepresentation, error) {
+	return keycloak.SmsCodeRepresentation{}, ErrNotImplemented
+}
+
+// SendReminderEmail is not implemented.
+func (f *Fake) SendReminderEmail(accessToken string, realmName string, userID string, options keycloak.ActionsEmailOptions) error {
+	return ErrNotImplemented
+}
+
+// LinkShadowUser is not implemented.
+func (f *Fake) LinkShadowUser(accessToken string, reqRealmName string, userID string, provider string, fedIDKC keycloak.FederatedIdentityRepresentation) error {
+	return ErrNotImplemented
+}
+
+// SendSMS is not implemented.
+func (f *Fake) SendSMS(accessToken string, realmName string, smsRep keycloak.SMSRepresentation) error {
+	return ErrNotImplemented
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
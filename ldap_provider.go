@@ -0,0 +1,228 @@
+package keycloak
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider type of the user storage components, such as the LDAP and Kerberos providers.
+const UserStorageProviderType = "org.keycloak.storage.UserStorageProvider"
+
+// Vendors of the LDAP servers.
+const (
+	LDAPVendorActiveDirectory = "ad"
+	LDAPVendorRedHatDS        = "rhds"
+	LDAPVendorTivoli          = "tivoli"
+	LDAPVendorEDirectory      = "edirectory"
+	LDAPVendorOther           = "other"
+)
+
+// Edit modes of the LDAP providers.
+const (
+	LDAPEditModeReadOnly = "READ_ONLY"
+	LDAPEditModeWritable = "WRITABLE"
+	LDAPEditModeUnsynced = "UNSYNCED"
+)
+
+// LDAPProvider describes an LDAP user storage provider. Component returns the component to create or update with
+// the components methods. The zero values of the attributes fall back to the defaults of the vendor, and those of
+// the periods disable the periodic synchronisations.
+type LDAPProvider struct {
+	Name     string
+	Priority int
+	Disabled bool
+	Vendor   string
+	EditMode string
+
+	ConnectionURL     string
+	BindDN            string
+	BindCredential    string
+	StartTLS          bool
+	UseTruststoreSPI  string
+	ConnectionTimeout time.Duration
+	Pagination        bool
+
+	UsersDN                string
+	UsernameAttribute      string
+	RDNAttribute           string
+	UUIDAttribute          string
+	UserObjectClasses      []string
+	CustomUserSearchFilter string
+	// SubtreeSearch searches the users in the whole subtree of UsersDN instead of its direct children.
+	SubtreeSearch bool
+
+	// DisableImport keeps the users in LDAP instead of importing them into Keycloak.
+	DisableImport     bool
+	SyncRegistrations bool
+	TrustEmail        bool
+	BatchSize         int
+	FullSyncPeriod    time.Duration
+	ChangedSyncPeriod time.Duration
+
+	// Kerberos enables the SPNEGO authentication of the LDAP users.
+	Kerberos *KerberosSettings
+}
+
+// KerberosSettings configures the Kerberos authentication of the users of a provider.
+type KerberosSettings struct {
+	KerberosRealm   string
+	ServerPrincipal string
+	KeyTab          string
+	Debug           bool
+	// UseForPasswordAuthentication checks the passwords against Kerberos rather than LDAP.
+	UseForPasswordAuthentication bool
+}
+
+// KerberosProvider describes a Kerberos user storage provider, for the users who are not in LDAP.
+type KerberosProvider struct {
+	Name     string
+	Priority int
+	Disabled bool
+	KerberosSettings
+	// AllowPasswordAuthentication checks the passwords against Kerberos too.
+	AllowPasswordAuthentication bool
+	// EditMode is LDAPEditModeUnsynced to let the users change their password in Keycloak, or else empty.
+	EditMode string
+	// UpdateProfileFirstLogin asks the users to complete their profile on their first login.
+	UpdateProfileFirstLogin bool
+}
+
+// ldapVendorDefaults are the attributes which fit the schema of each vendor.
+var ldapVendorDefaults = map[string]struct {
+	username, rdn, uuid string
+	objectClasses       []string
+}{
+	LDAPVendorActiveDirectory: {"cn", "cn", "objectGUID", []string{"person", "organizationalPerson", "user"}},
+	LDAPVendorRedHatDS:        {"uid", "uid", "nsuniqueid", []string{"inetOrgPerson", "organizationalPerson"}},
+	LDAPVendorTivoli:          {"uid", "uid", "uniqueidentifier", []string{"inetOrgPerson", "organizationalPerson"}},
+	LDAPVendorEDirectory:      {"uid", "uid", "guid", []string{"inetOrgPerson", "organizationalPerson"}},
+	LDAPVendorOther:           {"uid", "uid", "entryUUID", []string{"inetOrgPerson", "organizationalPerson"}},
+}
+
+// Component returns the component of the provider. Its parent is left empty, which Keycloak sets to the realm.
+func (p LDAPProvider) Component() ComponentRepresentation {
+	var vendor = p.Vendor
+	if _, ok := ldapVendorDefaults[vendor]; !ok {
+		vendor = LDAPVendorOther
+	}
+	var defaults = ldapVendorDefaults[vendor]
+	var objectClasses = p.UserObjectClasses
+	if len(objectClasses) == 0 {
+		objectClasses = defaults.objectClasses
+	}
+	var editMode = p.EditMode
+	if editMode == "" {
+		editMode = LDAPEditModeReadOnly
+	}
+	var searchScope = "1"
+	if p.SubtreeSearch {
+		searchScope = "2"
+	}
+	var authType = "simple"
+	if p.BindDN == "" {
+		authType = "none"
+	}
+
+	var config = componentConfig{}
+	config.set("enabled", strconv.FormatBool(!p.Disabled))
+	config.set("priority", strconv.Itoa(p.Priority))
+	config.set("vendor", vendor)
+	config.set("editMode", editMode)
+	config.set("connectionUrl", p.ConnectionURL)
+	config.set("authType", authType)
+	config.set("bindDn", p.BindDN)
+	config.set("bindCredential", p.BindCredential)
+	config.set("startTls", strconv.FormatBool(p.StartTLS))
+	config.set("useTruststoreSpi", orDefault(p.UseTruststoreSPI, "ldapsOnly"))
+	config.setDuration("connectionTimeout", p.ConnectionTimeout)
+	config.set("pagination", strconv.FormatBool(p.Pagination))
+	config.set("usersDn", p.UsersDN)
+	config.set("usernameLDAPAttribute", orDefault(p.UsernameAttribute, defaults.username))
+	config.set("rdnLDAPAttribute", orDefault(p.RDNAttribute, defaults.rdn))
+	config.set("uuidLDAPAttribute", orDefault(p.UUIDAttribute, defaults.uuid))
+	config.set("userObjectClasses", strings.Join(objectClasses, ", "))
+	config.set("customUserSearchFilter", p.CustomUserSearchFilter)
+	config.set("searchScope", searchScope)
+	config.set("importEnabled", strconv.FormatBool(!p.DisableImport))
+	config.set("syncRegistrations", strconv.FormatBool(p.SyncRegistrations))
+	config.set("trustEmail", strconv.FormatBool(p.TrustEmail))
+	config.set("batchSizeForSync", strconv.Itoa(orDefaultInt(p.BatchSize, 1000)))
+	config.setPeriod("fullSyncPeriod", p.FullSyncPeriod)
+	config.setPeriod("changedSyncPeriod", p.ChangedSyncPeriod)
+	config.set("allowKerberosAuthentication", strconv.FormatBool(p.Kerberos != nil))
+	if p.Kerberos != nil {
+		p.Kerberos.setConfig(config)
+		config.set("useKerberosForPasswordAuthentication", strconv.FormatBool(p.Kerberos.UseForPasswordAuthentication))
+	}
+	return newUserStorageComponent(p.Name, "ldap", config)
+}
+
+// Component returns the component of the provider. Its parent is left empty, which Keycloak sets to the realm.
+func (p KerberosProvider) Component() ComponentRepresentation {
+	var config = componentConfig{}
+	config.set("enabled", strconv.FormatBool(!p.Disabled))
+	config.set("priority", strconv.Itoa(p.Priority))
+	p.KerberosSettings.setConfig(config)
+	config.set("allowPasswordAuthentication", strconv.FormatBool(p.AllowPasswordAuthentication))
+	config.set("editMode", p.EditMode)
+	config.set("updateProfileFirstLogin", strconv.FormatBool(p.UpdateProfileFirstLogin))
+	return newUserStorageComponent(p.Name, "kerberos", config)
+}
+
+func (s KerberosSettings) setConfig(config componentConfig) {
+	config.set("kerberosRealm", s.KerberosRealm)
+	config.set("serverPrincipal", s.ServerPrincipal)
+	config.set("keyTab", s.KeyTab)
+	config.set("debug", strconv.FormatBool(s.Debug))
+}
+
+func newUserStorageComponent(name string, providerID string, config componentConfig) ComponentRepresentation {
+	var providerType = UserStorageProviderType
+	var multivalued = MultivaluedHashMap(config)
+	return ComponentRepresentation{
+		Name:         &name,
+		ProviderID:   &providerID,
+		ProviderType: &providerType,
+		Config:       &multivalued,
+	}
+}
+
+// componentConfig is the configuration of a component, whose values are all single.
+type componentConfig map[string][]string
+
+// set sets the key unless value is empty.
+func (c componentConfig) set(key string, value string) {
+	if value != "" {
+		c[key] = []string{value}
+	}
+}
+
+func (c componentConfig) setDuration(key string, value time.Duration) {
+	if value > 0 {
+		c.set(key, strconv.FormatInt(int64(value/time.Millisecond), 10))
+	}
+}
+
+// setPeriod sets a period in seconds, -1 meaning disabled.
+func (c componentConfig) setPeriod(key string, value time.Duration) {
+	var seconds = int64(-1)
+	if value > 0 {
+		seconds = int64(value / time.Second)
+	}
+	c.set(key, strconv.FormatInt(seconds, 10))
+}
+
+func orDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func orDefaultInt(value int, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
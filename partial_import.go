@@ -0,0 +1,116 @@
+package keycloak
+
+import (
+	"gopkg.in/h2non/gentleman.v2/plugin"
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	partialImportPath = realmPath + "/partialImport"
+	partialExportPath = realmPath + "/partial-export"
+)
+
+// IfResourceExists is the policy of PartialImport for the resources which already exist in the realm.
+type IfResourceExists string
+
+// Policies of PartialImport. The empty value is IfResourceExistsFail.
+const (
+	// IfResourceExistsFail aborts the whole import, which then imports nothing.
+	IfResourceExistsFail IfResourceExists = "FAIL"
+	// IfResourceExistsSkip keeps the existing resources unchanged.
+	IfResourceExistsSkip IfResourceExists = "SKIP"
+	// IfResourceExistsOverwrite replaces the existing resources with the imported ones.
+	IfResourceExistsOverwrite IfResourceExists = "OVERWRITE"
+)
+
+// Actions taken by PartialImport.
+const (
+	PartialImportActionAdded       = "ADDED"
+	PartialImportActionSkipped     = "SKIPPED"
+	PartialImportActionOverwritten = "OVERWRITTEN"
+)
+
+// Types of the resources imported by PartialImport.
+const (
+	PartialImportResourceUser       = "USER"
+	PartialImportResourceGroup      = "GROUP"
+	PartialImportResourceClient     = "CLIENT"
+	PartialImportResourceIdp        = "IDP"
+	PartialImportResourceRealmRole  = "REALM_ROLE"
+	PartialImportResourceClientRole = "CLIENT_ROLE"
+)
+
+// PartialImportResults is the outcome of PartialImport: the counts of the resources by action, and the action
+// taken on each resource.
+type PartialImportResults struct {
+	Added       int                   `json:"added"`
+	Skipped     int                   `json:"skipped"`
+	Overwritten int                   `json:"overwritten"`
+	Results     []PartialImportResult `json:"results"`
+}
+
+// PartialImportResult is the action taken by PartialImport on a resource. ResourceName is the name of the
+// resource, e.g. the username of a user or the client-id of a client.
+type PartialImportResult struct {
+	Action       string `json:"action"`
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	ID           string `json:"id"`
+}
+
+// WithAction returns the results of the resources on which action, one of the PartialImportAction constants,
+// was taken.
+func (r PartialImportResults) WithAction(action string) []PartialImportResult {
+	var res []PartialImportResult
+	for _, result := range r.Results {
+		if result.Action == action {
+			res = append(res, result)
+		}
+	}
+	return res
+}
+
+// Validate checks that the policy of the import is known.
+func (r PartialImportRepresentation) Validate() error {
+	switch r.IfResourceExists {
+	case "", IfResourceExistsFail, IfResourceExistsSkip, IfResourceExistsOverwrite:
+		return nil
+	default:
+		return newError(MsgErrInvalidParam, InvalidIfResourceExists, nil)
+	}
+}
+
+// PartialExportOptions selects the content exported by PartialExport along with the realm settings.
+type PartialExportOptions struct {
+	GroupsAndRoles bool
+	Clients        bool
+}
+
+func (o PartialExportOptions) plugins() []plugin.Plugin {
+	var p queryParams
+	p.addBool("exportGroupsAndRoles", o.GroupsAndRoles)
+	p.addBool("exportClients", o.Clients)
+	return p
+}
+
+// PartialImport imports users, groups, clients, roles and identity providers into an existing realm. The
+// resources which already exist are handled according to IfResourceExists.
+func (c *Client) PartialImport(accessToken string, realmName string, rep PartialImportRepresentation) (PartialImportResults, error) {
+	if err := rep.Validate(); err != nil {
+		return PartialImportResults{}, err
+	}
+
+	var resp = PartialImportResults{}
+	var _, err = c.post(accessToken, &resp, url.Path(partialImportPath), url.Param("realm", realmName), body.JSON(rep))
+	return resp, err
+}
+
+// PartialExport exports the settings of the realm and, according to the options, its groups, roles and clients.
+// The users are never exported, and the secrets are masked.
+func (c *Client) PartialExport(accessToken string, realmName string, options PartialExportOptions) (RealmRepresentation, error) {
+	var resp = RealmRepresentation{}
+	var plugins = append(options.plugins(), url.Path(partialExportPath), url.Param("realm", realmName))
+	var _, err = c.post(accessToken, &resp, plugins...)
+	return resp, err
+}
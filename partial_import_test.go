@@ -0,0 +1,63 @@
+package keycloak
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartialImport(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var content, _ = ioutil.ReadAll(r.Body)
+		assert.Equal(t, "/auth/admin/realms/test/partialImport", r.URL.Path)
+		assert.JSONEq(t, `{"ifResourceExists":"SKIP","users":[{"username":"john"}]}`, string(content))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"overwritten":0,"added":0,"skipped":1,"results":[{"action":"SKIPPED","resourceType":"USER","resourceName":"john","id":"u1"}]}`))
+	}))
+
+	var username = "john"
+	var results, err = client.PartialImport("token", "test", PartialImportRepresentation{
+		IfResourceExists: IfResourceExistsSkip,
+		Users:            &[]UserRepresentation{{Username: &username}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, results.Skipped)
+	assert.Equal(t, []PartialImportResult{{Action: PartialImportActionSkipped, ResourceType: PartialImportResourceUser, ResourceName: "john", ID: "u1"}},
+		results.WithAction(PartialImportActionSkipped))
+	assert.Empty(t, results.WithAction(PartialImportActionAdded))
+
+	_, err = client.PartialImport("token", "test", PartialImportRepresentation{IfResourceExists: "IGNORE"})
+	assert.Equal(t, Error{Code: MsgErrInvalidParam, Detail: InvalidIfResourceExists}, err)
+}
+
+func TestPartialExport(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/auth/admin/realms/test/partial-export", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("exportClients"))
+		assert.Equal(t, "", r.URL.Query().Get("exportGroupsAndRoles"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"realm":"test","clients":[{"clientId":"app","secret":"**********"}]}`))
+	}))
+
+	var realm, err = client.PartialExport("token", "test", PartialExportOptions{Clients: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "app", *(*realm.Clients)[0].ClientID)
+	assert.Nil(t, realm.Groups)
+}
+
+func TestExportRealm(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/auth/realms/test/export/realm", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"realm":"test","users":[{"username":"john"}],"clients":[{"clientId":"app","secret":"s3cr3t"}]}`))
+	}))
+
+	var realm, err = client.ExportRealm("token", "test")
+	assert.Nil(t, err)
+	assert.Equal(t, "john", *(*realm.Users)[0].Username)
+	assert.Equal(t, "s3cr3t", *(*realm.Clients)[0].Secret)
+}
@@ -0,0 +1,107 @@
+package keycloak
+
+import "strconv"
+
+// Types of the protocol mappers built by the constructors below.
+const (
+	UserAttributeMapperType   = "oidc-usermodel-attribute-mapper"
+	HardcodedClaimMapperType  = "oidc-hardcoded-claim-mapper"
+	GroupMembershipMapperType = "oidc-group-membership-mapper"
+	AudienceMapperType        = "oidc-audience-mapper"
+	UserRealmRoleMapperType   = "oidc-usermodel-realm-role-mapper"
+)
+
+// JSON types of the claims, as labelled by Keycloak.
+const (
+	ClaimTypeString  = "String"
+	ClaimTypeLong    = "long"
+	ClaimTypeInt     = "int"
+	ClaimTypeBoolean = "boolean"
+	ClaimTypeJSON    = "JSON"
+)
+
+const openIDConnectProtocol = "openid-connect"
+
+// TokenClaims selects the tokens a protocol mapper adds its claim to.
+type TokenClaims struct {
+	IDToken     bool
+	AccessToken bool
+	UserInfo    bool
+}
+
+// AllTokens adds the claim to the ID token, the access token and the user info.
+var AllTokens = TokenClaims{IDToken: true, AccessToken: true, UserInfo: true}
+
+func (t TokenClaims) config() map[string]interface{} {
+	return map[string]interface{}{
+		"id.token.claim":       strconv.FormatBool(t.IDToken),
+		"access.token.claim":   strconv.FormatBool(t.AccessToken),
+		"userinfo.token.claim": strconv.FormatBool(t.UserInfo),
+	}
+}
+
+func newOIDCMapper(name string, mapperType string, config map[string]interface{}) ProtocolMapperRepresentation {
+	var protocol = openIDConnectProtocol
+	return ProtocolMapperRepresentation{
+		Name:           &name,
+		Protocol:       &protocol,
+		ProtocolMapper: &mapperType,
+		Config:         &config,
+	}
+}
+
+// NewUserAttributeMapper returns a mapper which adds the user attribute userAttribute as the claim claimName,
+// converted to jsonType, one of the ClaimType constants.
+func NewUserAttributeMapper(name, userAttribute, claimName, jsonType string, tokens TokenClaims) ProtocolMapperRepresentation {
+	var config = tokens.config()
+	config["user.attribute"] = userAttribute
+	config["claim.name"] = claimName
+	config["jsonType.label"] = jsonType
+	return newOIDCMapper(name, UserAttributeMapperType, config)
+}
+
+// NewHardcodedClaimMapper returns a mapper which adds the claim claimName with the value claimValue, converted to
+// jsonType, one of the ClaimType constants.
+func NewHardcodedClaimMapper(name, claimName, claimValue, jsonType string, tokens TokenClaims) ProtocolMapperRepresentation {
+	var config = tokens.config()
+	config["claim.name"] = claimName
+	config["claim.value"] = claimValue
+	config["jsonType.label"] = jsonType
+	return newOIDCMapper(name, HardcodedClaimMapperType, config)
+}
+
+// NewGroupMembershipMapper returns a mapper which adds the groups of the user as the claim claimName, by path if
+// fullPath is set and by name otherwise.
+func NewGroupMembershipMapper(name, claimName string, fullPath bool, tokens TokenClaims) ProtocolMapperRepresentation {
+	var config = tokens.config()
+	config["claim.name"] = claimName
+	config["full.path"] = strconv.FormatBool(fullPath)
+	return newOIDCMapper(name, GroupMembershipMapperType, config)
+}
+
+// NewAudienceMapper returns a mapper which adds an audience to the tokens: the client-id includedClient, or else
+// the custom audience includedCustom. One of them must be set: Keycloak accepts a mapper with neither, but it adds
+// no audience. The user info has no audience, so tokens.UserInfo is ignored.
+func NewAudienceMapper(name, includedClient, includedCustom string, tokens TokenClaims) ProtocolMapperRepresentation {
+	var config = tokens.config()
+	delete(config, "userinfo.token.claim")
+	if includedClient != "" {
+		config["included.client.audience"] = includedClient
+	} else {
+		config["included.custom.audience"] = includedCustom
+	}
+	return newOIDCMapper(name, AudienceMapperType, config)
+}
+
+// NewUserRealmRoleMapper returns a mapper which adds the realm roles of the user, prefixed by rolePrefix, as the
+// multivalued claim claimName.
+func NewUserRealmRoleMapper(name, claimName, rolePrefix string, tokens TokenClaims) ProtocolMapperRepresentation {
+	var config = tokens.config()
+	config["claim.name"] = claimName
+	config["jsonType.label"] = ClaimTypeString
+	config["multivalued"] = "true"
+	if rolePrefix != "" {
+		config["usermodel.realmRoleMapping.rolePrefix"] = rolePrefix
+	}
+	return newOIDCMapper(name, UserRealmRoleMapperType, config)
+}
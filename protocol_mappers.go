@@ -0,0 +1,57 @@
+package keycloak
+
+import (
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	clientProtocolMappersPath     = clientIDPath + "/protocol-mappers/models"
+	clientProtocolMapperIDPath    = clientProtocolMappersPath + "/:mapperId"
+	clientProtocolMappersBulkPath = clientIDPath + "/protocol-mappers/add-models"
+	clientScopeMappersBulkPath    = clientScopeIDPath + "/protocol-mappers/add-models"
+)
+
+// GetClientProtocolMappers returns the protocol mappers defined on the client itself, not those inherited from
+// its client scopes. idClient is the id of client (not client-id).
+func (c *Client) GetClientProtocolMappers(accessToken string, realmName, idClient string) ([]ProtocolMapperRepresentation, error) {
+	var resp = []ProtocolMapperRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientProtocolMappersPath), url.Param("realm", realmName), url.Param("id", idClient))
+	return resp, err
+}
+
+// GetClientProtocolMapper returns a protocol mapper of the client.
+func (c *Client) GetClientProtocolMapper(accessToken string, realmName, idClient, mapperID string) (ProtocolMapperRepresentation, error) {
+	var resp = ProtocolMapperRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientProtocolMapperIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("mapperId", mapperID))
+	return resp, err
+}
+
+// CreateClientProtocolMapper adds a protocol mapper to the client. Its name must be unique within the client.
+func (c *Client) CreateClientProtocolMapper(accessToken string, realmName, idClient string, mapper ProtocolMapperRepresentation) (string, error) {
+	return c.post(accessToken, nil, url.Path(clientProtocolMappersPath), url.Param("realm", realmName), url.Param("id", idClient), body.JSON(mapper))
+}
+
+// AddClientProtocolMappers adds several protocol mappers to the client at once. None is added if one of them is
+// rejected.
+func (c *Client) AddClientProtocolMappers(accessToken string, realmName, idClient string, mappers []ProtocolMapperRepresentation) error {
+	var _, err = c.post(accessToken, nil, url.Path(clientProtocolMappersBulkPath), url.Param("realm", realmName), url.Param("id", idClient), body.JSON(mappers))
+	return err
+}
+
+// UpdateClientProtocolMapper updates a protocol mapper of the client. The mapper must contain its ID.
+func (c *Client) UpdateClientProtocolMapper(accessToken string, realmName, idClient, mapperID string, mapper ProtocolMapperRepresentation) error {
+	return c.put(accessToken, url.Path(clientProtocolMapperIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("mapperId", mapperID), body.JSON(mapper))
+}
+
+// DeleteClientProtocolMapper deletes a protocol mapper of the client.
+func (c *Client) DeleteClientProtocolMapper(accessToken string, realmName, idClient, mapperID string) error {
+	return c.delete(accessToken, url.Path(clientProtocolMapperIDPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("mapperId", mapperID))
+}
+
+// AddClientScopeMappers adds several protocol mappers to the client scope at once. None is added if one of them
+// is rejected.
+func (c *Client) AddClientScopeMappers(accessToken string, realmName, scopeID string, mappers []ProtocolMapperRepresentation) error {
+	var _, err = c.post(accessToken, nil, url.Path(clientScopeMappersBulkPath), url.Param("realm", realmName), url.Param("id", scopeID), body.JSON(mappers))
+	return err
+}
@@ -0,0 +1,56 @@
+package keycloak
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddClientProtocolMappers(t *testing.T) {
+	var requests []string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var content, _ = ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(content))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var mappers = []ProtocolMapperRepresentation{NewGroupMembershipMapper("groups", "groups", true, TokenClaims{AccessToken: true})}
+	assert.Nil(t, client.AddClientProtocolMappers("token", "test", "c1", mappers))
+	assert.Nil(t, client.AddClientScopeMappers("token", "test", "s1", mappers))
+	var body = `[{"config":{"access.token.claim":"true","claim.name":"groups","full.path":"true","id.token.claim":"false","userinfo.token.claim":"false"},` +
+		`"name":"groups","protocol":"openid-connect","protocolMapper":"oidc-group-membership-mapper"}]` + "\n"
+	assert.Equal(t, []string{
+		"POST /auth/admin/realms/test/clients/c1/protocol-mappers/add-models " + body,
+		"POST /auth/admin/realms/test/client-scopes/s1/protocol-mappers/add-models " + body,
+	}, requests)
+}
+
+func TestProtocolMapperBuilders(t *testing.T) {
+	var mapper = NewUserAttributeMapper("department", "dept", "department", ClaimTypeString, AllTokens)
+	assert.Equal(t, UserAttributeMapperType, *mapper.ProtocolMapper)
+	assert.Equal(t, map[string]interface{}{
+		"user.attribute":       "dept",
+		"claim.name":           "department",
+		"jsonType.label":       "String",
+		"id.token.claim":       "true",
+		"access.token.claim":   "true",
+		"userinfo.token.claim": "true",
+	}, *mapper.Config)
+
+	mapper = NewHardcodedClaimMapper("tier", "tier", "2", ClaimTypeInt, TokenClaims{IDToken: true})
+	assert.Equal(t, "2", (*mapper.Config)["claim.value"])
+	assert.Equal(t, "false", (*mapper.Config)["access.token.claim"])
+
+	mapper = NewAudienceMapper("api", "my-api", "", AllTokens)
+	assert.Equal(t, map[string]interface{}{
+		"included.client.audience": "my-api",
+		"id.token.claim":           "true",
+		"access.token.claim":       "true",
+	}, *mapper.Config)
+
+	mapper = NewUserRealmRoleMapper("roles", "realm_roles", "", TokenClaims{AccessToken: true})
+	assert.Equal(t, "true", (*mapper.Config)["multivalued"])
+	assert.NotContains(t, *mapper.Config, "usermodel.realmRoleMapping.rolePrefix")
+}
@@ -0,0 +1,407 @@
+package keycloak
+
+import (
+	"strconv"
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2/plugin"
+	"gopkg.in/h2non/gentleman.v2/plugins/query"
+)
+
+// UserQuery filters the users returned by GetUsers. The zero value matches all the users.
+type UserQuery struct {
+	Email     string
+	FirstName string
+	LastName  string
+	Username  string
+	// Search is a string contained in the username, first name, last name or email. Keycloak ignores the
+	// other filters when it is set, so it cannot be combined with them.
+	Search string
+	// Exact makes Email, FirstName, LastName and Username match whole values instead of substrings.
+	Exact bool
+	// First is the paging offset and Max the maximum result size (100 if 0).
+	First int
+	Max   int
+	// BriefRepresentation only returns the basic information of the users.
+	BriefRepresentation bool
+	// Enabled, if not nil, only returns the enabled or the disabled users.
+	Enabled *bool
+	// IdpAlias only returns the users linked to this identity provider.
+	IdpAlias string
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q UserQuery) Validate() error {
+	var filtered = q.Email != "" || q.FirstName != "" || q.LastName != "" || q.Username != ""
+	switch {
+	case q.Search != "" && (filtered || q.Exact):
+		return newError(MsgErrInvalidParam, SearchWithFilters, nil)
+	case q.Exact && !filtered:
+		return newError(MsgErrInvalidParam, ExactWithoutFilter, nil)
+	default:
+		return validatePaging(q.First, q.Max)
+	}
+}
+
+func (q UserQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.add("email", q.Email)
+	p.add("firstName", q.FirstName)
+	p.add("lastName", q.LastName)
+	p.add("username", q.Username)
+	p.add("search", q.Search)
+	p.addBool("exact", q.Exact)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	p.addBool("briefRepresentation", q.BriefRepresentation)
+	if q.Enabled != nil {
+		p.add("enabled", strconv.FormatBool(*q.Enabled))
+	}
+	p.add("idpAlias", q.IdpAlias)
+	return p
+}
+
+// ClientQuery filters the clients returned by GetClients. The zero value matches all the clients.
+type ClientQuery struct {
+	ClientID string
+	// Search makes ClientID match a substring of the client ids instead of a whole one.
+	Search bool
+	// ViewableOnly only returns the clients the caller can view in full.
+	ViewableOnly bool
+	// First is the paging offset and Max the maximum result size.
+	First int
+	Max   int
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q ClientQuery) Validate() error {
+	if q.Search && q.ClientID == "" {
+		return newError(MsgErrInvalidParam, SearchWithoutClientID, nil)
+	}
+	return validatePaging(q.First, q.Max)
+}
+
+func (q ClientQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.add("clientId", q.ClientID)
+	p.addBool("search", q.Search)
+	p.addBool("viewableOnly", q.ViewableOnly)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	return p
+}
+
+// GroupQuery filters the top level groups returned by GetGroups. The zero value matches all the groups.
+type GroupQuery struct {
+	// Search is a string contained in the name of the group or of one of its sub groups.
+	Search string
+	// First is the paging offset and Max the maximum result size.
+	First int
+	Max   int
+	// BriefRepresentation, if not nil, sets whether only the basic information of the groups is returned
+	// (Keycloak defaults to true).
+	BriefRepresentation *bool
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q GroupQuery) Validate() error {
+	return validatePaging(q.First, q.Max)
+}
+
+func (q GroupQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.add("search", q.Search)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	if q.BriefRepresentation != nil {
+		p.add("briefRepresentation", strconv.FormatBool(*q.BriefRepresentation))
+	}
+	return p
+}
+
+// GroupCountQuery filters the groups counted by GetGroupCount. The zero value counts all the groups.
+type GroupCountQuery struct {
+	// Search is a string contained in the name of the groups counted.
+	Search string
+	// Top only counts the top level groups.
+	Top bool
+}
+
+func (q GroupCountQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.add("search", q.Search)
+	p.addBool("top", q.Top)
+	return p
+}
+
+// PageQuery pages the listings which take no other filter. The zero value returns the default page of Keycloak.
+type PageQuery struct {
+	// First is the paging offset and Max the maximum result size.
+	First int
+	Max   int
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q PageQuery) Validate() error {
+	return validatePaging(q.First, q.Max)
+}
+
+func (q PageQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	return p
+}
+
+// ResourceQuery filters the authorization resources returned by GetResources. The zero value matches all the
+// resources.
+type ResourceQuery struct {
+	// Name is a string contained in the name of the resources.
+	Name string
+	// URI is a URI of the resources. With MatchingURI, it may also match their URI patterns, e.g. /api/*.
+	URI         string
+	MatchingURI bool
+	Owner       string
+	Type        string
+	// Scope is a string contained in the name of one of the scopes of the resources.
+	Scope string
+	// First is the paging offset and Max the maximum result size.
+	First int
+	Max   int
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q ResourceQuery) Validate() error {
+	if q.MatchingURI && q.URI == "" {
+		return newError(MsgErrInvalidParam, MatchingURIWithoutURI, nil)
+	}
+	return validatePaging(q.First, q.Max)
+}
+
+func (q ResourceQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.add("name", q.Name)
+	p.add("uri", q.URI)
+	p.addBool("matchingUri", q.MatchingURI)
+	p.add("owner", q.Owner)
+	p.add("type", q.Type)
+	p.add("scope", q.Scope)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	return p
+}
+
+// AuthorizationScopeQuery filters the authorization scopes returned by GetAuthorizationScopes. The zero value
+// matches all the scopes.
+type AuthorizationScopeQuery struct {
+	// Name is a string contained in the name of the scopes.
+	Name string
+	// First is the paging offset and Max the maximum result size.
+	First int
+	Max   int
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q AuthorizationScopeQuery) Validate() error {
+	return validatePaging(q.First, q.Max)
+}
+
+func (q AuthorizationScopeQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.add("name", q.Name)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	return p
+}
+
+// PolicyQuery filters the policies returned by GetPolicies and the permissions returned by GetPermissions. The
+// zero value matches all of them.
+type PolicyQuery struct {
+	// Name is a string contained in the name of the policies.
+	Name string
+	// Type is the type of the policies, e.g. PolicyTypeRole.
+	Type string
+	// Resource and Scope are the id or the name of a resource or of a scope the permissions apply to.
+	Resource string
+	Scope    string
+	Owner    string
+	// First is the paging offset and Max the maximum result size.
+	First int
+	Max   int
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q PolicyQuery) Validate() error {
+	return validatePaging(q.First, q.Max)
+}
+
+func (q PolicyQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.add("name", q.Name)
+	p.add("type", q.Type)
+	p.add("resource", q.Resource)
+	p.add("scope", q.Scope)
+	p.add("owner", q.Owner)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	return p
+}
+
+// ActionsEmailOptions customizes the emails sent by ExecuteActionsEmail and SendReminderEmail.
+type ActionsEmailOptions struct {
+	// Lifespan is the validity of the link sent (12 hours if 0). It is rounded up to the second.
+	Lifespan time.Duration
+	// ClientID and RedirectURI define where the user is redirected once the actions are performed.
+	// RedirectURI must be a valid redirect URI of the client.
+	ClientID    string
+	RedirectURI string
+}
+
+// Validate checks that the fields of the options can be combined.
+func (o ActionsEmailOptions) Validate() error {
+	switch {
+	case o.Lifespan < 0:
+		return newError(MsgErrInvalidParam, NegativeLifespan, nil)
+	case o.RedirectURI != "" && o.ClientID == "":
+		return newError(MsgErrInvalidParam, RedirectURIWithoutClientID, nil)
+	default:
+		return nil
+	}
+}
+
+func (o ActionsEmailOptions) plugins() []plugin.Plugin {
+	var p queryParams
+	p.addInt("lifespan", int((o.Lifespan+time.Second-1)/time.Second))
+	p.add("client_id", o.ClientID)
+	p.add("redirect_uri", o.RedirectURI)
+	return p
+}
+
+// EventQuery filters the login events returned by GetEvents. The zero value matches all the events.
+type EventQuery struct {
+	// DateFrom and DateTo, if not zero, bound the days of the events. The time of day is ignored.
+	DateFrom time.Time
+	DateTo   time.Time
+	// Types only returns the events having one of these types, e.g. LOGIN or LOGIN_ERROR.
+	Types     []string
+	Client    string
+	User      string
+	IPAddress string
+	// First is the paging offset and Max the maximum result size (100 if 0).
+	First int
+	Max   int
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q EventQuery) Validate() error {
+	if err := validateDates(q.DateFrom, q.DateTo); err != nil {
+		return err
+	}
+	return validatePaging(q.First, q.Max)
+}
+
+func (q EventQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.addDate("dateFrom", q.DateFrom)
+	p.addDate("dateTo", q.DateTo)
+	p.addAll("type", q.Types)
+	p.add("client", q.Client)
+	p.add("user", q.User)
+	p.add("ipAddress", q.IPAddress)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	return p
+}
+
+// AdminEventQuery filters the admin events returned by GetAdminEvents. The zero value matches all the events.
+type AdminEventQuery struct {
+	// DateFrom and DateTo, if not zero, bound the days of the events. The time of day is ignored.
+	DateFrom time.Time
+	DateTo   time.Time
+	// OperationTypes only returns the events having one of these operation types, e.g. OperationTypeCreate.
+	OperationTypes []string
+	// ResourceTypes only returns the events affecting one of these resource types, e.g. ResourceTypeUser.
+	ResourceTypes []string
+	// ResourcePath is the path of the affected resource, e.g. users/<id>. It may contain * wildcards.
+	ResourcePath string
+	// AuthRealm, AuthClient, AuthUser and AuthIPAddress filter on the administrator who performed the operation.
+	AuthRealm     string
+	AuthClient    string
+	AuthUser      string
+	AuthIPAddress string
+	// First is the paging offset and Max the maximum result size (100 if 0).
+	First int
+	Max   int
+}
+
+// Validate checks that the fields of the query can be combined.
+func (q AdminEventQuery) Validate() error {
+	if err := validateDates(q.DateFrom, q.DateTo); err != nil {
+		return err
+	}
+	return validatePaging(q.First, q.Max)
+}
+
+func (q AdminEventQuery) plugins() []plugin.Plugin {
+	var p queryParams
+	p.addDate("dateFrom", q.DateFrom)
+	p.addDate("dateTo", q.DateTo)
+	p.addAll("operationTypes", q.OperationTypes)
+	p.addAll("resourceTypes", q.ResourceTypes)
+	p.add("resourcePath", q.ResourcePath)
+	p.add("authRealm", q.AuthRealm)
+	p.add("authClient", q.AuthClient)
+	p.add("authUser", q.AuthUser)
+	p.add("authIpAddress", q.AuthIPAddress)
+	p.addInt("first", q.First)
+	p.addInt("max", q.Max)
+	return p
+}
+
+func validateDates(from time.Time, to time.Time) error {
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return newError(MsgErrInvalidParam, DateToBeforeDateFrom, nil)
+	}
+	return nil
+}
+
+func validatePaging(first int, max int) error {
+	if first < 0 || max < 0 {
+		return newError(MsgErrInvalidParam, NegativePaging, nil)
+	}
+	return nil
+}
+
+// queryParams accumulates the query parameters which are set.
+type queryParams []plugin.Plugin
+
+func (p *queryParams) add(key string, value string) {
+	if value != "" {
+		*p = append(*p, query.Add(key, value))
+	}
+}
+
+func (p *queryParams) addAll(key string, values []string) {
+	for _, value := range values {
+		p.add(key, value)
+	}
+}
+
+// addDate adds the day of value, in the yyyy-MM-dd format expected by Keycloak.
+func (p *queryParams) addDate(key string, value time.Time) {
+	if !value.IsZero() {
+		p.add(key, value.Format("2006-01-02"))
+	}
+}
+
+func (p *queryParams) addBool(key string, value bool) {
+	if value {
+		p.add(key, "true")
+	}
+}
+
+func (p *queryParams) addInt(key string, value int) {
+	if value != 0 {
+		p.add(key, strconv.Itoa(value))
+	}
+}
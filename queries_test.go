@@ -0,0 +1,63 @@
+package keycloak
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryValidation(t *testing.T) {
+	var invalid = func(err error, detail string) {
+		assert.Equal(t, Error{Code: MsgErrInvalidParam, Detail: detail}, err)
+	}
+
+	assert.Nil(t, UserQuery{}.Validate())
+	assert.Nil(t, UserQuery{Username: "john", Email: "john@example.com", Exact: true, Max: 10}.Validate())
+	invalid(UserQuery{Search: "john", LastName: "doe"}.Validate(), SearchWithFilters)
+	invalid(UserQuery{Search: "john", Exact: true}.Validate(), SearchWithFilters)
+	invalid(UserQuery{Exact: true}.Validate(), ExactWithoutFilter)
+	invalid(UserQuery{First: -1}.Validate(), NegativePaging)
+
+	assert.Nil(t, ClientQuery{ClientID: "app", Search: true}.Validate())
+	invalid(ClientQuery{Search: true}.Validate(), SearchWithoutClientID)
+	invalid(GroupQuery{Max: -5}.Validate(), NegativePaging)
+	invalid(ResourceQuery{MatchingURI: true}.Validate(), MatchingURIWithoutURI)
+
+	assert.Nil(t, ActionsEmailOptions{Lifespan: time.Hour, ClientID: "app", RedirectURI: "https://app"}.Validate())
+	invalid(ActionsEmailOptions{RedirectURI: "https://app"}.Validate(), RedirectURIWithoutClientID)
+	invalid(ActionsEmailOptions{Lifespan: -time.Second}.Validate(), NegativeLifespan)
+}
+
+func TestQueryParameters(t *testing.T) {
+	var params url.Values
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	}))
+
+	var enabled = false
+	var _, err = client.GetUsers("token", "test", UserQuery{Username: "john", Exact: true, First: 20, Max: 10, Enabled: &enabled})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"username": {"john"}, "exact": {"true"}, "first": {"20"}, "max": {"10"}, "enabled": {"false"}}, params)
+
+	_, err = client.GetUsers("token", "test", UserQuery{})
+	assert.Nil(t, err)
+	assert.Empty(t, params)
+
+	_, err = client.GetClients("token", "test", ClientQuery{ClientID: "app", Search: true})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"clientId": {"app"}, "search": {"true"}}, params)
+
+	err = client.ExecuteActionsEmail("token", "test", "id", []string{"UPDATE_PASSWORD"}, ActionsEmailOptions{Lifespan: 90 * time.Minute, ClientID: "app", RedirectURI: "https://app/"})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"lifespan": {"5400"}, "client_id": {"app"}, "redirect_uri": {"https://app/"}}, params)
+
+	// A validity shorter than a second must not fall back to the default.
+	err = client.ExecuteActionsEmail("token", "test", "id", []string{"UPDATE_PASSWORD"}, ActionsEmailOptions{Lifespan: 500 * time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"lifespan": {"1"}}, params)
+}
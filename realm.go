@@ -44,7 +44,8 @@ func (c *Client) DeleteRealm(accessToken string, realmName string) error {
 	return c.delete(accessToken, url.Path(realmPath), url.Param("realm", realmName))
 }
 
-// ExportRealm recovers the full realm.
+// ExportRealm recovers the full realm. It relies on the export endpoint of a Keycloak extension, whereas
+// PartialExport uses the admin API.
 func (c *Client) ExportRealm(accessToken string, realmName string) (RealmRepresentation, error) {
 	var resp = RealmRepresentation{}
 	var err = c.get(accessToken, &resp, url.Path(exportRealmPath), url.Param("realm", realmName))
@@ -0,0 +1,131 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// masked is the value Keycloak returns in place of the secrets.
+const masked = "**********"
+
+// fields is a representation decoded as a JSON object.
+type fields map[string]interface{}
+
+// toFields returns the JSON object of a representation, without the ignored fields.
+func toFields(rep interface{}, ignored ...string) fields {
+	var res = fields{}
+	decode(rep, &res)
+	for _, field := range ignored {
+		delete(res, field)
+	}
+	return res
+}
+
+// decode converts src to dst through JSON. The representations always convert.
+func decode(src interface{}, dst interface{}) {
+	var bytes, _ = json.Marshal(src)
+	_ = json.Unmarshal(bytes, dst)
+}
+
+// drift returns the sorted fields of desired whose value live does not have.
+func drift(desired fields, live fields) []string {
+	var res []string
+	for field, value := range desired {
+		if !covers(value, live[field]) {
+			res = append(res, field)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// covers tells whether live has the desired value. The objects only need the desired keys, the lists of scalars
+// are compared regardless of their order and the masked secrets match any value.
+func covers(desired interface{}, live interface{}) bool {
+	if live == masked {
+		return true
+	}
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		var l, ok = live.(map[string]interface{})
+		if !ok {
+			return live == nil && len(d) == 0
+		}
+		for key, value := range d {
+			if !covers(value, l[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		var l, ok = live.([]interface{})
+		if !ok {
+			return live == nil && len(d) == 0
+		}
+		if len(d) != len(l) {
+			return false
+		}
+		if isScalars(d) && isScalars(l) {
+			return coversScalars(d, l)
+		}
+		for i := range d {
+			if !covers(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, live)
+	}
+}
+
+func isScalars(values []interface{}) bool {
+	for _, value := range values {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+// coversScalars tells whether the lists of scalars of the same length have the same values, regardless of their
+// order, the masked live values matching any desired value, e.g. the bindCredential of an LDAP component.
+func coversScalars(desired []interface{}, live []interface{}) bool {
+	var missing = map[string]int{}
+	for _, value := range desired {
+		missing[fmt.Sprint(value)]++
+	}
+	for _, value := range live {
+		if value == masked {
+			continue
+		}
+		var key = fmt.Sprint(value)
+		if missing[key] == 0 {
+			return false
+		}
+		missing[key]--
+	}
+	return true
+}
+
+// overlay returns live with the desired values, the objects being overlaid key by key. It is the payload of the
+// updates, which keeps the fields the desired representation does not set.
+func overlay(live fields, desired fields) fields {
+	var res = fields{}
+	for key, value := range live {
+		res[key] = value
+	}
+	for key, value := range desired {
+		var d, dok = value.(map[string]interface{})
+		var l, lok = res[key].(map[string]interface{})
+		if dok && lok {
+			res[key] = map[string]interface{}(overlay(l, d))
+		} else {
+			res[key] = value
+		}
+	}
+	return res
+}
@@ -0,0 +1,160 @@
+// Package reconcile converges a live realm towards a desired RealmRepresentation, such as a realm export kept
+// under version control. Compute returns the plan of the operations to run, which can be printed and reviewed,
+// and Apply runs them with the CRUD methods of keycloak.KeycloakAdmin.
+//
+// The resources are matched by name: the clients by client id, the groups by path, the roles, client scopes and
+// components by name, the identity providers, authentication flows and required actions by alias. Only the
+// fields set in the desired representations are compared, and the fields generated by the server, like the ids
+// and the secrets, are ignored. A collection which is nil in the desired realm is left as is.
+//
+// The authentication flows are converged along with their executions, sub flows and authenticator configurations,
+// the built in flows being left as is. The role mappings of the groups and the composites of the roles are not
+// reconciled.
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// Action is what an operation does to a resource.
+type Action string
+
+// Actions of the operations.
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Kinds of the resources.
+const (
+	KindRealm              = "realm"
+	KindClientScope        = "client scope"
+	KindClient             = "client"
+	KindRealmRole          = "realm role"
+	KindClientRole         = "client role"
+	KindGroup              = "group"
+	KindIdentityProvider   = "identity provider"
+	KindComponent          = "component"
+	KindAuthenticationFlow = "authentication flow"
+	KindRequiredAction     = "required action"
+)
+
+// Options tune the computation of a plan.
+type Options struct {
+	// Prune deletes the resources of the collections of the desired realm which it does not list, but the built
+	// in authentication flows and the default role of the realm.
+	Prune bool
+	// DryRun makes Reconcile return the plan without applying it.
+	DryRun bool
+}
+
+// Operation is a change to a resource of the realm.
+type Operation struct {
+	Action Action
+	Kind   string
+	// Name identifies the resource among those of its kind, e.g. the client id of a client or the path of a group.
+	Name string
+	// Fields are the drifted fields of an update, sorted.
+	Fields []string
+
+	apply func(a *applier) error
+}
+
+var actionSymbols = map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}
+
+// String formats the operation as a line of a plan, e.g. "~ client my-app: enabled, redirectUris".
+func (o Operation) String() string {
+	var s = fmt.Sprintf("%s %s %s", actionSymbols[o.Action], o.Kind, o.Name)
+	if len(o.Fields) > 0 {
+		s += ": " + strings.Join(o.Fields, ", ")
+	}
+	return s
+}
+
+// Plan is the ordered list of the operations converging a realm towards its desired state. The creations and
+// updates come first, parents before children, then the deletions.
+type Plan struct {
+	Realm      string
+	Operations []Operation
+
+	// ids maps the keys of the resources to their ids, completed with those of the created resources by Apply.
+	ids map[string]string
+}
+
+// String formats the plan, one operation per line.
+func (p Plan) String() string {
+	if len(p.Operations) == 0 {
+		return fmt.Sprintf("realm %s is up to date\n", p.Realm)
+	}
+	var b strings.Builder
+	for _, op := range p.Operations {
+		b.WriteString(op.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Apply runs the operations in order. It stops at the first error, which tells the failed operation.
+func (p Plan) Apply(admin keycloak.KeycloakAdmin, accessToken string) error {
+	var a = &applier{admin: admin, accessToken: accessToken, realmName: p.Realm, ids: p.ids}
+	for _, op := range p.Operations {
+		if err := op.apply(a); err != nil {
+			return fmt.Errorf("cannot %s %s %s: %w", op.Action, op.Kind, op.Name, err)
+		}
+	}
+	return nil
+}
+
+// Compute returns the plan converging the realm named after desired towards it. The realm is created if it does
+// not exist.
+func Compute(admin keycloak.KeycloakAdmin, accessToken string, desired keycloak.RealmRepresentation, options Options) (Plan, error) {
+	if desired.Realm == nil || *desired.Realm == "" {
+		return Plan{}, errors.New("reconcile: the desired realm has no name")
+	}
+	var p = &planner{
+		admin:       admin,
+		accessToken: accessToken,
+		realmName:   *desired.Realm,
+		options:     options,
+		ids:         map[string]string{},
+	}
+	if err := p.plan(desired); err != nil {
+		return Plan{}, err
+	}
+	var plan = Plan{Realm: p.realmName, Operations: p.ops, ids: p.ids}
+	for i := len(p.deletes) - 1; i >= 0; i-- {
+		plan.Operations = append(plan.Operations, p.deletes[i]...)
+	}
+	return plan, nil
+}
+
+// Reconcile computes the plan converging the realm towards desired and applies it, unless options.DryRun is set.
+// The plan is returned in both cases.
+func Reconcile(admin keycloak.KeycloakAdmin, accessToken string, desired keycloak.RealmRepresentation, options Options) (Plan, error) {
+	var plan, err = Compute(admin, accessToken, desired, options)
+	if err != nil || options.DryRun {
+		return plan, err
+	}
+	return plan, plan.Apply(admin, accessToken)
+}
+
+// applier holds what the operations need to run.
+type applier struct {
+	admin       keycloak.KeycloakAdmin
+	accessToken string
+	realmName   string
+	ids         map[string]string
+}
+
+// created records the id of a created resource, found at the end of its location.
+func (a *applier) created(key string, location string, err error) error {
+	if err == nil {
+		a.ids[key] = location[strings.LastIndex(location, "/")+1:]
+	}
+	return err
+}
@@ -0,0 +1,304 @@
+package reconcile
+
+import (
+	"testing"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+	"github.com/nmasse-itix/keycloak-client/keycloaktest"
+	"github.com/stretchr/testify/assert"
+)
+
+const accessToken = "token"
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func desiredRealm() keycloak.RealmRepresentation {
+	var clientRoles = map[string]interface{}{
+		"app": []keycloak.RoleRepresentation{{Name: strPtr("viewer")}},
+	}
+	return keycloak.RealmRepresentation{
+		Realm:       strPtr("demo"),
+		Enabled:     boolPtr(true),
+		BrowserFlow: strPtr("custom browser"),
+		ClientScopes: &[]keycloak.ClientScopeRepresentation{
+			{Name: strPtr("audience"), Protocol: strPtr("openid-connect")},
+		},
+		Clients: &[]keycloak.ClientRepresentation{
+			{ClientID: strPtr("app"), RedirectUris: &[]string{"https://app/*", "https://app.local/*"}},
+		},
+		Roles: &keycloak.RolesRepresentation{
+			Realm:  &[]keycloak.RoleRepresentation{{Name: strPtr("admin"), Description: strPtr("Administrators")}},
+			Client: &clientRoles,
+		},
+		Groups: &[]keycloak.GroupRepresentation{
+			{Name: strPtr("staff"), SubGroups: &[]keycloak.GroupRepresentation{{Name: strPtr("ops")}}},
+		},
+		IdentityProviders: &[]keycloak.IdentityProviderRepresentation{
+			{Alias: strPtr("github"), ProviderID: strPtr("github"), Config: &map[string]interface{}{"clientId": "id", "clientSecret": "secret"}},
+		},
+		Components: &keycloak.ComponentsExportRepresentation{
+			keycloak.UserStorageProviderType: {{
+				Name:       strPtr("ldap"),
+				ProviderID: strPtr("ldap"),
+				Config:     &keycloak.MultivaluedHashMap{"connectionUrl": {"ldap://ldap"}},
+				SubComponents: &keycloak.ComponentsExportRepresentation{
+					"org.keycloak.storage.ldap.mappers.LDAPStorageMapper": {{Name: strPtr("email"), ProviderID: strPtr("user-attribute-ldap-mapper")}},
+				},
+			}},
+		},
+		AuthenticationFlows: &[]keycloak.AuthenticationFlowRepresentation{
+			{Alias: strPtr("custom browser"), ProviderID: strPtr("basic-flow"), TopLevel: boolPtr(true)},
+		},
+		RequiredActions: &[]keycloak.RequiredActionProviderRepresentation{
+			{Alias: strPtr("CONFIGURE_TOTP"), Name: strPtr("Configure OTP"), Enabled: boolPtr(true), DefaultAction: boolPtr(true)},
+		},
+	}
+}
+
+func TestReconcileCreatesRealm(t *testing.T) {
+	var f = keycloaktest.New()
+
+	var plan, err = Reconcile(f, accessToken, desiredRealm(), Options{DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, `+ realm demo
++ client scope audience
++ client app
++ realm role admin
++ client role app/viewer
++ group /staff
++ group /staff/ops
++ identity provider github
++ component ldap (org.keycloak.storage.UserStorageProvider)
++ component ldap (org.keycloak.storage.UserStorageProvider)/email (org.keycloak.storage.ldap.mappers.LDAPStorageMapper)
++ authentication flow custom browser
++ required action CONFIGURE_TOTP
+~ realm demo: browserFlow
+`, plan.String())
+	_, err = f.GetRealm(accessToken, "demo")
+	assert.True(t, err != nil, "dry run must not create the realm")
+
+	assert.Nil(t, plan.Apply(f, accessToken))
+
+	realm, err := f.ExportRealm(accessToken, "demo")
+	assert.Nil(t, err)
+	assert.Equal(t, "custom browser", *realm.BrowserFlow)
+	assert.Len(t, *(*realm.Groups)[0].SubGroups, 1)
+	var ldap = (*realm.Components)[keycloak.UserStorageProviderType][0]
+	assert.Len(t, (*ldap.SubComponents)["org.keycloak.storage.ldap.mappers.LDAPStorageMapper"], 1)
+	action, err := f.GetRequiredAction(accessToken, "demo", "CONFIGURE_TOTP")
+	assert.Nil(t, err)
+	assert.True(t, *action.DefaultAction)
+
+	plan, err = Compute(f, accessToken, desiredRealm(), Options{Prune: true})
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Operations)
+	assert.Equal(t, "realm demo is up to date\n", plan.String())
+}
+
+func TestReconcileUpdates(t *testing.T) {
+	var f = keycloaktest.New()
+	var _, err = Reconcile(f, accessToken, desiredRealm(), Options{})
+	assert.Nil(t, err)
+
+	var desired = desiredRealm()
+	desired.DisplayName = strPtr("Demo")
+	(*desired.Clients)[0].RedirectUris = &[]string{"https://app.local/*", "https://app/*"}
+	(*desired.Clients)[0].Secret = strPtr("ignored")
+	(*desired.Clients)[0].Enabled = boolPtr(false)
+	(*desired.Roles.Realm)[0].Description = strPtr("Admins")
+	(*(*desired.Groups)[0].SubGroups)[0].Attributes = &map[string]interface{}{"team": []string{"ops"}}
+	(*desired.Components)[keycloak.UserStorageProviderType][0].Config = &keycloak.MultivaluedHashMap{"connectionUrl": {"ldaps://ldap"}}
+
+	plan, err := Compute(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, `~ client app: enabled
+~ realm role admin: description
+~ group /staff/ops: attributes
+~ component ldap (org.keycloak.storage.UserStorageProvider): config
+~ realm demo: displayName
+`, plan.String())
+	assert.Nil(t, plan.Apply(f, accessToken))
+
+	plan, err = Compute(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Operations)
+	clients, err := f.GetClients(accessToken, "demo", keycloak.ClientQuery{ClientID: "app"})
+	assert.Nil(t, err)
+	assert.False(t, *clients[0].Enabled)
+	assert.Len(t, *clients[0].RedirectUris, 2)
+}
+
+func TestReconcilePrunes(t *testing.T) {
+	var f = keycloaktest.New()
+	var _, err = Reconcile(f, accessToken, desiredRealm(), Options{})
+	assert.Nil(t, err)
+	for _, extra := range []func() error{
+		func() error {
+			_, err := f.CreateClient(accessToken, "demo", keycloak.ClientRepresentation{ClientID: strPtr("legacy")})
+			return err
+		},
+		func() error {
+			_, err := f.CreateRole(accessToken, "demo", keycloak.RoleRepresentation{Name: strPtr("default-roles-demo")})
+			return err
+		},
+		func() error {
+			groups, _ := f.GetGroups(accessToken, "demo", keycloak.GroupQuery{})
+			_, err := f.CreateChildGroup(accessToken, "demo", *(*groups[0].SubGroups)[0].ID, keycloak.GroupRepresentation{Name: strPtr("night")})
+			return err
+		},
+		func() error {
+			return f.CreateAuthenticationFlow(accessToken, "demo", keycloak.AuthenticationFlowRepresentation{Alias: strPtr("browser"), BuiltIn: boolPtr(true)})
+		},
+	} {
+		assert.Nil(t, extra())
+	}
+
+	var desired = desiredRealm()
+	*desired.Groups = (*desired.Groups)[:0]
+	plan, err := Compute(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Operations)
+
+	plan, err = Reconcile(f, accessToken, desired, Options{Prune: true})
+	assert.Nil(t, err)
+	assert.Equal(t, `- group /staff
+- client legacy
+`, plan.String())
+
+	groups, err := f.GetGroups(accessToken, "demo", keycloak.GroupQuery{})
+	assert.Nil(t, err)
+	assert.Empty(t, groups)
+	_, err = f.GetRoleByName(accessToken, "demo", "default-roles-demo")
+	assert.Nil(t, err)
+}
+
+// maskingFake masks the credentials of the components, like Keycloak does.
+type maskingFake struct {
+	*keycloaktest.Fake
+}
+
+func (f maskingFake) GetComponents(accessToken string, realmName string) ([]keycloak.ComponentRepresentation, error) {
+	var components, err = f.Fake.GetComponents(accessToken, realmName)
+	for _, component := range components {
+		if component.Config != nil {
+			if _, ok := (*component.Config)["bindCredential"]; ok {
+				(*component.Config)["bindCredential"] = []string{masked}
+			}
+		}
+	}
+	return components, err
+}
+
+func TestReconcileMaskedCredentials(t *testing.T) {
+	var f = maskingFake{keycloaktest.New()}
+	var desired = desiredRealm()
+	(*desired.Components)[keycloak.UserStorageProviderType][0].Config = &keycloak.MultivaluedHashMap{
+		"connectionUrl":  {"ldap://ldap"},
+		"bindCredential": {"secret"},
+	}
+	var _, err = Reconcile(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+
+	plan, err := Compute(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Operations)
+}
+
+func TestReconcileAuthenticationFlows(t *testing.T) {
+	var f = keycloaktest.New()
+	var priority = func(value int32) *int32 { return &value }
+	var desired = desiredRealm()
+	desired.AuthenticationFlows = &[]keycloak.AuthenticationFlowRepresentation{
+		{Alias: strPtr("custom browser"), ProviderID: strPtr("basic-flow"), TopLevel: boolPtr(true), AuthenticationExecutions: &[]keycloak.AuthenticationExecutionExportRepresentation{
+			{Authenticator: strPtr("auth-cookie"), Requirement: strPtr(keycloak.RequirementAlternative), Priority: priority(10)},
+			{FlowAlias: strPtr("custom browser forms"), AuthenticatorFlow: boolPtr(true), Requirement: strPtr(keycloak.RequirementAlternative), Priority: priority(20)},
+		}},
+		{Alias: strPtr("custom browser forms"), ProviderID: strPtr("basic-flow"), TopLevel: boolPtr(false), AuthenticationExecutions: &[]keycloak.AuthenticationExecutionExportRepresentation{
+			{Authenticator: strPtr("auth-otp-form"), AuthenticatorConfig: strPtr("otp"), Requirement: strPtr(keycloak.RequirementRequired), Priority: priority(20)},
+			{Authenticator: strPtr("auth-username-password-form"), Requirement: strPtr(keycloak.RequirementRequired), Priority: priority(10)},
+		}},
+		{Alias: strPtr("browser"), BuiltIn: boolPtr(true), TopLevel: boolPtr(true)},
+	}
+	desired.AuthenticatorConfig = &[]keycloak.AuthenticatorConfigRepresentation{
+		{Alias: strPtr("otp"), Config: &map[string]interface{}{"period": "30"}},
+	}
+
+	var _, err = Reconcile(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+	executions, err := f.GetAuthenticationExecutionForFlow(accessToken, "demo", "custom browser")
+	assert.Nil(t, err)
+	var providers []string
+	for _, execution := range executions {
+		if execution.ProviderID != nil {
+			providers = append(providers, *execution.ProviderID)
+		}
+	}
+	assert.Equal(t, []string{"auth-cookie", "auth-username-password-form", "auth-otp-form"}, providers)
+	assert.Equal(t, "otp", *executions[3].Alias)
+	plan, err := Compute(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Operations)
+
+	var forms = (*desired.AuthenticationFlows)[1]
+	(*forms.AuthenticationExecutions)[0].Requirement = strPtr(keycloak.RequirementDisabled)
+	(*desired.AuthenticatorConfig)[0].Config = &map[string]interface{}{"period": "60"}
+	plan, err = Reconcile(f, accessToken, desired, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "~ authentication flow custom browser: authenticationExecutions\n", plan.String())
+	otp, err := keycloak.GetAuthenticationExecutionByProviderID(f, accessToken, "demo", "custom browser", "auth-otp-form")
+	assert.Nil(t, err)
+	assert.Equal(t, keycloak.RequirementDisabled, *otp.Requirement)
+	config, err := f.GetAuthenticatorConfig(accessToken, "demo", *otp.AuthenticationConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"period": "60"}, *config.Config)
+
+	(*forms.AuthenticationExecutions)[0].FlowAlias = strPtr("unknown")
+	_, err = Compute(f, accessToken, desired, Options{})
+	assert.EqualError(t, err, "reconcile: the desired sub flow unknown is missing or contains itself")
+}
+
+func TestReconcileErrors(t *testing.T) {
+	var f = keycloaktest.New()
+
+	var _, err = Compute(f, accessToken, keycloak.RealmRepresentation{}, Options{})
+	assert.NotNil(t, err)
+
+	var desired = desiredRealm()
+	(*desired.Clients)[0].ClientID = strPtr("other")
+	_, err = Compute(f, accessToken, desired, Options{})
+	assert.EqualError(t, err, "reconcile: the client app of the desired client roles does not exist")
+
+	desired = desiredRealm()
+	desired.Realm = strPtr("master")
+	(*desired.Groups)[0].Name = nil
+	_, err = Compute(f, accessToken, desired, Options{})
+	assert.EqualError(t, err, "reconcile: a desired group has no name")
+}
+
+func TestDrift(t *testing.T) {
+	var live = fields{
+		"enabled": true,
+		"uris":    []interface{}{"b", "a"},
+		"config":  map[string]interface{}{"url": []interface{}{"x"}, "secret": masked, "other": "kept"},
+	}
+	assert.Empty(t, drift(fields{"uris": []interface{}{"a", "b"}, "config": map[string]interface{}{"secret": "s"}}, live))
+	assert.Equal(t, []string{"config", "enabled", "name"}, drift(fields{
+		"enabled": false,
+		"name":    "n",
+		"config":  map[string]interface{}{"url": []interface{}{"y"}},
+	}, live))
+
+	assert.Empty(t, drift(fields{"config": map[string]interface{}{"bindCredential": []interface{}{"secret"}}},
+		fields{"config": map[string]interface{}{"bindCredential": []interface{}{masked}}}))
+	assert.Equal(t, []string{"uris"}, drift(fields{"uris": []interface{}{"a", "c"}}, fields{"uris": []interface{}{masked, "b"}}))
+
+	var merged = overlay(live, fields{"config": map[string]interface{}{"url": []interface{}{"y"}}})
+	assert.Equal(t, map[string]interface{}{"url": []interface{}{"y"}, "secret": masked, "other": "kept"}, merged["config"])
+	assert.Equal(t, true, merged["enabled"])
+}
@@ -0,0 +1,917 @@
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	keycloak "github.com/nmasse-itix/keycloak-client"
+)
+
+// realmCollections are the fields of a realm which are not its settings.
+var realmCollections = []string{
+	"id", "keycloakVersion", "users", "federatedUsers", "groups", "roles", "clients", "clientScopes",
+	"clientTemplates", "scopeMappings", "clientScopeMappings", "protocolMappers", "components", "identityProviders",
+	"identityProviderMappers", "authenticationFlows", "authenticatorConfig", "requiredActions",
+	"userFederationProviders", "userFederationMappers",
+}
+
+// realmFlowBindings are the settings of a realm naming authentication flows, which only exist once the flows
+// are created.
+var realmFlowBindings = []string{
+	"browserFlow", "registrationFlow", "directGrantFlow", "resetCredentialsFlow", "clientAuthenticationFlow",
+	"dockerAuthenticationFlow",
+}
+
+// builtIns are the resources Keycloak creates along with each realm, which are never pruned.
+var builtIns = map[string][]string{
+	KindClient:      {"account", "account-console", "admin-cli", "broker", "realm-management", "security-admin-console"},
+	KindClientScope: {"acr", "address", "basic", "email", "microprofile-jwt", "offline_access", "phone", "profile", "role_list", "roles", "saml_organization", "organization", "web-origins"},
+	KindRealmRole:   {"offline_access", "uma_authorization"},
+	KindRequiredAction: {
+		"CONFIGURE_TOTP", "CONFIGURE_RECOVERY_AUTHN_CODES", "TERMS_AND_CONDITIONS", "UPDATE_EMAIL", "UPDATE_PASSWORD",
+		"UPDATE_PROFILE", "VERIFY_EMAIL", "VERIFY_PROFILE", "delete_account", "delete_credential", "update_user_locale",
+		"webauthn-register", "webauthn-register-passwordless",
+	},
+}
+
+// planner computes the operations of a plan. The deletions are grouped by kind, so that they run in the reverse
+// order of the creations.
+type planner struct {
+	admin       keycloak.KeycloakAdmin
+	accessToken string
+	realmName   string
+	options     Options
+	ids         map[string]string
+	ops         []Operation
+	deletes     [][]Operation
+	exists      bool
+}
+
+func (p *planner) add(action Action, kind string, name string, apply func(a *applier) error) {
+	p.ops = append(p.ops, Operation{Action: action, Kind: kind, Name: name, apply: apply})
+}
+
+func (p *planner) update(kind string, name string, drifted []string, apply func(a *applier) error) {
+	p.ops = append(p.ops, Operation{Action: ActionUpdate, Kind: kind, Name: name, Fields: drifted, apply: apply})
+}
+
+// deleteAll plans the deletions of a kind of resources, if pruning. The built in resources are left as is.
+func (p *planner) deleteAll(kind string, names []string, apply func(a *applier, name string) error) {
+	if !p.options.Prune {
+		return
+	}
+	var ops []Operation
+	for _, name := range names {
+		var name = name
+		if p.builtIn(kind, name) {
+			continue
+		}
+		ops = append(ops, Operation{Action: ActionDelete, Kind: kind, Name: name, apply: func(a *applier) error {
+			return apply(a, name)
+		}})
+	}
+	p.deletes = append(p.deletes, ops)
+}
+
+// builtIn tells whether a resource is created by Keycloak along with the realm: the builtIns, the roles of the
+// built in clients, the default role of the realm and, in the master realm, the admin roles and the clients
+// managing the other realms.
+func (p *planner) builtIn(kind string, name string) bool {
+	switch kind {
+	case KindClientRole:
+		return p.builtIn(KindClient, strings.SplitN(name, "/", 2)[0])
+	case KindRealmRole:
+		if name == "default-roles-"+strings.ToLower(p.realmName) || p.realmName == "master" && (name == "admin" || name == "create-realm") {
+			return true
+		}
+	case KindClient:
+		if p.realmName == "master" && strings.HasSuffix(name, "-realm") {
+			return true
+		}
+	}
+	for _, builtIn := range builtIns[kind] {
+		if name == builtIn {
+			return true
+		}
+	}
+	return false
+}
+
+func missing(kind string, field string) error {
+	return fmt.Errorf("reconcile: a desired %s has no %s", kind, field)
+}
+
+func (p *planner) plan(desired keycloak.RealmRepresentation) error {
+	var live, err = p.admin.GetRealm(p.accessToken, p.realmName)
+	switch {
+	case err == nil:
+		p.exists = true
+	case !errors.Is(err, keycloak.ErrNotFound):
+		return err
+	}
+
+	// A created realm is bound to its flows once they are created too.
+	var settings = toFields(desired, realmCollections...)
+	if !p.exists {
+		var created = toFields(settings, realmFlowBindings...)
+		p.add(ActionCreate, KindRealm, p.realmName, func(a *applier) error {
+			var rep keycloak.RealmRepresentation
+			decode(created, &rep)
+			var _, err = a.admin.CreateRealm(a.accessToken, rep)
+			return err
+		})
+		var bindings = fields{}
+		for _, field := range realmFlowBindings {
+			if value, ok := settings[field]; ok {
+				bindings[field] = value
+			}
+		}
+		settings, live = bindings, keycloak.RealmRepresentation{}
+	}
+
+	var steps = []func() error{
+		func() error { return p.clientScopes(desired.ClientScopes) },
+		func() error { return p.clients(desired.Clients) },
+		func() error { return p.realmRoles(desired.Roles) },
+		func() error { return p.clientRoles(desired.Roles, desired.Clients) },
+		func() error { return p.groups(desired.Groups) },
+		func() error { return p.identityProviders(desired.IdentityProviders) },
+		func() error { return p.components(desired.Components, live.ID) },
+		func() error { return p.authenticationFlows(desired.AuthenticationFlows, desired.AuthenticatorConfig) },
+		func() error { return p.requiredActions(desired.RequiredActions) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	if drifted := drift(settings, toFields(live, realmCollections...)); len(drifted) > 0 {
+		p.update(KindRealm, p.realmName, drifted, func(a *applier) error {
+			var rep keycloak.RealmRepresentation
+			decode(settings, &rep)
+			return a.admin.UpdateRealm(a.accessToken, a.realmName, rep)
+		})
+	}
+	return nil
+}
+
+func (p *planner) clientScopes(desired *[]keycloak.ClientScopeRepresentation) error {
+	if desired == nil {
+		return nil
+	}
+	var live = map[string]keycloak.ClientScopeRepresentation{}
+	if p.exists {
+		var scopes, err = p.admin.GetClientScopes(p.accessToken, p.realmName)
+		if err != nil {
+			return err
+		}
+		for _, scope := range scopes {
+			live[*scope.Name] = scope
+			p.ids["client scope:"+*scope.Name] = *scope.ID
+		}
+	}
+
+	var ignored = []string{"id", "protocolMappers"}
+	for _, scope := range *desired {
+		if scope.Name == nil {
+			return missing(KindClientScope, "name")
+		}
+		var name, want = *scope.Name, toFields(scope, "id")
+		var existing, ok = live[name]
+		delete(live, name)
+		if !ok {
+			p.add(ActionCreate, KindClientScope, name, func(a *applier) error {
+				var rep keycloak.ClientScopeRepresentation
+				decode(want, &rep)
+				var location, err = a.admin.CreateClientScope(a.accessToken, a.realmName, rep)
+				return a.created("client scope:"+name, location, err)
+			})
+			continue
+		}
+		var have = toFields(existing)
+		if drifted := drift(toFields(want, ignored...), have); len(drifted) > 0 {
+			p.update(KindClientScope, name, drifted, func(a *applier) error {
+				var rep keycloak.ClientScopeRepresentation
+				decode(overlay(have, toFields(want, ignored...)), &rep)
+				return a.admin.UpdateClientScope(a.accessToken, a.realmName, *existing.ID, rep)
+			})
+		}
+	}
+	p.deleteAll(KindClientScope, sortedNames(live), func(a *applier, name string) error {
+		return a.admin.DeleteClientScope(a.accessToken, a.realmName, a.ids["client scope:"+name])
+	})
+	return nil
+}
+
+// clientsIgnored are the fields of the clients which are generated or not updated along with the client.
+var clientsIgnored = []string{
+	"id", "secret", "registrationAccessToken", "access", "protocolMappers", "authorizationSettings",
+	"defaultClientScopes", "optionalClientScopes",
+}
+
+func (p *planner) clients(desired *[]keycloak.ClientRepresentation) error {
+	var live = map[string]keycloak.ClientRepresentation{}
+	if p.exists {
+		var err = keycloak.ForEachClient(p.admin, p.accessToken, p.realmName, keycloak.ClientQuery{}, 0, func(c keycloak.ClientRepresentation) error {
+			live[*c.ClientID] = c
+			p.ids["client:"+*c.ClientID] = *c.ID
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if desired == nil {
+		return nil
+	}
+
+	for _, c := range *desired {
+		if c.ClientID == nil {
+			return missing(KindClient, "clientId")
+		}
+		var clientID, want = *c.ClientID, toFields(c, "id")
+		var existing, ok = live[clientID]
+		delete(live, clientID)
+		if !ok {
+			p.add(ActionCreate, KindClient, clientID, func(a *applier) error {
+				var rep keycloak.ClientRepresentation
+				decode(want, &rep)
+				var location, err = a.admin.CreateClient(a.accessToken, a.realmName, rep)
+				return a.created("client:"+clientID, location, err)
+			})
+			continue
+		}
+		var have = toFields(existing)
+		if drifted := drift(toFields(want, clientsIgnored...), have); len(drifted) > 0 {
+			p.update(KindClient, clientID, drifted, func(a *applier) error {
+				var rep keycloak.ClientRepresentation
+				decode(overlay(have, toFields(want, clientsIgnored...)), &rep)
+				return a.admin.UpdateClient(a.accessToken, a.realmName, *existing.ID, rep)
+			})
+		}
+	}
+	p.deleteAll(KindClient, sortedNames(live), func(a *applier, clientID string) error {
+		return a.admin.DeleteClient(a.accessToken, a.realmName, a.ids["client:"+clientID])
+	})
+	return nil
+}
+
+// rolesIgnored are the fields of the roles which are generated or reconciled apart.
+var rolesIgnored = []string{"id", "containerId", "clientRole", "composite", "composites"}
+
+func (p *planner) realmRoles(desired *keycloak.RolesRepresentation) error {
+	if desired == nil || desired.Realm == nil {
+		return nil
+	}
+	var live = map[string]keycloak.RoleRepresentation{}
+	if p.exists {
+		var roles, err = p.admin.GetRoles(p.accessToken, p.realmName)
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			live[*role.Name] = role
+		}
+	}
+
+	for _, role := range *desired.Realm {
+		if role.Name == nil {
+			return missing(KindRealmRole, "name")
+		}
+		var name, want = *role.Name, toFields(role, rolesIgnored...)
+		var existing, ok = live[name]
+		delete(live, name)
+		if !ok {
+			p.add(ActionCreate, KindRealmRole, name, func(a *applier) error {
+				var rep keycloak.RoleRepresentation
+				decode(want, &rep)
+				var _, err = a.admin.CreateRole(a.accessToken, a.realmName, rep)
+				return err
+			})
+			continue
+		}
+		var have = toFields(existing)
+		if drifted := drift(want, have); len(drifted) > 0 {
+			p.update(KindRealmRole, name, drifted, func(a *applier) error {
+				var rep keycloak.RoleRepresentation
+				decode(overlay(have, want), &rep)
+				return a.admin.UpdateRole(a.accessToken, a.realmName, name, rep)
+			})
+		}
+	}
+	p.deleteAll(KindRealmRole, sortedNames(live), func(a *applier, name string) error {
+		return a.admin.DeleteRole(a.accessToken, a.realmName, name)
+	})
+	return nil
+}
+
+// clientRoles plans the roles of the clients listed in the desired roles, which are either live or desired
+// clients.
+func (p *planner) clientRoles(desired *keycloak.RolesRepresentation, desiredClients *[]keycloak.ClientRepresentation) error {
+	if desired == nil || desired.Client == nil {
+		return nil
+	}
+	var clientRoles map[string][]keycloak.RoleRepresentation
+	decode(*desired.Client, &clientRoles)
+
+	var clientIDs []string
+	for clientID := range clientRoles {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	// deleted maps the names of the deleted roles to their client id and role name.
+	var deleted = map[string][2]string{}
+	for _, clientID := range clientIDs {
+		var clientID, clientKey = clientID, "client:" + clientID
+		var live = map[string]keycloak.RoleRepresentation{}
+		if id, ok := p.ids[clientKey]; ok {
+			var roles, err = p.admin.GetClientRoles(p.accessToken, p.realmName, id)
+			if err != nil {
+				return err
+			}
+			for _, role := range roles {
+				live[*role.Name] = role
+			}
+		} else if !hasClient(desiredClients, clientID) {
+			return fmt.Errorf("reconcile: the client %s of the desired client roles does not exist", clientID)
+		}
+
+		for _, role := range clientRoles[clientID] {
+			if role.Name == nil {
+				return missing(KindClientRole, "name")
+			}
+			var name, want = *role.Name, toFields(role, rolesIgnored...)
+			var existing, ok = live[name]
+			delete(live, name)
+			if !ok {
+				p.add(ActionCreate, KindClientRole, clientID+"/"+name, func(a *applier) error {
+					var rep keycloak.RoleRepresentation
+					decode(want, &rep)
+					var _, err = a.admin.CreateClientRole(a.accessToken, a.realmName, a.ids[clientKey], rep)
+					return err
+				})
+				continue
+			}
+			var have = toFields(existing)
+			if drifted := drift(want, have); len(drifted) > 0 {
+				p.update(KindClientRole, clientID+"/"+name, drifted, func(a *applier) error {
+					var rep keycloak.RoleRepresentation
+					decode(overlay(have, want), &rep)
+					return a.admin.UpdateClientRole(a.accessToken, a.realmName, a.ids[clientKey], name, rep)
+				})
+			}
+		}
+		for name := range live {
+			deleted[clientID+"/"+name] = [2]string{clientID, name}
+		}
+	}
+	p.deleteAll(KindClientRole, sortedNames(deleted), func(a *applier, name string) error {
+		var role = deleted[name]
+		return a.admin.DeleteClientRole(a.accessToken, a.realmName, a.ids["client:"+role[0]], role[1])
+	})
+	return nil
+}
+
+func hasClient(clients *[]keycloak.ClientRepresentation, clientID string) bool {
+	if clients != nil {
+		for _, c := range *clients {
+			if c.ClientID != nil && *c.ClientID == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupsIgnored are the fields of the groups which are generated or not updated along with the group.
+var groupsIgnored = []string{"id", "path", "subGroups", "realmRoles", "clientRoles", "access"}
+
+func (p *planner) groups(desired *[]keycloak.GroupRepresentation) error {
+	if desired == nil {
+		return nil
+	}
+	var live = map[string]keycloak.GroupRepresentation{}
+	if p.exists {
+		var brief = false
+		var err = keycloak.ForEachGroup(p.admin, p.accessToken, p.realmName, keycloak.GroupQuery{BriefRepresentation: &brief}, 0, func(g keycloak.GroupRepresentation) error {
+			flattenGroups(g, "", live)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for path, g := range live {
+			p.ids["group:"+path] = *g.ID
+		}
+	}
+
+	var groups = map[string]keycloak.GroupRepresentation{}
+	var paths []string
+	for _, g := range *desired {
+		if err := flattenDesiredGroups(g, "", groups, &paths); err != nil {
+			return err
+		}
+	}
+	for _, path := range paths {
+		var path, want = path, toFields(groups[path], groupsIgnored...)
+		var parent = path[:strings.LastIndex(path, "/")]
+		var existing, ok = live[path]
+		delete(live, path)
+		if !ok {
+			p.add(ActionCreate, KindGroup, path, func(a *applier) error {
+				var rep keycloak.GroupRepresentation
+				decode(want, &rep)
+				if parent == "" {
+					var location, err = a.admin.CreateGroup(a.accessToken, a.realmName, rep)
+					return a.created("group:"+path, location, err)
+				}
+				var location, err = a.admin.CreateChildGroup(a.accessToken, a.realmName, a.ids["group:"+parent], rep)
+				return a.created("group:"+path, location, err)
+			})
+			continue
+		}
+		var have = toFields(existing, "subGroups")
+		if drifted := drift(want, have); len(drifted) > 0 {
+			p.update(KindGroup, path, drifted, func(a *applier) error {
+				var rep keycloak.GroupRepresentation
+				decode(overlay(have, want), &rep)
+				return a.admin.UpdateGroup(a.accessToken, a.realmName, *existing.ID, rep)
+			})
+		}
+	}
+
+	// The sub groups of a deleted group are deleted along with it.
+	var deleted []string
+	for _, path := range sortedNames(live) {
+		if !hasAncestor(deleted, path) {
+			deleted = append(deleted, path)
+		}
+	}
+	p.deleteAll(KindGroup, deleted, func(a *applier, path string) error {
+		return a.admin.DeleteGroup(a.accessToken, a.realmName, a.ids["group:"+path])
+	})
+	return nil
+}
+
+func hasAncestor(paths []string, path string) bool {
+	for _, ancestor := range paths {
+		if strings.HasPrefix(path, ancestor+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func flattenGroups(g keycloak.GroupRepresentation, parent string, groups map[string]keycloak.GroupRepresentation) {
+	var path = parent + "/" + *g.Name
+	groups[path] = g
+	if g.SubGroups != nil {
+		for _, sub := range *g.SubGroups {
+			flattenGroups(sub, path, groups)
+		}
+	}
+}
+
+// flattenDesiredGroups flattens the desired groups, listing their paths with the parents first.
+func flattenDesiredGroups(g keycloak.GroupRepresentation, parent string, groups map[string]keycloak.GroupRepresentation, paths *[]string) error {
+	if g.Name == nil {
+		return missing(KindGroup, "name")
+	}
+	var path = parent + "/" + *g.Name
+	groups[path] = g
+	*paths = append(*paths, path)
+	if g.SubGroups != nil {
+		for _, sub := range *g.SubGroups {
+			if err := flattenDesiredGroups(sub, path, groups, paths); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *planner) identityProviders(desired *[]keycloak.IdentityProviderRepresentation) error {
+	if desired == nil {
+		return nil
+	}
+	var live = map[string]keycloak.IdentityProviderRepresentation{}
+	if p.exists {
+		var idps, err = p.admin.GetIdps(p.accessToken, p.realmName)
+		if err != nil {
+			return err
+		}
+		for _, idp := range idps {
+			live[*idp.Alias] = idp
+		}
+	}
+
+	for _, idp := range *desired {
+		if idp.Alias == nil {
+			return missing(KindIdentityProvider, "alias")
+		}
+		var alias, want = *idp.Alias, toFields(idp, "internalId")
+		var existing, ok = live[alias]
+		delete(live, alias)
+		if !ok {
+			p.add(ActionCreate, KindIdentityProvider, alias, func(a *applier) error {
+				var rep keycloak.IdentityProviderRepresentation
+				decode(want, &rep)
+				var _, err = a.admin.CreateIdp(a.accessToken, a.realmName, rep)
+				return err
+			})
+			continue
+		}
+		var have = toFields(existing)
+		if drifted := drift(want, have); len(drifted) > 0 {
+			p.update(KindIdentityProvider, alias, drifted, func(a *applier) error {
+				var rep keycloak.IdentityProviderRepresentation
+				decode(overlay(have, want), &rep)
+				return a.admin.UpdateIdp(a.accessToken, a.realmName, alias, rep)
+			})
+		}
+	}
+	p.deleteAll(KindIdentityProvider, sortedNames(live), func(a *applier, alias string) error {
+		return a.admin.DeleteIdp(a.accessToken, a.realmName, alias)
+	})
+	return nil
+}
+
+// components plans the components of the realm, whose id is realmID, and their sub components. Only the
+// provider types listed in the desired components are pruned.
+func (p *planner) components(desired *keycloak.ComponentsExportRepresentation, realmID *string) error {
+	if desired == nil {
+		return nil
+	}
+	var children = map[string][]keycloak.ComponentRepresentation{}
+	if p.exists {
+		var components, err = p.admin.GetComponents(p.accessToken, p.realmName)
+		if err != nil {
+			return err
+		}
+		for _, c := range components {
+			if c.ParentID != nil {
+				children[*c.ParentID] = append(children[*c.ParentID], c)
+			}
+		}
+	}
+	var parentID string
+	if realmID != nil {
+		parentID = *realmID
+	}
+	var deleted []string
+	if err := p.subComponents(*desired, "", parentID, children, &deleted); err != nil {
+		return err
+	}
+	p.deleteAll(KindComponent, deleted, func(a *applier, name string) error {
+		return a.admin.DeleteComponent(a.accessToken, a.realmName, a.ids["component:"+name])
+	})
+	return nil
+}
+
+// subComponents plans the components of a parent, named parentName, whose live id is parentID if it exists.
+// The components are named after the path of their names, followed by their provider type.
+func (p *planner) subComponents(desired keycloak.ComponentsExportRepresentation, parentName string, parentID string, children map[string][]keycloak.ComponentRepresentation, deleted *[]string) error {
+	var providerTypes []string
+	for providerType := range desired {
+		providerTypes = append(providerTypes, providerType)
+	}
+	sort.Strings(providerTypes)
+
+	for _, providerType := range providerTypes {
+		var live = map[string]keycloak.ComponentRepresentation{}
+		for _, c := range children[parentID] {
+			if c.ProviderType != nil && *c.ProviderType == providerType {
+				live[componentName(parentName, *c.Name, providerType)] = c
+				p.ids["component:"+componentName(parentName, *c.Name, providerType)] = *c.ID
+			}
+		}
+		for _, c := range desired[providerType] {
+			if c.Name == nil {
+				return missing(KindComponent, "name")
+			}
+			var name = componentName(parentName, *c.Name, providerType)
+			var want = toFields(keycloak.ComponentRepresentation{
+				Name:       c.Name,
+				ProviderID: c.ProviderID,
+				SubType:    c.SubType,
+				Config:     c.Config,
+			})
+			var existing, ok = live[name]
+			delete(live, name)
+			if !ok {
+				var parentKey, providerType = "component:" + parentName, providerType
+				p.add(ActionCreate, KindComponent, name, func(a *applier) error {
+					var rep keycloak.ComponentRepresentation
+					decode(want, &rep)
+					rep.ProviderType = &providerType
+					if parentID, ok := a.ids[parentKey]; ok {
+						rep.ParentID = &parentID
+					}
+					var location, err = a.admin.CreateComponent(a.accessToken, a.realmName, rep)
+					return a.created("component:"+name, location, err)
+				})
+			} else {
+				var have = toFields(existing)
+				if drifted := drift(want, have); len(drifted) > 0 {
+					p.update(KindComponent, name, drifted, func(a *applier) error {
+						var rep keycloak.ComponentRepresentation
+						decode(overlay(have, want), &rep)
+						return a.admin.UpdateComponent(a.accessToken, a.realmName, *existing.ID, rep)
+					})
+				}
+			}
+			if c.SubComponents != nil {
+				var subParentID string
+				if ok {
+					subParentID = *existing.ID
+				}
+				if err := p.subComponents(*c.SubComponents, name, subParentID, children, deleted); err != nil {
+					return err
+				}
+			}
+		}
+		*deleted = append(*deleted, sortedNames(live)...)
+	}
+	return nil
+}
+
+func componentName(parentName string, name string, providerType string) string {
+	if parentName == "" {
+		return name + " (" + providerType + ")"
+	}
+	return parentName + "/" + name + " (" + providerType + ")"
+}
+
+// authenticationFlows plans the top level flows along with their executions, sub flows and authenticator
+// configurations, which keycloak.SyncAuthenticationFlow converges. The built in flows are left as is.
+func (p *planner) authenticationFlows(desired *[]keycloak.AuthenticationFlowRepresentation, configs *[]keycloak.AuthenticatorConfigRepresentation) error {
+	if desired == nil {
+		return nil
+	}
+	var live = map[string]keycloak.AuthenticationFlowRepresentation{}
+	if p.exists {
+		var flows, err = p.admin.GetAuthenticationFlows(p.accessToken, p.realmName)
+		if err != nil {
+			return err
+		}
+		for _, flow := range flows {
+			live[*flow.Alias] = flow
+		}
+	}
+
+	// The sub flows and the configurations are referenced by alias from the executions.
+	var byAlias = map[string]keycloak.AuthenticationFlowRepresentation{}
+	for _, flow := range *desired {
+		if flow.Alias == nil {
+			return missing(KindAuthenticationFlow, "alias")
+		}
+		byAlias[*flow.Alias] = flow
+	}
+	var configsByAlias = map[string]keycloak.AuthenticatorConfigRepresentation{}
+	if configs != nil {
+		for _, config := range *configs {
+			if config.Alias != nil {
+				configsByAlias[*config.Alias] = config
+			}
+		}
+	}
+
+	for _, flow := range *desired {
+		if flow.TopLevel != nil && !*flow.TopLevel {
+			continue
+		}
+		var alias = *flow.Alias
+		var existing, ok = live[alias]
+		delete(live, alias)
+		if flow.BuiltIn != nil && *flow.BuiltIn || ok && existing.BuiltIn != nil && *existing.BuiltIn {
+			continue
+		}
+		var want, err = desiredFlow(alias, byAlias, configsByAlias, map[string]bool{})
+		if err != nil {
+			return err
+		}
+		var sync = func(a *applier) error {
+			return keycloak.SyncAuthenticationFlow(a.admin, a.accessToken, a.realmName, want)
+		}
+		if !ok {
+			p.add(ActionCreate, KindAuthenticationFlow, alias, sync)
+			continue
+		}
+		have, err := p.liveFlow(existing)
+		if err != nil {
+			return err
+		}
+		if drifted := drift(flowFields(want), flowFields(have)); len(drifted) > 0 {
+			p.update(KindAuthenticationFlow, alias, drifted, sync)
+		}
+	}
+
+	var deleted []string
+	for _, alias := range sortedNames(live) {
+		if live[alias].BuiltIn == nil || !*live[alias].BuiltIn {
+			deleted = append(deleted, alias)
+			p.ids["authentication flow:"+alias] = *live[alias].ID
+		}
+	}
+	p.deleteAll(KindAuthenticationFlow, deleted, func(a *applier, alias string) error {
+		return a.admin.DeleteAuthenticationFlow(a.accessToken, a.realmName, a.ids["authentication flow:"+alias])
+	})
+	return nil
+}
+
+// flowFields returns the compared fields of a flow, named like those of the representations.
+func flowFields(flow keycloak.AuthenticationFlow) fields {
+	return toFields(struct {
+		Description string                   `json:"description"`
+		ProviderID  string                   `json:"providerId"`
+		Executions  []keycloak.FlowExecution `json:"authenticationExecutions"`
+	}{flow.Description, flow.ProviderID, flow.Executions})
+}
+
+// desiredFlow converts the desired flow of a realm export, whose executions reference the sub flows and the
+// authenticator configurations by alias. The sub flows on the path to the flow are visited, which catches the
+// cycles.
+func desiredFlow(alias string, flows map[string]keycloak.AuthenticationFlowRepresentation, configs map[string]keycloak.AuthenticatorConfigRepresentation, visited map[string]bool) (keycloak.AuthenticationFlow, error) {
+	var rep, ok = flows[alias]
+	if !ok || visited[alias] {
+		return keycloak.AuthenticationFlow{}, fmt.Errorf("reconcile: the desired sub flow %s is missing or contains itself", alias)
+	}
+	visited[alias] = true
+	defer delete(visited, alias)
+
+	var flow = keycloak.AuthenticationFlow{Alias: alias, ProviderID: flowProvider(rep.ProviderID), Executions: []keycloak.FlowExecution{}}
+	if rep.Description != nil {
+		flow.Description = *rep.Description
+	}
+	var executions []keycloak.AuthenticationExecutionExportRepresentation
+	if rep.AuthenticationExecutions != nil {
+		executions = append(executions, *rep.AuthenticationExecutions...)
+	}
+	sort.SliceStable(executions, func(i, j int) bool {
+		return executions[i].Priority != nil && executions[j].Priority != nil && *executions[i].Priority < *executions[j].Priority
+	})
+
+	for _, e := range executions {
+		var execution keycloak.FlowExecution
+		if e.Requirement != nil {
+			execution.Requirement = *e.Requirement
+		}
+		switch {
+		case e.FlowAlias != nil:
+			var sub, err = desiredFlow(*e.FlowAlias, flows, configs, visited)
+			if err != nil {
+				return keycloak.AuthenticationFlow{}, err
+			}
+			execution.SubFlow = &sub
+		case e.Authenticator != nil:
+			execution.Authenticator = *e.Authenticator
+		default:
+			return keycloak.AuthenticationFlow{}, missing(KindAuthenticationFlow+" execution", "authenticator")
+		}
+		if e.AuthenticatorConfig != nil {
+			var config, ok = configs[*e.AuthenticatorConfig]
+			if !ok {
+				return keycloak.AuthenticationFlow{}, fmt.Errorf("reconcile: the desired authenticator config %s does not exist", *e.AuthenticatorConfig)
+			}
+			execution.Config = authenticatorConfig(config)
+		}
+		flow.Executions = append(flow.Executions, execution)
+	}
+	return flow, nil
+}
+
+// liveFlow returns the live flow along with its executions, sub flows and authenticator configurations.
+func (p *planner) liveFlow(rep keycloak.AuthenticationFlowRepresentation) (keycloak.AuthenticationFlow, error) {
+	var executions, err = p.admin.GetAuthenticationExecutionForFlow(p.accessToken, p.realmName, *rep.Alias)
+	if err != nil {
+		return keycloak.AuthenticationFlow{}, err
+	}
+	return p.liveExecutions(rep, keycloak.AuthenticationExecutionTree(executions))
+}
+
+func (p *planner) liveExecutions(rep keycloak.AuthenticationFlowRepresentation, nodes []keycloak.AuthenticationExecutionNode) (keycloak.AuthenticationFlow, error) {
+	var flow = keycloak.AuthenticationFlow{Alias: *rep.Alias, ProviderID: flowProvider(rep.ProviderID), Executions: []keycloak.FlowExecution{}}
+	if rep.Description != nil {
+		flow.Description = *rep.Description
+	}
+	for _, node := range nodes {
+		var e, execution = node.Execution, keycloak.FlowExecution{}
+		if e.Requirement != nil {
+			execution.Requirement = *e.Requirement
+		}
+		if e.AuthenticationFlow != nil && *e.AuthenticationFlow {
+			var subRep, err = p.admin.GetAuthenticationFlow(p.accessToken, p.realmName, *e.FlowID)
+			if err != nil {
+				return keycloak.AuthenticationFlow{}, err
+			}
+			sub, err := p.liveExecutions(subRep, node.Executions)
+			if err != nil {
+				return keycloak.AuthenticationFlow{}, err
+			}
+			execution.SubFlow = &sub
+		} else if e.ProviderID != nil {
+			execution.Authenticator = *e.ProviderID
+		}
+		if e.AuthenticationConfig != nil {
+			var config, err = p.admin.GetAuthenticatorConfig(p.accessToken, p.realmName, *e.AuthenticationConfig)
+			if err != nil {
+				return keycloak.AuthenticationFlow{}, err
+			}
+			execution.Config = authenticatorConfig(config)
+		}
+		flow.Executions = append(flow.Executions, execution)
+	}
+	return flow, nil
+}
+
+func flowProvider(providerID *string) string {
+	if providerID == nil || *providerID == "" {
+		return keycloak.FlowProviderBasic
+	}
+	return *providerID
+}
+
+func authenticatorConfig(rep keycloak.AuthenticatorConfigRepresentation) *keycloak.FlowAuthenticatorConfig {
+	var config = keycloak.FlowAuthenticatorConfig{Config: map[string]string{}}
+	if rep.Alias != nil {
+		config.Alias = *rep.Alias
+	}
+	if rep.Config != nil {
+		for key, value := range *rep.Config {
+			config.Config[key] = fmt.Sprint(value)
+		}
+	}
+	return &config
+}
+
+// requiredActions plans the required actions, which are registered with their provider id as alias.
+func (p *planner) requiredActions(desired *[]keycloak.RequiredActionProviderRepresentation) error {
+	if desired == nil {
+		return nil
+	}
+	var live = map[string]keycloak.RequiredActionProviderRepresentation{}
+	if p.exists {
+		var actions, err = p.admin.GetRequiredActions(p.accessToken, p.realmName)
+		if err != nil {
+			return err
+		}
+		for _, action := range actions {
+			live[*action.Alias] = action
+		}
+	}
+
+	var ignored = []string{"alias", "name", "providerId"}
+	for _, action := range *desired {
+		if action.Alias == nil {
+			return missing(KindRequiredAction, "alias")
+		}
+		var alias, want = *action.Alias, toFields(action, ignored...)
+		var existing, ok = live[alias]
+		delete(live, alias)
+		if !ok {
+			var providerID, name = alias, alias
+			if action.ProviderID != nil {
+				providerID = *action.ProviderID
+			}
+			if action.Name != nil {
+				name = *action.Name
+			}
+			p.add(ActionCreate, KindRequiredAction, alias, func(a *applier) error {
+				if err := a.admin.RegisterRequiredAction(a.accessToken, a.realmName, providerID, name); err != nil {
+					return err
+				}
+				var rep keycloak.RequiredActionProviderRepresentation
+				decode(want, &rep)
+				return a.admin.UpdateRequiredAction(a.accessToken, a.realmName, alias, rep)
+			})
+			continue
+		}
+		var have = toFields(existing)
+		if drifted := drift(want, have); len(drifted) > 0 {
+			p.update(KindRequiredAction, alias, drifted, func(a *applier) error {
+				var rep keycloak.RequiredActionProviderRepresentation
+				decode(overlay(have, want), &rep)
+				return a.admin.UpdateRequiredAction(a.accessToken, a.realmName, alias, rep)
+			})
+		}
+	}
+	p.deleteAll(KindRequiredAction, sortedNames(live), func(a *applier, alias string) error {
+		return a.admin.DeleteRequiredAction(a.accessToken, a.realmName, alias)
+	})
+	return nil
+}
+
+// sortedNames returns the sorted keys of a map of resources indexed by name.
+func sortedNames(m interface{}) []string {
+	var names []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+	return names
+}
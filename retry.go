@@ -0,0 +1,129 @@
+package keycloak
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default values of the RetryPolicy fields left empty.
+const (
+	DefaultInitialBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+)
+
+var (
+	// DefaultRetryStatusCodes are the statuses retried when RetryPolicy.StatusCodes is empty: those returned
+	// while a Keycloak node or the load balancer in front of it is restarting or overloaded.
+	DefaultRetryStatusCodes = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+	// DefaultRetryMethods are the methods retried when RetryPolicy.Methods is empty. POST is not idempotent,
+	// retrying it could create a resource twice.
+	DefaultRetryMethods = []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}
+)
+
+// RetryPolicy defines how the client retries the requests which fail with a transport error or a retryable
+// status. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, the first attempt included.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. It doubles after each attempt, up to MaxBackoff,
+	// and a random jitter of up to half its value is applied. A Retry-After header overrides it, up to
+	// MaxBackoff as well.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// StatusCodes are the HTTP statuses which are retried.
+	StatusCodes []int
+	// Methods are the HTTP methods which are retried.
+	Methods []string
+}
+
+// TokenRenewer renews the access tokens rejected by Keycloak. It is implemented by *TokenProvider.
+type TokenRenewer interface {
+	RenewToken(rejectedToken string) (string, error)
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if len(p.StatusCodes) == 0 {
+		p.StatusCodes = DefaultRetryStatusCodes
+	}
+	if len(p.Methods) == 0 {
+		p.Methods = DefaultRetryMethods
+	}
+	return p
+}
+
+// retryable reports whether a request sent with method, which got status (0 for a transport error), may be
+// sent again after attempt attempts.
+func (p RetryPolicy) retryable(method string, status int, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	var methodOK = false
+	for _, m := range p.Methods {
+		methodOK = methodOK || m == method
+	}
+	if !methodOK || status == 0 {
+		return methodOK
+	}
+
+	for _, s := range p.StatusCodes {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait before the retry following attempt attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	var backoff = p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// responseBackoff returns the wait before the retry of a request which got a response with the given
+// Retry-After header after attempt attempt. The wait asked by the server is capped at MaxBackoff.
+func (p RetryPolicy) responseBackoff(header string, now time.Time, attempt int) time.Duration {
+	var wait, ok = retryAfter(header, now)
+	if !ok {
+		return p.backoff(attempt)
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,126 @@
+package keycloak
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tokenRenewerMock struct {
+	calls int32
+}
+
+func (m *tokenRenewerMock) RenewToken(rejectedToken string) (string, error) {
+	atomic.AddInt32(&m.calls, 1)
+	return "renewed", nil
+}
+
+func newRetryingClient(t *testing.T, handler http.HandlerFunc) *Client {
+	var server = httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var client, err = NewClient(Config{
+		AddrAPI: server.URL,
+		Timeout: 5 * time.Second,
+		Retry:   RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+	assert.Nil(t, err)
+	return client
+}
+
+func TestRetry(t *testing.T) {
+	var calls int32
+	var client = newRetryingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1)%3 != 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	})
+
+	t.Run("Idempotent method", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		var _, err = client.GetRealms("token")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("POST is not retried", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		var _, err = client.CreateRealm("token", RealmRepresentation{})
+		assert.Equal(t, http.StatusServiceUnavailable, err.(HTTPError).HTTPStatus)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestRetryExhausted(t *testing.T) {
+	var calls int32
+	var client = newRetryingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	var _, err = client.GetRealms("token")
+	assert.Equal(t, http.StatusBadGateway, err.(HTTPError).HTTPStatus)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRenewTokenOn401(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer renewed" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	t.Run("Without token provider", func(t *testing.T) {
+		var _, err = client.CreateRealm("expired", RealmRepresentation{})
+		assert.Equal(t, http.StatusUnauthorized, err.(HTTPError).HTTPStatus)
+	})
+
+	t.Run("With token provider", func(t *testing.T) {
+		var renewer = &tokenRenewerMock{}
+		var _, err = client.WithTokenProvider(renewer).CreateRealm("expired", RealmRepresentation{})
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), renewer.calls)
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var policy = RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}.withDefaults()
+
+	assert.True(t, policy.retryable(http.MethodGet, 0, 1))
+	assert.True(t, policy.retryable(http.MethodPut, http.StatusTooManyRequests, 4))
+	assert.False(t, policy.retryable(http.MethodGet, http.StatusInternalServerError, 1))
+	assert.False(t, policy.retryable(http.MethodPost, http.StatusServiceUnavailable, 1))
+	assert.False(t, policy.retryable(http.MethodGet, http.StatusServiceUnavailable, 5))
+	assert.False(t, RetryPolicy{}.withDefaults().retryable(http.MethodGet, 0, 1))
+
+	for attempt := 1; attempt < 10; attempt++ {
+		var backoff = policy.backoff(attempt)
+		assert.True(t, backoff >= 50*time.Millisecond && backoff <= time.Second)
+	}
+	assert.True(t, policy.backoff(3) >= 200*time.Millisecond)
+
+	var now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var wait, ok = retryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, wait)
+	wait, ok = retryAfter("Wed, 01 Jan 2020 00:00:30 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, wait)
+	_, ok = retryAfter("soon", now)
+	assert.False(t, ok)
+
+	assert.Equal(t, time.Second, policy.responseBackoff("3600", now, 1))
+	assert.Equal(t, 30*time.Second, RetryPolicy{MaxBackoff: time.Minute}.responseBackoff("30", now, 1))
+	var backoff = policy.responseBackoff("soon", now, 1)
+	assert.True(t, backoff >= 50*time.Millisecond && backoff <= 100*time.Millisecond)
+}
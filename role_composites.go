@@ -0,0 +1,138 @@
+package keycloak
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ResolveComposites looks up the roles named in composites: the realm roles by name and the client roles by
+// client id (not the id of the client) and name, in the order of the client ids. It fails with ErrNotFound if a client or a role does not exist.
+func ResolveComposites(client KeycloakAdmin, accessToken string, realmName string, composites RoleRepresentationComposites) ([]RoleRepresentation, error) {
+	var roles = []RoleRepresentation{}
+	if composites.Realm != nil {
+		for _, name := range *composites.Realm {
+			var role, err = client.GetRoleByName(accessToken, realmName, name)
+			if err != nil {
+				return nil, err
+			}
+			roles = append(roles, role)
+		}
+	}
+	if composites.Client == nil {
+		return roles, nil
+	}
+
+	var clientIDs []string
+	for clientID := range *composites.Client {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+	for _, clientID := range clientIDs {
+		var names, err = roleNames((*composites.Client)[clientID])
+		if err != nil {
+			return nil, err
+		}
+		c, err := GetClientByClientID(client, accessToken, realmName, clientID)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			var role, err = client.GetClientRole(accessToken, realmName, *c.ID, name)
+			if err != nil {
+				return nil, err
+			}
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// SetRoleComposites makes the roles named in composites the only roles directly included in the role, adding and
+// removing composites as needed. See ResolveComposites for the naming of the roles.
+func SetRoleComposites(client KeycloakAdmin, accessToken string, realmName, roleID string, composites RoleRepresentationComposites) error {
+	var wanted, err = ResolveComposites(client, accessToken, realmName, composites)
+	if err != nil {
+		return err
+	}
+	current, err := client.GetRoleComposites(accessToken, realmName, roleID)
+	if err != nil {
+		return err
+	}
+
+	var toAdd = rolesNotIn(wanted, current)
+	var toDelete = rolesNotIn(current, wanted)
+	if len(toAdd) > 0 {
+		if err = client.AddRoleComposites(accessToken, realmName, roleID, toAdd); err != nil {
+			return err
+		}
+	}
+	if len(toDelete) > 0 {
+		return client.DeleteRoleComposites(accessToken, realmName, roleID, toDelete)
+	}
+	return nil
+}
+
+// GetEffectiveRoleComposites returns the roles included in the composite role, directly or through other
+// composite roles.
+func GetEffectiveRoleComposites(client KeycloakAdmin, accessToken string, realmName, roleID string) ([]RoleRepresentation, error) {
+	var res = []RoleRepresentation{}
+	var visited = map[string]bool{roleID: true}
+	var queue = []string{roleID}
+	for len(queue) > 0 {
+		var composites, err = client.GetRoleComposites(accessToken, realmName, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+
+		for _, role := range composites {
+			if role.ID == nil || visited[*role.ID] {
+				continue
+			}
+			visited[*role.ID] = true
+			res = append(res, role)
+			if role.Composite != nil && *role.Composite {
+				queue = append(queue, *role.ID)
+			}
+		}
+	}
+	return res, nil
+}
+
+// roleNames accepts the role names of a RoleRepresentationComposites client entry, either built in Go as a
+// []string or unmarshalled from JSON as a []interface{}.
+func roleNames(value interface{}) ([]string, error) {
+	switch names := value.(type) {
+	case []string:
+		return names, nil
+	case []interface{}:
+		var res []string
+		for _, name := range names {
+			var s, ok = name.(string)
+			if !ok {
+				return nil, newError(MsgErrInvalidParam, Representation, fmt.Errorf("role name %v is not a string", name))
+			}
+			res = append(res, s)
+		}
+		return res, nil
+	default:
+		return nil, newError(MsgErrInvalidParam, Representation, fmt.Errorf("role names %v are not a list", value))
+	}
+}
+
+// rolesNotIn returns the roles which are not in others, comparing their IDs.
+func rolesNotIn(roles []RoleRepresentation, others []RoleRepresentation) []RoleRepresentation {
+	var ids = map[string]bool{}
+	for _, role := range others {
+		if role.ID != nil {
+			ids[*role.ID] = true
+		}
+	}
+	var res []RoleRepresentation
+	for _, role := range roles {
+		if role.ID != nil && !ids[*role.ID] {
+			res = append(res, role)
+		}
+	}
+	return res
+}
@@ -6,9 +6,18 @@ import (
 )
 
 const (
-	rolePath       = "/auth/admin/realms/:realm/roles"
-	roleByIDPath   = "/auth/admin/realms/:realm/roles-by-id/:id"
-	clientRolePath = "/auth/admin/realms/:realm/clients/:id/roles"
+	rolePath                 = "/auth/admin/realms/:realm/roles"
+	roleByNamePath           = rolePath + "/:name"
+	roleUsersPath            = roleByNamePath + "/users"
+	roleGroupsPath           = roleByNamePath + "/groups"
+	roleByIDPath             = "/auth/admin/realms/:realm/roles-by-id/:id"
+	roleCompositesPath       = roleByIDPath + "/composites"
+	roleRealmCompositesPath  = roleCompositesPath + "/realm"
+	roleClientCompositesPath = roleCompositesPath + "/clients/:clientId"
+	clientRolePath           = "/auth/admin/realms/:realm/clients/:id/roles"
+	clientRoleByNamePath     = clientRolePath + "/:name"
+	clientRoleUsersPath      = clientRoleByNamePath + "/users"
+	clientRoleGroupsPath     = clientRoleByNamePath + "/groups"
 )
 
 // GetClientRoles gets all roles for the realm or client
@@ -23,6 +32,47 @@ func (c *Client) CreateClientRole(accessToken string, realmName, clientID string
 	return c.post(accessToken, nil, url.Path(clientRolePath), url.Param("realm", realmName), url.Param("id", clientID), body.JSON(role))
 }
 
+// GetClientRole gets the client role with the given name.
+func (c *Client) GetClientRole(accessToken string, realmName, idClient, roleName string) (RoleRepresentation, error) {
+	var resp = RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(clientRoleByNamePath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("name", roleName))
+	return resp, err
+}
+
+// UpdateClientRole updates the name, the description and the attributes of the client role.
+func (c *Client) UpdateClientRole(accessToken string, realmName, idClient, roleName string, role RoleRepresentation) error {
+	return c.put(accessToken, url.Path(clientRoleByNamePath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("name", roleName), body.JSON(role))
+}
+
+// DeleteClientRole deletes the client role with the given name.
+func (c *Client) DeleteClientRole(accessToken string, realmName, idClient, roleName string) error {
+	return c.delete(accessToken, url.Path(clientRoleByNamePath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("name", roleName))
+}
+
+// GetClientRoleUsers returns the users directly granted the client role, paged according to the query.
+func (c *Client) GetClientRoleUsers(accessToken string, realmName, idClient, roleName string, query PageQuery) ([]UserRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []UserRepresentation{}
+	var plugins = append(query.plugins(), url.Path(clientRoleUsersPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("name", roleName))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetClientRoleGroups returns the groups directly granted the client role, paged according to the query.
+func (c *Client) GetClientRoleGroups(accessToken string, realmName, idClient, roleName string, query PageQuery) ([]GroupRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []GroupRepresentation{}
+	var plugins = append(query.plugins(), url.Path(clientRoleGroupsPath), url.Param("realm", realmName), url.Param("id", idClient), url.Param("name", roleName))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
 // GetRoles gets all roles for the realm or client
 func (c *Client) GetRoles(accessToken string, realmName string) ([]RoleRepresentation, error) {
 	var resp = []RoleRepresentation{}
@@ -36,3 +86,91 @@ func (c *Client) GetRole(accessToken string, realmName string, roleID string) (R
 	var err = c.get(accessToken, &resp, url.Path(roleByIDPath), url.Param("realm", realmName), url.Param("id", roleID))
 	return resp, err
 }
+
+// CreateRole creates a realm role.
+func (c *Client) CreateRole(accessToken string, realmName string, role RoleRepresentation) (string, error) {
+	return c.post(accessToken, nil, url.Path(rolePath), url.Param("realm", realmName), body.JSON(role))
+}
+
+// GetRoleByName gets the realm role with the given name.
+func (c *Client) GetRoleByName(accessToken string, realmName, roleName string) (RoleRepresentation, error) {
+	var resp = RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(roleByNamePath), url.Param("realm", realmName), url.Param("name", roleName))
+	return resp, err
+}
+
+// UpdateRole updates the name, the description and the attributes of the realm role with the given name.
+func (c *Client) UpdateRole(accessToken string, realmName, roleName string, role RoleRepresentation) error {
+	return c.put(accessToken, url.Path(roleByNamePath), url.Param("realm", realmName), url.Param("name", roleName), body.JSON(role))
+}
+
+// UpdateRoleByID updates the name, the description and the attributes of the realm or client role.
+func (c *Client) UpdateRoleByID(accessToken string, realmName, roleID string, role RoleRepresentation) error {
+	return c.put(accessToken, url.Path(roleByIDPath), url.Param("realm", realmName), url.Param("id", roleID), body.JSON(role))
+}
+
+// DeleteRole deletes the realm role with the given name.
+func (c *Client) DeleteRole(accessToken string, realmName, roleName string) error {
+	return c.delete(accessToken, url.Path(roleByNamePath), url.Param("realm", realmName), url.Param("name", roleName))
+}
+
+// DeleteRoleByID deletes the realm or client role.
+func (c *Client) DeleteRoleByID(accessToken string, realmName, roleID string) error {
+	return c.delete(accessToken, url.Path(roleByIDPath), url.Param("realm", realmName), url.Param("id", roleID))
+}
+
+// GetRoleUsers returns the users directly granted the realm role, paged according to the query.
+func (c *Client) GetRoleUsers(accessToken string, realmName, roleName string, query PageQuery) ([]UserRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []UserRepresentation{}
+	var plugins = append(query.plugins(), url.Path(roleUsersPath), url.Param("realm", realmName), url.Param("name", roleName))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetRoleGroups returns the groups directly granted the realm role, paged according to the query.
+func (c *Client) GetRoleGroups(accessToken string, realmName, roleName string, query PageQuery) ([]GroupRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []GroupRepresentation{}
+	var plugins = append(query.plugins(), url.Path(roleGroupsPath), url.Param("realm", realmName), url.Param("name", roleName))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetRoleComposites returns the roles, realm or client ones, directly included in the composite role.
+func (c *Client) GetRoleComposites(accessToken string, realmName, roleID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(roleCompositesPath), url.Param("realm", realmName), url.Param("id", roleID))
+	return resp, err
+}
+
+// GetRealmRoleComposites returns the realm roles included in the composite role.
+func (c *Client) GetRealmRoleComposites(accessToken string, realmName, roleID string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(roleRealmCompositesPath), url.Param("realm", realmName), url.Param("id", roleID))
+	return resp, err
+}
+
+// GetClientRoleComposites returns the roles of the client included in the composite role.
+func (c *Client) GetClientRoleComposites(accessToken string, realmName, roleID, idClient string) ([]RoleRepresentation, error) {
+	var resp = []RoleRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(roleClientCompositesPath), url.Param("realm", realmName), url.Param("id", roleID), url.Param("clientId", idClient))
+	return resp, err
+}
+
+// AddRoleComposites includes roles in the role, making it a composite role. The roles are identified by ID.
+func (c *Client) AddRoleComposites(accessToken string, realmName, roleID string, roles []RoleRepresentation) error {
+	var _, err = c.post(accessToken, nil, url.Path(roleCompositesPath), url.Param("realm", realmName), url.Param("id", roleID), body.JSON(roles))
+	return err
+}
+
+// DeleteRoleComposites removes roles from the composite role. The roles are identified by ID.
+func (c *Client) DeleteRoleComposites(accessToken string, realmName, roleID string, roles []RoleRepresentation) error {
+	return c.delete(accessToken, url.Path(roleCompositesPath), url.Param("realm", realmName), url.Param("id", roleID), body.JSON(roles))
+}
@@ -0,0 +1,33 @@
+package keycloak
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleRequests(t *testing.T) {
+	var requests []string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var content, _ = ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(content))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	}))
+
+	assert.Nil(t, client.UpdateRole("token", "test", "reader", RoleRepresentation{Description: strPtr("Read only")}))
+	assert.Nil(t, client.DeleteRoleComposites("token", "test", "r1", []RoleRepresentation{{ID: strPtr("r2")}}))
+	var _, err = client.GetClientRoleUsers("token", "test", "c1", "manage", PageQuery{Max: 20})
+	assert.Nil(t, err)
+	_, err = client.GetClientRoleComposites("token", "test", "r1", "c1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		`PUT /auth/admin/realms/test/roles/reader {"description":"Read only"}` + "\n",
+		`DELETE /auth/admin/realms/test/roles-by-id/r1/composites [{"id":"r2"}]` + "\n",
+		"GET /auth/admin/realms/test/clients/c1/roles/manage/users?max=20 ",
+		"GET /auth/admin/realms/test/roles-by-id/r1/composites/clients/c1 ",
+	}, requests)
+}
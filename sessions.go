@@ -0,0 +1,105 @@
+package keycloak
+
+import (
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	userSessionsPath              = userIDPath + "/sessions"
+	userOfflineSessionsPath       = userIDPath + "/offline-sessions/:clientId"
+	userLogoutPath                = userIDPath + "/logout"
+	sessionPath                   = realmPath + "/sessions/:session"
+	logoutAllPath                 = realmPath + "/logout-all"
+	clientUserSessionsPath        = clientIDPath + "/user-sessions"
+	clientOfflineSessionsPath     = clientIDPath + "/offline-sessions"
+	clientSessionCountPath        = clientIDPath + "/session-count"
+	clientOfflineSessionCountPath = clientIDPath + "/offline-session-count"
+)
+
+// GetUserSessions returns the active sessions of the user.
+func (c *Client) GetUserSessions(accessToken string, realmName, userID string) ([]UserSessionRepresentation, error) {
+	var resp = []UserSessionRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(userSessionsPath), url.Param("realm", realmName), url.Param("id", userID))
+	return resp, err
+}
+
+// GetUserOfflineSessions returns the offline sessions of the user for the client.
+func (c *Client) GetUserOfflineSessions(accessToken string, realmName, userID, idClient string) ([]UserSessionRepresentation, error) {
+	var resp = []UserSessionRepresentation{}
+	var err = c.get(accessToken, &resp, url.Path(userOfflineSessionsPath), url.Param("realm", realmName), url.Param("id", userID), url.Param("clientId", idClient))
+	return resp, err
+}
+
+// LogoutUser removes all the sessions of the user, including the offline ones.
+func (c *Client) LogoutUser(accessToken string, realmName, userID string) error {
+	var _, err = c.post(accessToken, nil, url.Path(userLogoutPath), url.Param("realm", realmName), url.Param("id", userID))
+	return err
+}
+
+// DeleteSession removes a user session, logging the user out of all the clients of this session.
+func (c *Client) DeleteSession(accessToken string, realmName, sessionID string) error {
+	return c.delete(accessToken, url.Path(sessionPath), url.Param("realm", realmName), url.Param("session", sessionID))
+}
+
+// LogoutAll removes all the user sessions of the realm and pushes a logout to the clients having an admin URL.
+// The result lists the clients which were reached and those which were not.
+func (c *Client) LogoutAll(accessToken string, realmName string) (GlobalRequestResult, error) {
+	var resp = GlobalRequestResult{}
+	var _, err = c.post(accessToken, &resp, url.Path(logoutAllPath), url.Param("realm", realmName))
+	return resp, err
+}
+
+// GetClientSessions returns the user sessions of the client (its user-sessions endpoint), paged according to the
+// query.
+func (c *Client) GetClientSessions(accessToken string, realmName, idClient string, query PageQuery) ([]UserSessionRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []UserSessionRepresentation{}
+	var plugins = append(query.plugins(), url.Path(clientUserSessionsPath), url.Param("realm", realmName), url.Param("id", idClient))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetClientOfflineSessions returns the offline sessions of the client, paged according to the query.
+func (c *Client) GetClientOfflineSessions(accessToken string, realmName, idClient string, query PageQuery) ([]UserSessionRepresentation, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp = []UserSessionRepresentation{}
+	var plugins = append(query.plugins(), url.Path(clientOfflineSessionsPath), url.Param("realm", realmName), url.Param("id", idClient))
+	var err = c.get(accessToken, &resp, plugins...)
+	return resp, err
+}
+
+// GetClientSessionCount returns the number of user sessions of the client.
+func (c *Client) GetClientSessionCount(accessToken string, realmName, idClient string) (int, error) {
+	return c.getCount(accessToken, clientSessionCountPath, realmName, idClient)
+}
+
+// GetClientOfflineSessionCount returns the number of offline sessions of the client.
+func (c *Client) GetClientOfflineSessionCount(accessToken string, realmName, idClient string) (int, error) {
+	return c.getCount(accessToken, clientOfflineSessionCountPath, realmName, idClient)
+}
+
+func (c *Client) getCount(accessToken string, path string, realmName, id string) (int, error) {
+	var resp = struct {
+		Count int `json:"count"`
+	}{}
+	var err = c.get(accessToken, &resp, url.Path(path), url.Param("realm", realmName), url.Param("id", id))
+	return resp.Count, err
+}
+
+// GetStart returns the time the session started.
+func (s UserSessionRepresentation) GetStart() time.Time {
+	return fromMillis(s.Start)
+}
+
+// GetLastAccess returns the time of the last request of the session.
+func (s UserSessionRepresentation) GetLastAccess() time.Time {
+	return fromMillis(s.LastAccess)
+}
@@ -0,0 +1,54 @@
+package keycloak
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessions(t *testing.T) {
+	var requests []string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/admin/realms/test/clients/c1/session-count":
+			_, _ = w.Write([]byte(`{"count":3}`))
+		case "/auth/admin/realms/test/logout-all":
+			_, _ = w.Write([]byte(`{"successRequests":["http://app"],"failedRequests":[]}`))
+		case "/auth/admin/realms/test/users/u1/sessions":
+			_, _ = w.Write([]byte(`[{"id":"s1","start":1583103600000,"lastAccess":1583103660000}]`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	var sessions, err = client.GetUserSessions("token", "test", "u1")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1583103600, 0), sessions[0].GetStart())
+	assert.Equal(t, time.Minute, sessions[0].GetLastAccess().Sub(sessions[0].GetStart()))
+
+	count, err := client.GetClientSessionCount("token", "test", "c1")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	result, err := client.LogoutAll("token", "test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"http://app"}, *result.SuccessRequests)
+
+	_, err = client.GetClientSessions("token", "test", "c1", PageQuery{First: 10, Max: 5})
+	assert.Nil(t, err)
+	assert.Nil(t, client.LogoutUser("token", "test", "u1"))
+	assert.Nil(t, client.DeleteSession("token", "test", "s1"))
+
+	assert.Equal(t, []string{
+		"GET /auth/admin/realms/test/users/u1/sessions",
+		"GET /auth/admin/realms/test/clients/c1/session-count",
+		"POST /auth/admin/realms/test/logout-all",
+		"GET /auth/admin/realms/test/clients/c1/user-sessions?first=10&max=5",
+		"POST /auth/admin/realms/test/users/u1/logout",
+		"DELETE /auth/admin/realms/test/sessions/s1",
+	}, requests)
+}
@@ -0,0 +1,235 @@
+package keycloak
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2"
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	gurl "gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	tokenPath = "/auth/realms/:realm" + tokenEndpointPath
+
+	defaultTokenClientID = "admin-cli"
+)
+
+// OAuth2 grant types supported by the token endpoint.
+const (
+	GrantTypePassword          = "password"
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+// Token and client assertion types used by the token exchange (RFC 8693) and JWT client authentication (RFC 7523).
+const (
+	TokenTypeAccessToken         = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeRefreshToken        = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIDToken             = "urn:ietf:params:oauth:token-type:id_token"
+	ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// TokenRequest holds the parameters sent to the token endpoint. Empty values are not sent,
+// except the ClientID which defaults to admin-cli.
+type TokenRequest struct {
+	GrantType string
+	ClientID  string
+	// ClientSecret authenticates a confidential client with client_secret_post.
+	ClientSecret string
+	// ClientAssertion is a signed JWT authenticating the client (see SignClientAssertion).
+	ClientAssertion string
+	Scope           string
+	// Username and Password are used by the password grant.
+	Username string
+	Password string
+	// RefreshToken is used by the refresh_token grant.
+	RefreshToken string
+	// SubjectToken, SubjectTokenType, RequestedTokenType, Audience, RequestedSubject and
+	// RequestedIssuer are used by the token exchange grant.
+	SubjectToken       string
+	SubjectTokenType   string
+	RequestedTokenType string
+	Audience           string
+	RequestedSubject   string
+	RequestedIssuer    string
+}
+
+// TokenResponse is the answer of the token endpoint.
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
+	IDToken          string `json:"id_token,omitempty"`
+	TokenType        string `json:"token_type,omitempty"`
+	IssuedTokenType  string `json:"issued_token_type,omitempty"`
+	NotBeforePolicy  int64  `json:"not-before-policy,omitempty"`
+	SessionState     string `json:"session_state,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+}
+
+// GetToken returns a valid token from keycloak, obtained with the password grant of the admin-cli client.
+func (c *Client) GetToken(realm string, username string, password string) (string, error) {
+	var resp, err = c.RequestToken(realm, TokenRequest{
+		GrantType: GrantTypePassword,
+		Username:  username,
+		Password:  password,
+	})
+	return resp.AccessToken, err
+}
+
+// GetTokenWithClientCredentials returns a token of the service account of the client, authenticated with its secret.
+func (c *Client) GetTokenWithClientCredentials(realm string, clientID string, clientSecret string) (TokenResponse, error) {
+	return c.RequestToken(realm, TokenRequest{
+		GrantType:    GrantTypeClientCredentials,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	})
+}
+
+// GetTokenWithClientAssertion returns a token of the service account of the client, authenticated with a signed JWT.
+func (c *Client) GetTokenWithClientAssertion(realm string, clientID string, clientAssertion string) (TokenResponse, error) {
+	return c.RequestToken(realm, TokenRequest{
+		GrantType:       GrantTypeClientCredentials,
+		ClientID:        clientID,
+		ClientAssertion: clientAssertion,
+	})
+}
+
+// RefreshToken obtains a new token using a refresh token. The client credentials of tokenReq, if any, are
+// sent along.
+func (c *Client) RefreshToken(realm string, refreshToken string, tokenReq TokenRequest) (TokenResponse, error) {
+	tokenReq.GrantType = GrantTypeRefreshToken
+	tokenReq.RefreshToken = refreshToken
+	return c.RequestToken(realm, tokenReq)
+}
+
+// ExchangeToken exchanges the subject token of tokenReq for another token (RFC 8693). The subject token type
+// defaults to an access token.
+func (c *Client) ExchangeToken(realm string, tokenReq TokenRequest) (TokenResponse, error) {
+	tokenReq.GrantType = GrantTypeTokenExchange
+	if tokenReq.SubjectTokenType == "" {
+		tokenReq.SubjectTokenType = TokenTypeAccessToken
+	}
+	return c.RequestToken(realm, tokenReq)
+}
+
+// RequestToken sends tokenReq to the token endpoint of the realm.
+func (c *Client) RequestToken(realm string, tokenReq TokenRequest) (TokenResponse, error) {
+	var req = c.httpClient.Post()
+	req = req.Use(gurl.Path(tokenPath))
+	req = req.Use(gurl.Param("realm", realm))
+	req = c.applyContext(req)
+	return requestToken(req, tokenReq)
+}
+
+// requestToken posts tokenReq as an url-encoded form using req.
+func requestToken(req *gentleman.Request, tokenReq TokenRequest) (TokenResponse, error) {
+	var resp = TokenResponse{}
+
+	req = req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	req = req.Use(body.String(tokenReq.form().Encode()))
+
+	var httpResp *gentleman.Response
+	{
+		var err error
+		httpResp, err = req.Do()
+		if err != nil {
+			return resp, newError(MsgErrCannotObtain, TokenMsg, err)
+		}
+	}
+	defer httpResp.Close()
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 400 {
+		return resp, newHTTPError(httpResp)
+	}
+
+	if err := httpResp.JSON(&resp); err != nil {
+		return resp, newError(MsgErrCannotUnmarshal, Response, err)
+	}
+	if resp.AccessToken == "" {
+		return resp, newError(MsgErrMissingParam, AccessToken, nil)
+	}
+	return resp, nil
+}
+
+func (r TokenRequest) form() url.Values {
+	var form = url.Values{}
+	var setWhenNotEmpty = func(key, value string) {
+		if value != "" {
+			form.Set(key, value)
+		}
+	}
+
+	var clientID = r.ClientID
+	if clientID == "" {
+		clientID = defaultTokenClientID
+	}
+	form.Set("grant_type", r.GrantType)
+	form.Set("client_id", clientID)
+	setWhenNotEmpty("client_secret", r.ClientSecret)
+	if r.ClientAssertion != "" {
+		form.Set("client_assertion_type", ClientAssertionTypeJWTBearer)
+		form.Set("client_assertion", r.ClientAssertion)
+	}
+	setWhenNotEmpty("scope", r.Scope)
+	setWhenNotEmpty("username", r.Username)
+	setWhenNotEmpty("password", r.Password)
+	setWhenNotEmpty("refresh_token", r.RefreshToken)
+	setWhenNotEmpty("subject_token", r.SubjectToken)
+	setWhenNotEmpty("subject_token_type", r.SubjectTokenType)
+	setWhenNotEmpty("requested_token_type", r.RequestedTokenType)
+	setWhenNotEmpty("audience", r.Audience)
+	setWhenNotEmpty("requested_subject", r.RequestedSubject)
+	setWhenNotEmpty("requested_issuer", r.RequestedIssuer)
+	return form
+}
+
+// SignClientAssertion returns a JWT signed with RS256, usable as client assertion by a client configured with
+// the "Signed JWT" authenticator. audience is the URL of the realm, e.g. http://localhost:8080/auth/realms/master
+func SignClientAssertion(clientID string, audience string, key *rsa.PrivateKey, lifetime time.Duration) (string, error) {
+	var jti = make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", newError(MsgErrCannotCreate, ClientAssertion, err)
+	}
+
+	var now = time.Now()
+	var header = map[string]string{"alg": "RS256", "typ": "JWT"}
+	var claims = map[string]interface{}{
+		"iss": clientID,
+		"sub": clientID,
+		"aud": audience,
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(lifetime).Unix(),
+	}
+
+	var signingInput string
+	{
+		var encodedHeader, err = json.Marshal(header)
+		if err != nil {
+			return "", newError(MsgErrCannotMarshal, ClientAssertion, err)
+		}
+		encodedClaims, err := json.Marshal(claims)
+		if err != nil {
+			return "", newError(MsgErrCannotMarshal, ClientAssertion, err)
+		}
+		signingInput = base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+	}
+
+	var digest = sha256.Sum256([]byte(signingInput))
+	var signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", newError(MsgErrCannotCreate, ClientAssertion, err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,191 @@
+package keycloak
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2"
+	"gopkg.in/h2non/gentleman.v2/plugins/timeout"
+)
+
+const (
+	tokenEndpointPath = "/protocol/openid-connect/token"
+
+	// tokenExpiryMargin is how long before its expiry a cached token is renewed.
+	tokenExpiryMargin = 10 * time.Second
+
+	// tokenRetryDelay is how long a cached token is served after a failed renewal before the next attempt.
+	tokenRetryDelay = 2 * time.Second
+)
+
+// TokenProvider obtains access tokens from the token endpoint of Config.AddrTokenProvider and caches them.
+// A cached token is served until shortly before it expires (or until Config.CacheTTL elapses, if shorter),
+// then it is renewed with the refresh_token grant, falling back to the initial grant. If Keycloak
+// cannot be reached, the cached token is still served during Config.ErrorTolerance, but never beyond its
+// expiry, the renewal being retried every few seconds.
+// A TokenProvider is safe for concurrent use: callers asking for a token while it is being renewed
+// wait for the pending renewal instead of issuing their own.
+type TokenProvider struct {
+	httpClient     *gentleman.Client
+	tokenReq       TokenRequest
+	cacheTTL       time.Duration
+	errorTolerance time.Duration
+	now            func() time.Time
+
+	mutex   sync.Mutex
+	token   *cachedToken
+	pending *tokenCall
+}
+
+// cachedToken is a token obtained from the token endpoint along with its validity.
+type cachedToken struct {
+	accessToken      string
+	refreshToken     string
+	renewAt          time.Time
+	retryAt          time.Time
+	expiresAt        time.Time
+	refreshExpiresAt time.Time
+}
+
+// tokenCall is a token renewal shared by all the callers waiting for it.
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// NewTokenProvider returns a token provider which authenticates with the given username and password
+// against the realm located at config.AddrTokenProvider (e.g. http://localhost:8080/auth/realms/master).
+func NewTokenProvider(config Config, username string, password string) (*TokenProvider, error) {
+	return NewTokenProviderWithRequest(config, TokenRequest{
+		GrantType: GrantTypePassword,
+		Username:  username,
+		Password:  password,
+	})
+}
+
+// NewTokenProviderWithRequest returns a token provider which obtains its tokens by sending tokenReq, e.g. a
+// client_credentials grant, to the realm located at config.AddrTokenProvider. As Keycloak rejects client
+// assertions which are used twice, tokenReq should authenticate the client with its secret.
+func NewTokenProviderWithRequest(config Config, tokenReq TokenRequest) (*TokenProvider, error) {
+	var uToken *url.URL
+	{
+		var err error
+		uToken, err = url.Parse(strings.TrimSuffix(config.AddrTokenProvider, "/") + tokenEndpointPath)
+		if err != nil {
+			return nil, newError(MsgErrCannotParse, TokenProviderURL, err)
+		}
+	}
+
+	var httpClient = gentleman.New()
+	{
+		httpClient = httpClient.URL(uToken.String())
+		httpClient = httpClient.Use(timeout.Request(config.Timeout))
+	}
+
+	return &TokenProvider{
+		httpClient:     httpClient,
+		tokenReq:       tokenReq,
+		cacheTTL:       config.CacheTTL,
+		errorTolerance: config.ErrorTolerance,
+		now:            time.Now,
+	}, nil
+}
+
+// ProvideToken returns a valid access token, renewing it if needed.
+func (tp *TokenProvider) ProvideToken() (string, error) {
+	tp.mutex.Lock()
+	if tp.token != nil && (tp.now().Before(tp.token.renewAt) || tp.now().Before(tp.token.retryAt)) {
+		var accessToken = tp.token.accessToken
+		tp.mutex.Unlock()
+		return accessToken, nil
+	}
+
+	var call = tp.pending
+	if call != nil {
+		tp.mutex.Unlock()
+		<-call.done
+		return call.token, call.err
+	}
+
+	call = &tokenCall{done: make(chan struct{})}
+	tp.pending = call
+	var current = tp.token
+	tp.mutex.Unlock()
+
+	var resp, err = tp.requestNewToken(current)
+
+	tp.mutex.Lock()
+	var now = tp.now()
+	switch {
+	case err == nil:
+		tp.token = newCachedToken(resp, now, tp.cacheTTL)
+		call.token = tp.token.accessToken
+	case current != nil && now.Before(current.renewAt.Add(tp.errorTolerance)) && now.Before(current.expiresAt):
+		current.retryAt = now.Add(tokenRetryDelay)
+		if current.retryAt.After(current.expiresAt) {
+			current.retryAt = current.expiresAt
+		}
+		call.token = current.accessToken
+	default:
+		call.err = err
+	}
+	tp.pending = nil
+	tp.mutex.Unlock()
+	close(call.done)
+
+	return call.token, call.err
+}
+
+// RenewToken returns a new access token to replace rejectedToken, which Keycloak refused, e.g. because the
+// session was logged out. If the cached token has already been replaced, it is returned as is.
+func (tp *TokenProvider) RenewToken(rejectedToken string) (string, error) {
+	tp.mutex.Lock()
+	if tp.token != nil && tp.token.accessToken == rejectedToken {
+		tp.token.accessToken = ""
+		tp.token.renewAt, tp.token.retryAt, tp.token.expiresAt = time.Time{}, time.Time{}, time.Time{}
+	}
+	tp.mutex.Unlock()
+
+	return tp.ProvideToken()
+}
+
+// requestNewToken uses the refresh token of the current token if it is still valid, the initial grant otherwise.
+func (tp *TokenProvider) requestNewToken(current *cachedToken) (TokenResponse, error) {
+	if current != nil && current.refreshToken != "" && tp.now().Before(current.refreshExpiresAt) {
+		var refreshReq = TokenRequest{
+			GrantType:    GrantTypeRefreshToken,
+			ClientID:     tp.tokenReq.ClientID,
+			ClientSecret: tp.tokenReq.ClientSecret,
+			Scope:        tp.tokenReq.Scope,
+			RefreshToken: current.refreshToken,
+		}
+		var resp, err = requestToken(tp.httpClient.Post(), refreshReq)
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	return requestToken(tp.httpClient.Post(), tp.tokenReq)
+}
+
+func newCachedToken(resp TokenResponse, now time.Time, cacheTTL time.Duration) *cachedToken {
+	var lifespan = time.Duration(resp.ExpiresIn) * time.Second
+	var validity = lifespan - tokenExpiryMargin
+	if validity < lifespan/2 {
+		validity = lifespan / 2
+	}
+	if cacheTTL > 0 && cacheTTL < validity {
+		validity = cacheTTL
+	}
+
+	return &cachedToken{
+		accessToken:      resp.AccessToken,
+		refreshToken:     resp.RefreshToken,
+		renewAt:          now.Add(validity),
+		expiresAt:        now.Add(lifespan),
+		refreshExpiresAt: now.Add(time.Duration(resp.RefreshExpiresIn) * time.Second),
+	}
+}
@@ -0,0 +1,182 @@
+package keycloak
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tokenEndpointMock struct {
+	calls      int32
+	grantTypes []string
+	failing    bool
+	delay      time.Duration
+	mutex      sync.Mutex
+}
+
+func (m *tokenEndpointMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var call = atomic.AddInt32(&m.calls, 1)
+	_ = r.ParseForm()
+	m.mutex.Lock()
+	m.grantTypes = append(m.grantTypes, r.PostForm.Get("grant_type"))
+	var failing = m.failing
+	m.mutex.Unlock()
+
+	time.Sleep(m.delay)
+	if failing {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":60,"refresh_token":"refresh-%d","refresh_expires_in":1800}`, call, call)
+}
+
+func newTestTokenProvider(t *testing.T, mock *tokenEndpointMock, config Config) (*TokenProvider, *time.Time) {
+	var server = httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	config.AddrTokenProvider = server.URL + "/auth/realms/master"
+	config.Timeout = 5 * time.Second
+	var tp, err = NewTokenProvider(config, "admin", "admin")
+	assert.Nil(t, err)
+
+	var now = time.Now()
+	tp.now = func() time.Time { return now }
+	return tp, &now
+}
+
+func TestTokenProviderCache(t *testing.T) {
+	var mock = &tokenEndpointMock{}
+	var tp, now = newTestTokenProvider(t, mock, Config{})
+
+	var token, err = tp.ProvideToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+
+	*now = now.Add(30 * time.Second)
+	token, err = tp.ProvideToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+
+	// Token expires in 60s: it is renewed 10s before with the refresh token
+	*now = now.Add(21 * time.Second)
+	token, err = tp.ProvideToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, []string{"password", "refresh_token"}, mock.grantTypes)
+}
+
+func TestTokenProviderCacheTTL(t *testing.T) {
+	var mock = &tokenEndpointMock{}
+	var tp, now = newTestTokenProvider(t, mock, Config{CacheTTL: 5 * time.Second})
+
+	var token, _ = tp.ProvideToken()
+	assert.Equal(t, "token-1", token)
+
+	*now = now.Add(6 * time.Second)
+	token, _ = tp.ProvideToken()
+	assert.Equal(t, "token-2", token)
+}
+
+func TestTokenProviderErrorTolerance(t *testing.T) {
+	var mock = &tokenEndpointMock{}
+	var tp, now = newTestTokenProvider(t, mock, Config{ErrorTolerance: 5 * time.Second})
+
+	var token, err = tp.ProvideToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+
+	mock.failing = true
+
+	t.Run("Within error tolerance", func(t *testing.T) {
+		*now = now.Add(52 * time.Second)
+		token, err = tp.ProvideToken()
+		assert.Nil(t, err)
+		assert.Equal(t, "token-1", token)
+	})
+
+	t.Run("Beyond error tolerance", func(t *testing.T) {
+		*now = now.Add(5 * time.Second)
+		_, err = tp.ProvideToken()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Keycloak is back", func(t *testing.T) {
+		mock.failing = false
+		token, err = tp.ProvideToken()
+		assert.Nil(t, err)
+		assert.NotEqual(t, "token-1", token)
+	})
+}
+
+func TestTokenProviderErrorToleranceCappedAtExpiry(t *testing.T) {
+	var mock = &tokenEndpointMock{}
+	var tp, now = newTestTokenProvider(t, mock, Config{ErrorTolerance: time.Minute})
+
+	var token, _ = tp.ProvideToken()
+	assert.Equal(t, "token-1", token)
+	mock.failing = true
+
+	// The renewal fails: the token is served and the renewal is only retried after a delay
+	*now = now.Add(55 * time.Second)
+	token, err := tp.ProvideToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+	var calls = atomic.LoadInt32(&mock.calls)
+	*now = now.Add(time.Second)
+	token, _ = tp.ProvideToken()
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, calls, atomic.LoadInt32(&mock.calls))
+
+	*now = now.Add(2 * time.Second)
+	token, _ = tp.ProvideToken()
+	assert.Equal(t, "token-1", token)
+	assert.True(t, atomic.LoadInt32(&mock.calls) > calls)
+
+	// The token expired, although the error tolerance did not elapse
+	*now = now.Add(3 * time.Second)
+	_, err = tp.ProvideToken()
+	assert.NotNil(t, err)
+}
+
+func TestTokenProviderConcurrentCallers(t *testing.T) {
+	var mock = &tokenEndpointMock{delay: 100 * time.Millisecond}
+	var tp, _ = newTestTokenProvider(t, mock, Config{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var token, err = tp.ProvideToken()
+			assert.Nil(t, err)
+			assert.Equal(t, "token-1", token)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mock.calls))
+}
+
+func TestTokenProviderRenewToken(t *testing.T) {
+	var mock = &tokenEndpointMock{}
+	var tp, _ = newTestTokenProvider(t, mock, Config{})
+
+	var token, _ = tp.ProvideToken()
+	assert.Equal(t, "token-1", token)
+
+	token, err := tp.RenewToken("token-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "token-2", token)
+
+	// The rejected token was already replaced
+	token, err = tp.RenewToken("token-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&mock.calls))
+}
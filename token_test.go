@@ -0,0 +1,71 @@
+package keycloak
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestToken(t *testing.T) {
+	var form url.Values
+	var path string
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.PostForm
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"at","expires_in":300,"refresh_token":"rt","refresh_expires_in":1800,"id_token":"it","scope":"openid email"}`))
+	}))
+
+	t.Run("Password grant escapes the form values", func(t *testing.T) {
+		var token, err = client.GetToken("master", "admin", "p&ss=word")
+		assert.Nil(t, err)
+		assert.Equal(t, "at", token)
+		assert.Equal(t, "/auth/realms/master/protocol/openid-connect/token", path)
+		assert.Equal(t, "p&ss=word", form.Get("password"))
+		assert.Equal(t, "admin-cli", form.Get("client_id"))
+	})
+
+	t.Run("Client credentials", func(t *testing.T) {
+		var resp, err = client.GetTokenWithClientCredentials("myrealm", "backend", "secret")
+		assert.Nil(t, err)
+		assert.Equal(t, TokenResponse{AccessToken: "at", ExpiresIn: 300, RefreshToken: "rt", RefreshExpiresIn: 1800, IDToken: "it", Scope: "openid email"}, resp)
+		assert.Equal(t, GrantTypeClientCredentials, form.Get("grant_type"))
+		assert.Equal(t, "backend", form.Get("client_id"))
+		assert.Equal(t, "secret", form.Get("client_secret"))
+		assert.Equal(t, "", form.Get("username"))
+	})
+
+	t.Run("Token exchange", func(t *testing.T) {
+		var _, err = client.ExchangeToken("myrealm", TokenRequest{ClientID: "gateway", ClientSecret: "secret", SubjectToken: "subject", Audience: "backend"})
+		assert.Nil(t, err)
+		assert.Equal(t, GrantTypeTokenExchange, form.Get("grant_type"))
+		assert.Equal(t, TokenTypeAccessToken, form.Get("subject_token_type"))
+		assert.Equal(t, "backend", form.Get("audience"))
+	})
+}
+
+func TestSignClientAssertion(t *testing.T) {
+	var key, err = rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	assertion, err := SignClientAssertion("backend", "http://localhost:8080/auth/realms/master", key, time.Minute)
+	assert.Nil(t, err)
+
+	var parts = strings.Split(assertion, ".")
+	assert.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.Nil(t, err)
+	var digest = sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.Nil(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+}
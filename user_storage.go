@@ -0,0 +1,110 @@
+package keycloak
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/headers"
+	"gopkg.in/h2non/gentleman.v2/plugins/query"
+	gurl "gopkg.in/h2non/gentleman.v2/plugins/url"
+)
+
+const (
+	userStoragePath               = "/auth/admin/realms/:realm/user-storage/:id"
+	userStorageSyncPath           = userStoragePath + "/sync"
+	userStorageRemoveImportedPath = userStoragePath + "/remove-imported-users"
+	userStorageUnlinkPath         = userStoragePath + "/unlink-users"
+	userStorageMapperSyncPath     = userStoragePath + "/mappers/:mapperId/sync"
+	testLDAPConnectionPath        = "/auth/admin/realms/:realm/testLDAPConnection"
+)
+
+// Directions of the synchronisation of a user storage mapper.
+const (
+	SyncDirectionFedToKeycloak = "fedToKeycloak"
+	SyncDirectionKeycloakToFed = "keycloakToFed"
+)
+
+// Actions of TestLDAPConnection.
+const (
+	LDAPTestConnection     = "testConnection"
+	LDAPTestAuthentication = "testAuthentication"
+)
+
+// LDAPConnectionTest describes the connection tested by TestLDAPConnection. When ComponentID is the id of an
+// existing provider and BindCredential is left to the masked value returned by Keycloak, the stored credential
+// is used.
+type LDAPConnectionTest struct {
+	// Action is LDAPTestConnection, to check the server is reachable, or LDAPTestAuthentication, to check the
+	// bind DN and credential too.
+	Action            string
+	ConnectionURL     string
+	BindDN            string
+	BindCredential    string
+	UseTruststoreSPI  string
+	ConnectionTimeout time.Duration
+	ComponentID       string
+	StartTLS          bool
+}
+
+func (t LDAPConnectionTest) form() url.Values {
+	var form = url.Values{}
+	form.Set("action", t.Action)
+	form.Set("connectionUrl", t.ConnectionURL)
+	form.Set("bindDn", t.BindDN)
+	form.Set("bindCredential", t.BindCredential)
+	form.Set("useTruststoreSpi", t.UseTruststoreSPI)
+	if t.ConnectionTimeout > 0 {
+		form.Set("connectionTimeout", strconv.FormatInt(int64(t.ConnectionTimeout/time.Millisecond), 10))
+	}
+	form.Set("componentId", t.ComponentID)
+	form.Set("startTls", strconv.FormatBool(t.StartTLS))
+	return form
+}
+
+// TriggerFullSync imports or updates all the users of the user storage provider, e.g. an LDAP provider.
+func (c *Client) TriggerFullSync(accessToken string, realmName, providerID string) (SynchronizationResult, error) {
+	return c.syncUserStorage(accessToken, realmName, providerID, "triggerFullSync")
+}
+
+// TriggerChangedUsersSync imports or updates the users of the user storage provider changed since the last sync.
+func (c *Client) TriggerChangedUsersSync(accessToken string, realmName, providerID string) (SynchronizationResult, error) {
+	return c.syncUserStorage(accessToken, realmName, providerID, "triggerChangedUsersSync")
+}
+
+func (c *Client) syncUserStorage(accessToken string, realmName, providerID, action string) (SynchronizationResult, error) {
+	var resp = SynchronizationResult{}
+	var _, err = c.post(accessToken, &resp, gurl.Path(userStorageSyncPath), gurl.Param("realm", realmName), gurl.Param("id", providerID),
+		query.Add("action", action))
+	return resp, err
+}
+
+// RemoveImportedUsers deletes the users imported from the user storage provider.
+func (c *Client) RemoveImportedUsers(accessToken string, realmName, providerID string) error {
+	var _, err = c.post(accessToken, nil, gurl.Path(userStorageRemoveImportedPath), gurl.Param("realm", realmName), gurl.Param("id", providerID))
+	return err
+}
+
+// UnlinkUsers turns the users imported from the user storage provider into local users.
+func (c *Client) UnlinkUsers(accessToken string, realmName, providerID string) error {
+	var _, err = c.post(accessToken, nil, gurl.Path(userStorageUnlinkPath), gurl.Param("realm", realmName), gurl.Param("id", providerID))
+	return err
+}
+
+// SyncUserStorageMapper synchronises the data handled by a mapper of the user storage provider, e.g. the groups
+// of an LDAP group mapper, in the given direction, one of the SyncDirection constants.
+func (c *Client) SyncUserStorageMapper(accessToken string, realmName, providerID, mapperID, direction string) (SynchronizationResult, error) {
+	var resp = SynchronizationResult{}
+	var _, err = c.post(accessToken, &resp, gurl.Path(userStorageMapperSyncPath), gurl.Param("realm", realmName), gurl.Param("id", providerID),
+		gurl.Param("mapperId", mapperID), query.Add("direction", direction))
+	return resp, err
+}
+
+// TestLDAPConnection checks that Keycloak can connect, and possibly bind, to an LDAP server. It fails with an
+// HTTPError of status 400 if it cannot.
+func (c *Client) TestLDAPConnection(accessToken string, realmName string, test LDAPConnectionTest) error {
+	var _, err = c.post(accessToken, nil, gurl.Path(testLDAPConnectionPath), gurl.Param("realm", realmName),
+		body.String(test.form().Encode()), headers.Set("Content-Type", "application/x-www-form-urlencoded"))
+	return err
+}
@@ -0,0 +1,87 @@
+package keycloak
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerFullSync(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/auth/admin/realms/test/user-storage/ldap1/sync", r.URL.Path)
+		assert.Equal(t, "triggerFullSync", r.URL.Query().Get("action"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"added":3,"updated":1,"removed":0,"failed":0,"ignored":false,"status":"3 imported users, 1 updated users"}`))
+	}))
+
+	var result, err = client.TriggerFullSync("token", "test", "ldap1")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), *result.Added)
+	assert.Equal(t, int32(1), *result.Updated)
+}
+
+func TestTestLDAPConnection(t *testing.T) {
+	var client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/test/testLDAPConnection", r.URL.Path)
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, LDAPTestAuthentication, r.PostForm.Get("action"))
+		assert.Equal(t, "ldaps://ldap.example.com", r.PostForm.Get("connectionUrl"))
+		assert.Equal(t, "5000", r.PostForm.Get("connectionTimeout"))
+		assert.Equal(t, "false", r.PostForm.Get("startTls"))
+		if r.PostForm.Get("bindCredential") != "secret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errorMessage":"LDAP test error"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var test = LDAPConnectionTest{
+		Action:            LDAPTestAuthentication,
+		ConnectionURL:     "ldaps://ldap.example.com",
+		BindDN:            "cn=admin,dc=example,dc=com",
+		BindCredential:    "secret",
+		ConnectionTimeout: 5 * time.Second,
+	}
+	assert.Nil(t, client.TestLDAPConnection("token", "test", test))
+	test.BindCredential = "wrong"
+	assert.True(t, errors.Is(client.TestLDAPConnection("token", "test", test), ErrBadRequest))
+}
+
+func TestLDAPProviderComponent(t *testing.T) {
+	var component = LDAPProvider{
+		Name:           "corporate",
+		Vendor:         LDAPVendorActiveDirectory,
+		ConnectionURL:  "ldaps://dc.example.com",
+		BindDN:         "cn=keycloak,dc=example,dc=com",
+		UsersDN:        "ou=people,dc=example,dc=com",
+		SubtreeSearch:  true,
+		FullSyncPeriod: 24 * time.Hour,
+		Kerberos:       &KerberosSettings{KerberosRealm: "EXAMPLE.COM", ServerPrincipal: "HTTP/sso.example.com@EXAMPLE.COM"},
+	}.Component()
+
+	assert.Equal(t, "ldap", *component.ProviderID)
+	assert.Equal(t, UserStorageProviderType, *component.ProviderType)
+	assert.Nil(t, component.ParentID)
+	var config = *component.Config
+	assert.Equal(t, []string{"objectGUID"}, config["uuidLDAPAttribute"])
+	assert.Equal(t, []string{"person, organizationalPerson, user"}, config["userObjectClasses"])
+	assert.Equal(t, []string{"2"}, config["searchScope"])
+	assert.Equal(t, []string{"simple"}, config["authType"])
+	assert.Equal(t, []string{"86400"}, config["fullSyncPeriod"])
+	assert.Equal(t, []string{"-1"}, config["changedSyncPeriod"])
+	assert.Equal(t, []string{"true"}, config["allowKerberosAuthentication"])
+	assert.Equal(t, []string{"EXAMPLE.COM"}, config["kerberosRealm"])
+	assert.NotContains(t, config, "customUserSearchFilter")
+
+	config = *LDAPProvider{Name: "openldap", ConnectionURL: "ldap://ldap.example.com"}.Component().Config
+	assert.Equal(t, []string{LDAPVendorOther}, config["vendor"])
+	assert.Equal(t, []string{"entryUUID"}, config["uuidLDAPAttribute"])
+	assert.Equal(t, []string{"none"}, config["authType"])
+	assert.Equal(t, []string{LDAPEditModeReadOnly}, config["editMode"])
+}
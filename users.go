@@ -1,9 +1,8 @@
 package keycloak
 
 import (
-	"errors"
-
 	"gopkg.in/h2non/gentleman.v2/plugins/body"
+	"gopkg.in/h2non/gentleman.v2/plugins/query"
 	"gopkg.in/h2non/gentleman.v2/plugins/url"
 )
 
@@ -21,17 +20,14 @@ const (
 	shadowUser              = userIDPath + "/federated-identity/:provider"
 )
 
-// GetUsers returns a list of users, filtered according to the query parameters.
-// Parameters: email, first (paging offset, int), firstName, lastName, username,
-// max (maximum result size, default = 100),
-// search (string contained in username, firstname, lastname or email)
-func (c *Client) GetUsers(accessToken string, targetRealmName string, paramKV ...string) ([]UserRepresentation, error) {
+// GetUsers returns a list of users, filtered according to the query.
+func (c *Client) GetUsers(accessToken string, targetRealmName string, query UserQuery) ([]UserRepresentation, error) {
 	var resp []UserRepresentation
-	if len(paramKV)%2 != 0 {
-		return resp, errors.New(MsgErrInvalidParam + "." + EvenParams)
+	if err := query.Validate(); err != nil {
+		return resp, err
 	}
 
-	var plugins = append(createQueryPlugins(paramKV...), url.Path(userPath), url.Param("realm", targetRealmName))
+	var plugins = append(query.plugins(), url.Path(userPath), url.Param("realm", targetRealmName))
 	var err = c.get(accessToken, &resp, plugins...)
 	return resp, err
 }
@@ -83,12 +79,12 @@ func (c *Client) DeleteUser(accessToken string, realmName, userID string) error
 }
 
 // ExecuteActionsEmail sends an update account email to the user. An email contains a link the user can click to perform a set of required actions.
-func (c *Client) ExecuteActionsEmail(accessToken string, realmName string, userID string, actions []string, paramKV ...string) error {
-	if len(paramKV)%2 != 0 {
-		return errors.New(MsgErrInvalidParam + "." + EvenParams)
+func (c *Client) ExecuteActionsEmail(accessToken string, realmName string, userID string, actions []string, options ActionsEmailOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
 	}
 
-	var plugins = append(createQueryPlugins(paramKV...), url.Path(executeActionsEmailPath), url.Param("realm", realmName), url.Param("id", userID), body.JSON(actions))
+	var plugins = append(options.plugins(), url.Path(executeActionsEmailPath), url.Param("realm", realmName), url.Param("id", userID), body.JSON(actions))
 
 	return c.put(accessToken, plugins...)
 }
@@ -106,13 +102,12 @@ func (c *Client) SendSmsCode(accessToken string, realmName string, userID string
 }
 
 // SendReminderEmail sends a reminder email to a user
-func (c *Client) SendReminderEmail(accessToken string, realmName string, userID string, paramKV ...string) error {
-	if len(paramKV)%2 != 0 {
-		return errors.New(MsgErrInvalidParam + "." + EvenParams)
+func (c *Client) SendReminderEmail(accessToken string, realmName string, userID string, options ActionsEmailOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
 	}
-	var newParamKV = append(paramKV, "userid", userID)
 
-	var plugins = append(createQueryPlugins(newParamKV...), url.Path(sendReminderEmailPath), url.Param("realm", realmName))
+	var plugins = append(options.plugins(), query.Add("userid", userID), url.Path(sendReminderEmailPath), url.Param("realm", realmName))
 
 	_, err := c.post(accessToken, nil, plugins...)
 	return err